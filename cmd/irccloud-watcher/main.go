@@ -1,83 +1,272 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"irccloud-watcher/internal/api"
+	"irccloud-watcher/internal/auth"
+	"irccloud-watcher/internal/bridge"
+	"irccloud-watcher/internal/commands"
 	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/llm"
+	"irccloud-watcher/internal/logging"
+	"irccloud-watcher/internal/plugins"
 	"irccloud-watcher/internal/storage"
 	"irccloud-watcher/internal/summary"
 
 	"github.com/alecthomas/kong"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
 )
 
 type CLI struct {
 	Config          string `help:"Path to the configuration file" default:"config.yaml"`
 	GenerateSummary bool   `help:"Generate a summary and exit"`
+	SummaryFormat   string `help:"Override summary.format for --generate-summary (markdown, json, atom)"`
 	Debug           bool   `help:"Print raw received messages to stdout in addition to formatted messages"`
+
+	Search        bool   `help:"Search stored messages and exit"`
+	SearchChannel string `help:"Restrict --search to this channel"`
+	SearchSender  string `help:"Restrict --search to this sender"`
+	SearchKeyword string `help:"FTS5 keyword/phrase query for --search"`
+	SearchSince   string `help:"Restrict --search to messages newer than this duration ago, e.g. 24h"`
+	SearchLimit   int    `help:"Maximum --search results" default:"100"`
+
+	DefaultConfig bool `help:"Print the embedded default config.yaml to stdout and exit"`
 }
 
 func main() {
 	var cli CLI
 	kong.Parse(&cli)
 
+	if cli.DefaultConfig {
+		os.Stdout.Write(config.DefaultConfigYAML())
+		os.Exit(0)
+	}
+
 	cfg, err := config.LoadConfig(cli.Config)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	db, err := storage.NewDB(cfg.DatabasePath)
+	store, err := storage.Open(cfg.Storage.Driver, cfg.Storage.Source)
 	if err != nil {
-		log.Fatalf("Failed to open database: %v", err)
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	db, _ := store.(*storage.DB)
+	if db == nil && cfg.Storage.Driver != "sqlite" {
+		log.Printf("⚠️ storage.driver=%s: EID-cache persistence, enrichment, and commands all require the sqlite driver and are disabled", cfg.Storage.Driver)
+	}
+
+	var pluginManager *plugins.Manager
+	if len(cfg.Plugins.Scripts) > 0 {
+		pluginManager, err = plugins.NewManager(cfg.Plugins)
+		if err != nil {
+			log.Fatalf("Failed to load plugins: %v", err)
+		}
+		defer pluginManager.Close()
 	}
 
 	if cli.GenerateSummary {
+		if cli.SummaryFormat != "" {
+			cfg.SummaryFormat = cli.SummaryFormat
+		}
 		summaryGenerator := summary.NewGenerator(cfg)
-		if summaryErr := summaryGenerator.GenerateDailySummary(db, cfg.SummaryOutputPath); summaryErr != nil {
-			db.Close()
+		summaryGenerator.SetPluginManager(pluginManager)
+		if summaryErr := summaryGenerator.GenerateDailySummary(store, cfg.SummaryOutputPath); summaryErr != nil {
+			store.Close()
 			log.Fatalf("Failed to generate summary: %v", summaryErr)
 		}
-		db.Close()
+		store.Close()
+		os.Exit(0)
+	}
+
+	if cli.Search {
+		if searchErr := runSearch(store, cli); searchErr != nil {
+			store.Close()
+			log.Fatalf("Search failed: %v", searchErr)
+		}
+		store.Close()
 		os.Exit(0)
 	}
 
-	defer db.Close()
+	defer store.Close()
+
+	logger, err := logging.New(cfg.Logging)
+	if err != nil {
+		log.Fatalf("Failed to build logger: %v", err)
+	}
 
-	client := api.NewIRCCloudClient(db)
+	client := api.NewIRCCloudClient(store)
 	client.SetConnectionConfig(&cfg.Connection)
+	client.SetAuthConfig(&cfg.Auth)
 	client.SetDebugMode(cli.Debug)
+	client.SetFormatRenderer(cfg.Connection.FormatRenderer)
+	client.SetPluginManager(pluginManager)
+	client.SetLogger(logger)
+
+	switch cfg.Auth.Mode {
+	case "oauth2":
+		client.SetAuthenticator(auth.NewOAuth2Authenticator(auth.OAuth2Config{
+			TokenURL:      cfg.Auth.OAuth2.TokenURL,
+			ClientID:      cfg.Auth.OAuth2.ClientID,
+			ClientSecret:  cfg.Auth.OAuth2.ClientSecret,
+			Username:      cfg.Auth.OAuth2.Username,
+			Password:      cfg.Auth.OAuth2.Password,
+			GrantType:     cfg.Auth.OAuth2.GrantType,
+			Scope:         cfg.Auth.OAuth2.Scope,
+			APIHost:       cfg.Auth.OAuth2.APIHost,
+			WebSocketHost: cfg.Auth.OAuth2.WebSocketHost,
+			WebSocketPath: cfg.Auth.OAuth2.WebSocketPath,
+		}))
+	case "token":
+		client.SetAuthenticator(auth.NewTokenAuthenticator(auth.TokenConfig{
+			Session:       cfg.Auth.Token.Session,
+			APIHost:       cfg.Auth.Token.APIHost,
+			WebSocketHost: cfg.Auth.Token.WebSocketHost,
+			WebSocketPath: cfg.Auth.Token.WebSocketPath,
+		}))
+	case "jwt":
+		jwtAuthenticator, jwtErr := auth.NewJWTAuthenticator(auth.JWTConfig{
+			Token:         cfg.Auth.JWT.Token,
+			Key:           cfg.Auth.JWT.Key,
+			APIHost:       cfg.Auth.JWT.APIHost,
+			WebSocketHost: cfg.Auth.JWT.WebSocketHost,
+			WebSocketPath: cfg.Auth.JWT.WebSocketPath,
+		})
+		if jwtErr != nil {
+			log.Fatalf("Failed to set up JWT authenticator: %v", jwtErr)
+		}
+		client.SetAuthenticator(jwtAuthenticator)
+	}
+
+	var bridgeServer *bridge.Server
+	if cfg.Bridge.Enabled {
+		bridgeServer = bridge.NewServer(cfg.Bridge, client)
+		client.SetBridge(bridgeServer)
+		go func() {
+			if bridgeErr := bridgeServer.ListenAndServe(); bridgeErr != nil {
+				log.Printf("❌ IRC bridge stopped: %v", bridgeErr)
+			}
+		}()
+		defer bridgeServer.Close()
+	}
+
+	if cfg.Enrichment.Enabled {
+		provider, providerName, providerErr := resolveEnrichmentProvider(cfg)
+		if providerErr != nil {
+			log.Printf("⚠️ Enrichment disabled: %v", providerErr)
+		} else {
+			namedProvider, _ := cfg.LLM.ResolveProvider(providerName)
+			client.SetEnricher(llm.NewLLMEnricher(provider, namedProvider.Model), &cfg.Enrichment)
+		}
+	}
+
+	if cfg.Connection.MetricsAddr != "" {
+		if db != nil {
+			if regErr := db.RegisterMetrics(prometheus.DefaultRegisterer); regErr != nil {
+				log.Printf("⚠️ Failed to register db_open_connections metric: %v", regErr)
+			}
+		}
+		metricsServer := api.NewMetricsServer(cfg.Connection.MetricsAddr)
+		go func() {
+			if metricsErr := metricsServer.ListenAndServe(); metricsErr != nil {
+				log.Printf("❌ metrics server stopped: %v", metricsErr)
+			}
+		}()
+		log.Printf("📈 Metrics listening on %s/metrics", cfg.Connection.MetricsAddr)
+	}
+
 	if connectErr := client.Connect(cfg.Email, cfg.Password); connectErr != nil {
 		log.Fatalf("Failed to connect to IRCCloud: %v", connectErr)
 	}
 	defer client.Close()
 
+	if resyncErr := client.Resync(context.Background()); resyncErr != nil {
+		log.Printf("⚠️ Resync failed: %v", resyncErr)
+	}
+
+	// One Generator is shared across warmup and every scheduled summary run,
+	// so each provider's CircuitBreaker (see fallbackProviderFor) actually
+	// carries state across cron ticks instead of starting fresh - and fresh
+	// breakers closed - on every single run.
+	summaryGenerator := summary.NewGenerator(cfg)
+	summaryGenerator.SetPluginManager(pluginManager)
+	if warmErr := summaryGenerator.WarmLLMProviders(context.Background()); warmErr != nil {
+		log.Printf("⚠️ Failed to warm up LLM providers: %v", warmErr)
+	}
+
+	// Poll any open circuit breaker between scheduled summary runs, so a
+	// recovered provider doesn't stay degraded until the next cron tick
+	// happens to land after its cooldown - see StartHealthChecks' doc
+	// comment.
+	healthCheckCtx, stopHealthChecks := context.WithCancel(context.Background())
+	defer stopHealthChecks()
+	summaryGenerator.StartHealthChecks(healthCheckCtx, 5*time.Minute)
+
 	c := cron.New()
 	_, err = c.AddFunc(cfg.SummaryTime, func() {
-		summaryGenerator := summary.NewGenerator(cfg)
-		if cronErr := summaryGenerator.GenerateDailySummary(db, cfg.SummaryOutputPath); cronErr != nil {
+		if cronErr := summaryGenerator.GenerateDailySummary(store, cfg.SummaryOutputPath); cronErr != nil {
 			log.Printf("Failed to generate summary: %v", cronErr)
 		}
 	})
 	if err != nil {
 		log.Fatalf("Failed to schedule summary generation: %v", err)
 	}
+
+	for _, ch := range cfg.Channels {
+		if ch.SummaryTime == "" {
+			continue
+		}
+		channelCfg := cfg.EffectiveChannelConfig(ch.Name)
+		if _, chErr := c.AddFunc(channelCfg.SummaryTime, func() {
+			if cronErr := summaryGenerator.GenerateChannelSummary(store, channelCfg); cronErr != nil {
+				log.Printf("Failed to generate summary for channel %s: %v", channelCfg.Name, cronErr)
+			}
+		}); chErr != nil {
+			log.Fatalf("Failed to schedule summary generation for channel %s: %v", ch.Name, chErr)
+		}
+	}
+
+	if len(cfg.Commands) > 0 && db != nil {
+		commandRunner := commands.NewRunner(db, cfg.Commands)
+		for name, cmdCfg := range cfg.Commands {
+			if cmdCfg.Schedule == "" {
+				continue
+			}
+			name := name
+			if _, cmdErr := c.AddFunc(cmdCfg.Schedule, func() {
+				output, runErr := commandRunner.Run(name, "")
+				if runErr != nil {
+					log.Printf("❌ Command %q failed: %v", name, runErr)
+					return
+				}
+				log.Printf("📋 Command %q result:\n%s", name, output)
+			}); cmdErr != nil {
+				log.Fatalf("Failed to schedule command %q: %v", name, cmdErr)
+			}
+		}
+	}
+
 	c.Start()
 	defer c.Stop()
 
 	log.Println("🚀 IRCCloud watcher started successfully!")
 	if len(cfg.Channels) > 0 {
-		log.Printf("📺 Monitoring channels: %v", cfg.Channels)
+		log.Printf("📺 Monitoring channels: %v", cfg.ChannelNames())
 	} else {
 		log.Println("📺 Monitoring all channels")
 	}
 	if len(cfg.IgnoredChannels) > 0 {
 		log.Printf("🚫 Ignoring channels: %v", cfg.IgnoredChannels)
 	}
-	log.Printf("💾 Database: %s", cfg.DatabasePath)
+	log.Printf("💾 Storage: driver=%s source=%s", cfg.Storage.Driver, cfg.Storage.Source)
 	log.Printf("📊 Summary schedule: %s", cfg.SummaryTime)
 
 	// Set up graceful shutdown
@@ -85,7 +274,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	// Run the client in a goroutine
-	go client.Run(cfg.Channels, cfg.IgnoredChannels, &cfg.Connection)
+	go client.Run(cfg.ChannelNames(), cfg.IgnoredChannels, &cfg.Connection)
 
 	// Wait for shutdown signal
 	<-quit
@@ -93,3 +282,75 @@ func main() {
 
 	// Cleanup is handled by defer statements
 }
+
+// resolveEnrichmentProvider builds the llm.Provider the enrichment pipeline
+// should use: cfg.Enrichment.Provider if set, otherwise the same default
+// provider profile summaries use.
+func resolveEnrichmentProvider(cfg *config.Config) (llm.Provider, string, error) {
+	name := cfg.Enrichment.Provider
+	if name == "" {
+		name = cfg.LLM.Default
+	}
+
+	namedProvider, ok := cfg.LLM.ResolveProvider(name)
+	if !ok {
+		return nil, "", fmt.Errorf("no LLM provider profile named %q", name)
+	}
+
+	providerConfig := &llm.ProviderConfig{
+		BaseURL:            namedProvider.BaseURL,
+		DefaultModel:       namedProvider.Model,
+		DefaultMaxTokens:   namedProvider.MaxTokens,
+		DefaultTemperature: namedProvider.Temperature,
+		Organization:       namedProvider.Organization,
+	}
+
+	switch namedProvider.Kind {
+	case "ollama":
+		return llm.NewOllamaClient(providerConfig), name, nil
+	case "openai", "openai-compatible":
+		return llm.NewOpenAIClient(providerConfig, namedProvider.APIKey), name, nil
+	case "anthropic":
+		return llm.NewAnthropicClient(providerConfig, namedProvider.APIKey), name, nil
+	case "gemini":
+		return llm.NewGeminiClient(providerConfig, namedProvider.APIKey), name, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported LLM provider kind for enrichment: %s", namedProvider.Kind)
+	}
+}
+
+// runSearch builds a storage.SearchFilter from cli's --search flags, runs
+// it against db, and prints the results, one line per message, newest
+// first - a CHATHISTORY/SEARCH-style lookup over the archived logs without
+// having to write SQL.
+func runSearch(db storage.MessageStore, cli CLI) error {
+	filter := storage.SearchFilter{
+		Channel: cli.SearchChannel,
+		Sender:  cli.SearchSender,
+		Keyword: cli.SearchKeyword,
+		Limit:   cli.SearchLimit,
+	}
+
+	if cli.SearchSince != "" {
+		since, err := time.ParseDuration(cli.SearchSince)
+		if err != nil {
+			return fmt.Errorf("invalid --search-since %q: %w", cli.SearchSince, err)
+		}
+		filter.Since = time.Now().Add(-since)
+	}
+
+	messages, err := db.SearchMessages(filter)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	if len(messages) == 0 {
+		fmt.Println("No matching messages found")
+		return nil
+	}
+
+	for _, msg := range messages {
+		fmt.Printf("[%s] %s <%s> %s (eid=%d)\n", msg.Timestamp.Format(time.RFC3339), msg.Channel, msg.Sender, msg.Message, msg.EID)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Command migrate-logs copies every message from one storage.MessageStore
+// backend into another, e.g. moving an existing SQLite history onto the
+// "fs" flat-log driver or vice versa.
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"irccloud-watcher/internal/storage"
+)
+
+func main() {
+	fromDriver := flag.String("from-driver", "sqlite", "Source storage driver (sqlite, fs, memory)")
+	fromSource := flag.String("from-source", "", "Source storage.source (DSN/file path for sqlite, root dir for fs)")
+	toDriver := flag.String("to-driver", "fs", "Destination storage driver (sqlite, fs, memory)")
+	toSource := flag.String("to-source", "", "Destination storage.source (DSN/file path for sqlite, root dir for fs)")
+	since := flag.Duration("since", 365*24*time.Hour, "How far back to migrate, relative to now")
+	flag.Parse()
+
+	from, err := storage.Open(*fromDriver, *fromSource)
+	if err != nil {
+		log.Fatalf("failed to open source storage: %v", err)
+	}
+	defer from.Close()
+
+	to, err := storage.Open(*toDriver, *toSource)
+	if err != nil {
+		log.Fatalf("failed to open destination storage: %v", err)
+	}
+	defer to.Close()
+
+	end := time.Now()
+	start := end.Add(-*since)
+
+	messages, err := from.GetMessagesInTimeRange(start, end)
+	if err != nil {
+		log.Fatalf("failed to read source messages: %v", err)
+	}
+
+	migrated := 0
+	for i := range messages {
+		if err := to.InsertMessage(&messages[i]); err != nil {
+			log.Printf("failed to migrate message channel=%s eid=%d: %v", messages[i].Channel, messages[i].EID, err)
+			continue
+		}
+		migrated++
+	}
+
+	log.Printf("migrated %d/%d messages from %s (%s) to %s (%s)", migrated, len(messages), *fromDriver, *fromSource, *toDriver, *toSource)
+}
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"irccloud-watcher/internal/auth"
+	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/logging"
+	"irccloud-watcher/internal/storage"
+)
+
+// fakeAuthenticator hands IRCCloudClient credentials pointing at a fake
+// server instead of the real IRCCloud login flow, the same way
+// auth.TokenAuthenticator or auth.OAuth2Authenticator would for a real
+// deployment.
+type fakeAuthenticator struct {
+	apiHost       string
+	webSocketHost string
+	webSocketPath string
+}
+
+func (f *fakeAuthenticator) Authenticate(ctx context.Context) (*auth.Credentials, error) {
+	return &auth.Credentials{
+		APIHost:       f.apiHost,
+		WebSocketHost: f.webSocketHost,
+		WebSocketPath: f.webSocketPath,
+		HeaderName:    "Authorization",
+		HeaderValue:   "Bearer fake-session-token",
+	}, nil
+}
+
+// gzipJSON marshals v and gzip-compresses it, the shape a real IRCCloud
+// backlog response takes when the client sends Accept-Encoding: gzip.
+func gzipJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(body); err != nil {
+		t.Fatalf("failed to gzip fixture: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// newFakeWebSocketClient builds an IRCCloudClient pointed at server via
+// fakeAuthenticator and a skip-verify dialer/http client, so it can drive
+// Connect/Run against server's self-signed certificate the way it would
+// against the real IRCCloud TLS endpoint.
+func newFakeWebSocketClient(db storage.MessageStore, server *httptest.Server, wsPath string) *IRCCloudClient {
+	client := NewIRCCloudClient(db)
+	client.SetLogger(logging.Nop())
+	client.SetAuthenticator(&fakeAuthenticator{
+		apiHost:       server.URL,
+		webSocketHost: server.Listener.Addr().String(),
+		webSocketPath: wsPath,
+	})
+
+	insecureTLS := &tls.Config{InsecureSkipVerify: true} //nolint:gosec // test-only, talks to our own fake server
+	client.SetWebSocketDialer(&websocket.Dialer{TLSClientConfig: insecureTLS})
+	client.SetHTTPClient(&http.Client{Transport: &http.Transport{TLSClientConfig: insecureTLS}, Timeout: 10 * time.Second})
+
+	return client
+}
+
+// fastTestConnectionConfig returns a ConnectionConfig tuned so backoff and
+// reconnection happen fast enough for a test, and heartbeat/ping never fire
+// within the test's lifetime.
+func fastTestConnectionConfig() *config.ConnectionConfig {
+	return &config.ConnectionConfig{
+		HeartbeatInterval:       "1h",
+		PingInterval:            "1h",
+		PongTimeoutMultiplier:   2,
+		ConnectionTimeout:       "5s",
+		MaxRetryAttempts:        5,
+		InitialRetryDelay:       "5ms",
+		MaxRetryDelay:           "50ms",
+		BackoffMultiplier:       2.0,
+		BackoffStrategy:         "exponential",
+		WriteRateLimit:          1000,
+		WriteBurst:              1000,
+		ReconnectRateLimit:      1000,
+		ReconnectBurst:          1000,
+		ChannelIngestRateLimit:  1000,
+		ChannelIngestBurst:      1000,
+		CircuitBreakerThreshold: 100,
+		CircuitBreakerWindow:    "1m",
+		CircuitBreakerCooldown:  "1m",
+		BacklogWorkers:          2,
+	}
+}
+
+// waitForLastEID polls db.GetLastEID(channel) until it reaches at least
+// want, failing t if deadline passes first.
+func waitForLastEID(t *testing.T, db *storage.DB, channel string, want int64, deadline time.Duration) {
+	t.Helper()
+	until := time.Now().Add(deadline)
+	for time.Now().Before(until) {
+		if eid, err := db.GetLastEID(channel); err == nil && eid >= want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for channel %s to reach eid %d", channel, want)
+}
+
+// TestFakeServerDrivesReconnectDedupAndChannelFiltering runs IRCCloudClient's
+// real Connect/Run/runMessageLoop/processBacklog code paths against an
+// in-process fake IRCCloud server: a gzip-compressed oob_include backlog,
+// scripted live buffer_msg frames exercising EID dedup and ignored/
+// disallowed channel filtering, and a dropped connection the client must
+// reconnect to on its own.
+func TestFakeServerDrivesReconnectDedupAndChannelFiltering(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "fakeserver-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp database: %v", err)
+	}
+	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	db, err := storage.NewDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	backlog := []IRCMessage{
+		{Type: "buffer_msg", Chan: "#general", From: "alice", Msg: "backlog one", EID: 100},
+		{Type: "buffer_msg", Chan: "#general", From: "alice", Msg: "backlog two", EID: 101},
+		{Type: "buffer_msg", Chan: "#general", From: "alice", Msg: "backlog three", EID: 102},
+	}
+	mux.HandleFunc("/chat/backlog", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipJSON(t, backlog))
+	})
+
+	var connectAttempts int
+	mux.HandleFunc("/websocket/2", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		connectAttempts++
+		if connectAttempts == 1 {
+			_ = conn.WriteJSON(map[string]string{"type": "oob_include", "url": server.URL + "/chat/backlog"})
+			// Duplicate of the backlog's last EID - dedupMiddleware should drop it.
+			_ = conn.WriteJSON(IRCMessage{Type: "buffer_msg", Chan: "#general", From: "alice", Msg: "duplicate", EID: 102})
+			// Not in the allowed channel set - filterChannelsMiddleware should drop it.
+			_ = conn.WriteJSON(IRCMessage{Type: "buffer_msg", Chan: "#other", From: "bob", Msg: "disallowed", EID: 200})
+			// Explicitly ignored - filterChannelsMiddleware should drop it.
+			_ = conn.WriteJSON(IRCMessage{Type: "buffer_msg", Chan: "#ignored", From: "bob", Msg: "ignored", EID: 201})
+			_ = conn.WriteJSON(IRCMessage{Type: "buffer_msg", Chan: "#general", From: "alice", Msg: "live one", EID: 103})
+			// Drop the connection; Run should reconnect on its own.
+			return
+		}
+
+		_ = conn.WriteJSON(IRCMessage{Type: "buffer_msg", Chan: "#general", From: "alice", Msg: "after reconnect", EID: 104})
+		// Keep the connection open until the test tears it down.
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	server = httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	client := newFakeWebSocketClient(db, server, "/websocket/2")
+	connConfig := fastTestConnectionConfig()
+	client.SetConnectionConfig(connConfig)
+
+	if err := client.Connect("fake@example.com", "password"); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	go client.Run([]string{"#general"}, []string{"#ignored"}, connConfig)
+
+	waitForLastEID(t, db, "#general", 104, 5*time.Second)
+
+	if eid, _ := db.GetLastEID("#other"); eid != 0 {
+		t.Errorf("expected #other to have no persisted messages, got eid=%d", eid)
+	}
+	if eid, _ := db.GetLastEID("#ignored"); eid != 0 {
+		t.Errorf("expected #ignored to have no persisted messages, got eid=%d", eid)
+	}
+
+	messages, err := db.GetMessagesInTimeRange(time.Unix(0, 0), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetMessagesInTimeRange failed: %v", err)
+	}
+	var generalCount int
+	for _, m := range messages {
+		if m.Channel == "#general" {
+			generalCount++
+		}
+	}
+	// backlog's 3 + "live one" + "after reconnect" = 5; "duplicate" (eid
+	// 102) must not have been persisted a second time.
+	if generalCount != 5 {
+		t.Errorf("expected 5 persisted #general messages (dedup should drop the repeated eid), got %d", generalCount)
+	}
+
+	if connectAttempts < 2 {
+		t.Fatalf("expected the client to reconnect after the dropped connection, got %d connect attempts", connectAttempts)
+	}
+}
+
+// TestStreamBacklogMessagesSurfacesGzipDecodeFailure drives
+// streamBacklogMessages against a response that claims to be gzip-encoded
+// but isn't, confirming processBacklog surfaces the decode error instead of
+// panicking or silently dropping the backlog.
+func TestStreamBacklogMessagesSurfacesGzipDecodeFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not actually gzip"))
+	}))
+	defer server.Close()
+
+	client := NewIRCCloudClient(nil)
+	client.SetLogger(logging.Nop())
+	client.apiHost = server.URL
+	client.authHeaderName = "Authorization"
+	client.authHeaderValue = "session test-session"
+	client.SetConnectionConfig(fastTestConnectionConfig())
+
+	_, err := client.streamBacklogMessages("/chat/backlog")
+	if err == nil {
+		t.Fatal("expected an error decoding a malformed gzip backlog response, got nil")
+	}
+}
@@ -3,18 +3,66 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/httpretry"
 )
 
+// refreshAuth obtains fresh credentials and caches them on c: from the
+// configured authenticator if one was set via SetAuthenticator, or from the
+// built-in email/password form-token login otherwise.
+func (c *IRCCloudClient) refreshAuth() error {
+	if c.authenticator != nil {
+		creds, err := c.authenticator.Authenticate(c.ctx)
+		if err != nil {
+			return err
+		}
+		c.authResp = &AuthResponse{
+			Success:       true,
+			APIHost:       creds.APIHost,
+			WebSocketHost: creds.WebSocketHost,
+			WebSocketPath: creds.WebSocketPath,
+		}
+		c.apiHost = creds.APIHost
+		c.authHeaderName = creds.HeaderName
+		c.authHeaderValue = creds.HeaderValue
+		return nil
+	}
+
+	authResp, err := c.authenticate(c.email, c.password)
+	if err != nil {
+		return err
+	}
+	c.authResp = authResp
+	c.session = authResp.Session
+	c.apiHost = authResp.APIHost
+	c.authHeaderName = "Cookie"
+	c.authHeaderValue = "session=" + authResp.Session
+	return nil
+}
+
 // authenticate authenticates with the IRCCloud API and returns the full authentication response.
-func (c *IRCCloudClient) authenticate(email, password string) (*AuthResponse, error) {
-	log.Printf("🔐 Starting authentication for email: %s", email)
+func (c *IRCCloudClient) authenticate(email, password string) (authResp *AuthResponse, err error) {
+	defer func() {
+		if err != nil {
+			metricAuthAttemptsTotal.WithLabelValues("failure").Inc()
+		} else {
+			metricAuthAttemptsTotal.WithLabelValues("success").Inc()
+		}
+	}()
+
+	c.logger.Infof("starting authentication email=%s", email)
 
 	jar, err := cookiejar.New(nil)
 	if err != nil {
@@ -23,28 +71,36 @@ func (c *IRCCloudClient) authenticate(email, password string) (*AuthResponse, er
 	client := &http.Client{Timeout: 10 * time.Second, Jar: jar}
 
 	// Step 1: Get an auth-formtoken
-	log.Println("📡 Step 1: Requesting auth-formtoken...")
+	c.logger.Debugf("requesting auth-formtoken")
 	tokenURL := "https://www.irccloud.com/chat/auth-formtoken"
-	req, err := http.NewRequest("POST", tokenURL, http.NoBody)
-	if err != nil {
-		return nil, fmt.Errorf("could not create token request: %w", err)
+	reqHeader := http.Header{
+		"User-Agent":     []string{"irccloud-watcher/0.1.0"},
+		"Accept":         []string{"application/json"},
+		"Content-Length": []string{"0"},
 	}
-	req.Header.Set("User-Agent", "irccloud-watcher/0.1.0")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Length", "0")
-
-	debugLogRequest("POST", tokenURL, req.Header)
-	resp, err := client.Do(req)
+	resp, err := httpretry.Do(c.ctx, client, c.formTokenRetryConfig(), func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", tokenURL, http.NoBody)
+		if err != nil {
+			return nil, fmt.Errorf("could not create token request: %w", err)
+		}
+		req.Header = reqHeader.Clone()
+		debugLogRequest(c.logger, "POST", tokenURL, req.Header)
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("could not perform token request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	log.Printf("📡 Token request response status: %s", resp.Status)
+	c.logger.Debugf("token request response status=%s", resp.Status)
 	if resp.StatusCode != http.StatusOK {
 		errorBody, readErr := io.ReadAll(resp.Body)
 		if readErr == nil {
-			log.Printf("❌ Token request error response body: %s", string(errorBody))
+			c.logger.Errorf("token request error response body=%s", string(errorBody))
+		}
+		if c.authConfig != nil {
+			DumpAuthTranscript(c.logger, c.authConfig.DebugDumpPath, "auth-formtoken", "POST", tokenURL,
+				reqHeader, nil, resp.Status, resp.Header, errorBody)
 		}
 		return nil, fmt.Errorf("token request failed with status: %s", resp.Status)
 	}
@@ -54,72 +110,183 @@ func (c *IRCCloudClient) authenticate(email, password string) (*AuthResponse, er
 		return nil, fmt.Errorf("could not read token response body: %w", err)
 	}
 
-	debugLogResponse(resp, body)
+	debugLogResponse(c.logger, resp, body)
 
 	var tokenResp TokenResponse
 	if parseErr := json.Unmarshal(body, &tokenResp); parseErr != nil {
-		log.Printf("❌ Failed to parse token response: %s", string(body))
+		c.logger.Errorf("failed to parse token response body=%s", string(body))
 		return nil, fmt.Errorf("could not parse token response: %w", parseErr)
 	}
 
-	log.Printf("✅ Token received successfully: %t, Token length: %d", tokenResp.Success, len(tokenResp.Token))
+	c.logger.Debugf("token received success=%t token_length=%d", tokenResp.Success, len(tokenResp.Token))
 	if !tokenResp.Success {
 		return nil, fmt.Errorf("token request unsuccessful")
 	}
 
 	// Step 2: Log in with email, password, and token
-	log.Println("🔑 Step 2: Logging in with credentials...")
+	c.logger.Debugf("logging in with credentials")
 	loginURL := "https://www.irccloud.com/chat/login"
 	data := url.Values{}
 	data.Set("email", email)
 	data.Set("password", password)
 	data.Set("token", tokenResp.Token)
 
-	req, err = http.NewRequest("POST", loginURL, bytes.NewBufferString(data.Encode()))
+	authResp, err = c.loginWithRetry(client, loginURL, data, tokenResp.Token)
 	if err != nil {
-		return nil, fmt.Errorf("could not create login request: %w", err)
+		c.logger.Errorf("authentication failed error=%v", err)
+		return nil, err
 	}
 
-	req.Header.Set("X-Auth-Formtoken", tokenResp.Token)
+	c.logger.Infof("authentication completed successfully success=%t session_length=%d websocket_host=%s websocket_path=%s",
+		authResp.Success, len(authResp.Session), authResp.WebSocketHost, authResp.WebSocketPath)
+	return authResp, nil
+}
+
+// loginAttempt performs a single login HTTP call and parses the response
+// via parseAPIResponse, returning the raw *http.Response alongside it so
+// loginWithRetry can inspect status/headers (e.g. Retry-After) regardless of
+// whether parsing succeeded.
+func (c *IRCCloudClient) loginAttempt(client *http.Client, loginURL string, data url.Values, formtoken string) (*AuthResponse, *http.Response, error) {
+	req, err := http.NewRequest("POST", loginURL, bytes.NewBufferString(data.Encode()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create login request: %w", err)
+	}
+	req.Header.Set("X-Auth-Formtoken", formtoken)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("User-Agent", "irccloud-watcher/0.1.0")
 	req.Header.Set("Accept", "application/json")
 
-	debugLogRequest("POST", loginURL, req.Header)
-	resp, err = client.Do(req)
+	debugLogRequest(c.logger, "POST", loginURL, req.Header)
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("could not perform login request: %w", err)
+		return nil, nil, fmt.Errorf("could not perform login request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	log.Printf("🔑 Login response status: %s", resp.Status)
-	if resp.StatusCode != http.StatusOK {
-		errorBody, readErr := io.ReadAll(resp.Body)
-		if readErr == nil {
-			log.Printf("❌ Login request error response body: %s", string(errorBody))
-		}
-		return nil, fmt.Errorf("login failed with status: %s", resp.Status)
+	c.logger.Debugf("login response status=%s", resp.Status)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp, fmt.Errorf("could not read login response body: %w", err)
 	}
+	debugLogResponse(c.logger, resp, body)
 
-	body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("could not read login response body: %w", err)
+	authResp, err := parseAPIResponse(body, resp.StatusCode)
+	if err != nil && c.authConfig != nil {
+		DumpAuthTranscript(c.logger, c.authConfig.DebugDumpPath, "login", "POST", loginURL,
+			req.Header, []byte(data.Encode()), resp.Status, resp.Header, body)
+	}
+	return authResp, resp, err
+}
+
+// formTokenRetryConfig builds an httpretry.Config from AuthConfig's
+// RetryInitialInterval/RetryMaxInterval/RetryMaxElapsedTime, the same knobs
+// loginWithRetry honors, so the formtoken step backs off consistently with
+// the login step that follows it.
+func (c *IRCCloudClient) formTokenRetryConfig() httpretry.Config {
+	cfg := httpretry.DefaultConfig()
+	if c.authConfig == nil {
+		return cfg
 	}
+	if d, err := time.ParseDuration(c.authConfig.RetryInitialInterval); err == nil {
+		cfg.InitialInterval = d
+	}
+	if d, err := time.ParseDuration(c.authConfig.RetryMaxInterval); err == nil {
+		cfg.MaxInterval = d
+	}
+	if d, err := time.ParseDuration(c.authConfig.RetryMaxElapsedTime); err == nil {
+		cfg.MaxElapsedTime = d
+	}
+	return cfg
+}
 
-	debugLogResponse(resp, body)
+// loginWithRetry retries loginAttempt with jittered exponential backoff
+// (bounded by AuthConfig's RetryInitialInterval/RetryMaxInterval/
+// RetryMaxElapsedTime) on network errors and responses classified as
+// ErrRateLimited or ErrServerError, honoring a Retry-After header on those
+// responses instead of the computed backoff delay when one is present. It
+// gives up immediately on ErrInvalidCredentials, ErrTOTPRequired, or any
+// other unclassified AuthError, since retrying can't fix those.
+func (c *IRCCloudClient) loginWithRetry(client *http.Client, loginURL string, data url.Values, formtoken string) (*AuthResponse, error) {
+	if c.authConfig == nil {
+		c.authConfig = &config.AuthConfig{}
+	}
 
-	authResp, err := parseAPIResponse(body, resp.StatusCode)
-	if err != nil {
-		log.Printf("❌ Authentication failed: %v", err)
-		return nil, err
+	b := backoff.NewExponentialBackOff()
+	if d, parseErr := time.ParseDuration(c.authConfig.RetryInitialInterval); parseErr == nil {
+		b.InitialInterval = d
+	}
+	if d, parseErr := time.ParseDuration(c.authConfig.RetryMaxInterval); parseErr == nil {
+		b.MaxInterval = d
+	}
+	if d, parseErr := time.ParseDuration(c.authConfig.RetryMaxElapsedTime); parseErr == nil {
+		b.MaxElapsedTime = d
+	}
+
+	var authResp *AuthResponse
+	attempt := 0
+	operation := func() error {
+		attempt++
+		resp, httpResp, err := c.loginAttempt(client, loginURL, data, formtoken)
+		if err == nil {
+			authResp = resp
+			return nil
+		}
+
+		var authErr *AuthError
+		if errors.As(err, &authErr) {
+			if !errors.Is(authErr, ErrRateLimited) && !errors.Is(authErr, ErrServerError) {
+				return backoff.Permanent(err)
+			}
+		} else if httpResp != nil {
+			// A response arrived but couldn't be read or parsed; only a
+			// failed request with no response at all is a network error.
+			return backoff.Permanent(err)
+		}
+
+		if httpResp != nil {
+			if wait, ok := retryAfterDuration(httpResp.Header.Get("Retry-After")); ok {
+				c.logger.Warnf("login attempt=%d rate limited/unavailable, honoring Retry-After=%v error=%v", attempt, wait, err)
+				select {
+				case <-time.After(wait):
+				case <-c.ctx.Done():
+					return backoff.Permanent(fmt.Errorf("login cancelled while honoring Retry-After: %w", c.ctx.Err()))
+				}
+				return err
+			}
+		}
+
+		c.logger.Warnf("login attempt=%d failed, retrying error=%v", attempt, err)
+		return err
 	}
 
-	log.Printf("✅ Login success: %t, Session length: %d", authResp.Success, len(authResp.Session))
-	log.Printf("🎉 Authentication completed successfully!")
-	log.Printf("🌐 WebSocket details - Host: %s, Path: %s", authResp.WebSocketHost, authResp.WebSocketPath)
+	if err := backoff.Retry(operation, backoff.WithContext(b, c.ctx)); err != nil {
+		return nil, err
+	}
 	return authResp, nil
 }
 
+// retryAfterDuration parses a Retry-After header, which per RFC 7231 is
+// either a number of delay-seconds or an HTTP-date, returning the duration
+// to wait and whether parsing succeeded.
+func retryAfterDuration(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
 // buildWebSocketURL constructs the WebSocket URL from authentication response
 func (c *IRCCloudClient) buildWebSocketURL(authResp *AuthResponse) string {
 	if authResp.WebSocketHost != "" && authResp.WebSocketPath != "" {
@@ -127,7 +294,7 @@ func (c *IRCCloudClient) buildWebSocketURL(authResp *AuthResponse) string {
 		// Add query parameters
 		u, err := url.Parse(baseURL)
 		if err != nil {
-			log.Printf("⚠️ Error parsing WebSocket URL, using fallback: %v", err)
+			c.logger.Warnf("error parsing websocket url, using fallback error=%v", err)
 			return "wss://www.irccloud.com/?since_id=0&stream_id=0"
 		}
 		q := u.Query()
@@ -138,11 +305,14 @@ func (c *IRCCloudClient) buildWebSocketURL(authResp *AuthResponse) string {
 	}
 
 	// Fallback to original URL
-	log.Println("⚠️ Using fallback WebSocket URL")
+	c.logger.Warnf("using fallback websocket url")
 	return "wss://www.irccloud.com/?since_id=0&stream_id=0"
 }
 
-// parseAPIResponse parses API responses and handles errors properly
+// parseAPIResponse parses API responses and handles errors properly. A
+// response is treated as an error whenever `success` is false, even if the
+// HTTP status is the unorthodox-but-observed-in-the-wild 200, since
+// IRCCloud (and some OAuth2-fronting proxies) report failures that way.
 func parseAPIResponse(body []byte, statusCode int) (*AuthResponse, error) {
 	var authResp AuthResponse
 	if err := json.Unmarshal(body, &authResp); err != nil {
@@ -157,14 +327,53 @@ func parseAPIResponse(body []byte, statusCode int) (*AuthResponse, error) {
 				Type:    "api_error",
 				Message: "Authentication failed",
 				Status:  statusCode,
+				Kind:    classifyAuthErrorKind(statusCode, "", ""),
 			}
 		}
+		message := errResp.Message
+		if message == "" {
+			message = errResp.ErrorDescription
+		}
+		if message == "" {
+			message = errResp.Error
+		}
 		return nil, &AuthError{
 			Type:    "api_error",
-			Message: errResp.Message,
+			Message: message,
 			Status:  statusCode,
+			Kind:    classifyAuthErrorKind(statusCode, errResp.Error, message),
 		}
 	}
 
 	return &authResp, nil
 }
+
+// classifyAuthErrorKind maps an RFC 6749 `error` code, HTTP status, and (for
+// IRCCloud's own login, which has no `error` code for this case) the
+// message text to one of the sentinel error kinds, so callers can
+// errors.Is against a stable kind instead of string-matching Message text
+// that varies between IRCCloud and OAuth2 gateways.
+func classifyAuthErrorKind(statusCode int, errorCode, message string) error {
+	switch errorCode {
+	case "invalid_grant", "invalid_client", "unauthorized_client":
+		return ErrInvalidCredentials
+	case "slow_down":
+		return ErrRateLimited
+	}
+
+	if strings.Contains(strings.ToLower(message), "totp") {
+		return ErrTOTPRequired
+	}
+
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrInvalidCredentials
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	if statusCode >= 500 {
+		return ErrServerError
+	}
+
+	return nil
+}
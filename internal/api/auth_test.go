@@ -0,0 +1,102 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseAPIResponseIRCCloudInvalidCredentials(t *testing.T) {
+	body := []byte(`{"success":false,"message":"Invalid login or password"}`)
+
+	_, err := parseAPIResponse(body, http.StatusUnauthorized)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestParseAPIResponseOAuth2ErrorShape(t *testing.T) {
+	body := []byte(`{"success":false,"error":"invalid_grant","error_description":"The provided grant is invalid"}`)
+
+	_, err := parseAPIResponse(body, http.StatusBadRequest)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+	authErr, ok := err.(*AuthError)
+	if !ok {
+		t.Fatalf("expected *AuthError, got %T", err)
+	}
+	if authErr.Message != "The provided grant is invalid" {
+		t.Errorf("expected message from error_description, got %q", authErr.Message)
+	}
+}
+
+func TestParseAPIResponseSuccessFalseWith200Status(t *testing.T) {
+	body := []byte(`{"success":false,"message":"rate limited, try again later"}`)
+
+	_, err := parseAPIResponse(body, http.StatusOK)
+	if err == nil {
+		t.Fatal("expected an error even though the HTTP status is 200")
+	}
+}
+
+func TestParseAPIResponseRateLimited(t *testing.T) {
+	body := []byte(`{"success":false,"message":"too many attempts"}`)
+
+	_, err := parseAPIResponse(body, http.StatusTooManyRequests)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestParseAPIResponseTOTPRequired(t *testing.T) {
+	body := []byte(`{"success":false,"message":"totp required"}`)
+
+	_, err := parseAPIResponse(body, http.StatusOK)
+	if !errors.Is(err, ErrTOTPRequired) {
+		t.Errorf("expected ErrTOTPRequired, got %v", err)
+	}
+}
+
+func TestParseAPIResponseServerError(t *testing.T) {
+	body := []byte(`{"success":false,"message":"upstream unavailable"}`)
+
+	_, err := parseAPIResponse(body, http.StatusBadGateway)
+	if !errors.Is(err, ErrServerError) {
+		t.Errorf("expected ErrServerError, got %v", err)
+	}
+}
+
+func TestRetryAfterDurationSeconds(t *testing.T) {
+	d, ok := retryAfterDuration("5")
+	if !ok {
+		t.Fatal("expected a parsed duration")
+	}
+	if d != 5*time.Second {
+		t.Errorf("expected 5s, got %v", d)
+	}
+}
+
+func TestRetryAfterDurationEmpty(t *testing.T) {
+	if _, ok := retryAfterDuration(""); ok {
+		t.Error("expected no duration for an empty header")
+	}
+}
+
+func TestRetryAfterDurationHTTPDate(t *testing.T) {
+	future := "Mon, 01 Jan 2035 00:00:00 GMT"
+	d, ok := retryAfterDuration(future)
+	if !ok {
+		t.Fatal("expected a parsed duration for an HTTP-date")
+	}
+	if d <= 0 {
+		t.Errorf("expected a positive duration, got %v", d)
+	}
+}
@@ -4,122 +4,189 @@ import (
 	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
-	"log"
 	"net/http"
-	"os"
 	"strings"
+	"sync"
 	"time"
+)
+
+const (
+	// defaultBacklogWorkers is used when ConnectionConfig.BacklogWorkers is
+	// unset or non-positive.
+	defaultBacklogWorkers = 4
 
-	"irccloud-watcher/internal/storage"
-	"irccloud-watcher/internal/utils"
+	// backlogProgressInterval is how often processBacklog logs progress
+	// while streaming a large history, instead of only at start and end.
+	backlogProgressInterval = 500
 )
 
+// processBacklog fetches and replays the backlog IRCCloud points to via an
+// oob_include message's URL, streaming it through a bounded worker pool
+// rather than loading the whole history into memory at once.
 func (c *IRCCloudClient) processBacklog(backlogURL string) error {
-	// The backlog URL is just a path, we need to prepend the correct API host
-	if !strings.HasPrefix(backlogURL, "http") {
-		if c.apiHost != "" {
-			// APIHost already includes the protocol (https://)
-			backlogURL = c.apiHost + backlogURL
-		} else {
-			// Fallback to www.irccloud.com if no API host is available
-			backlogURL = "https://www.irccloud.com" + backlogURL
-		}
+	_, err := c.streamBacklogMessages(backlogURL)
+	return err
+}
+
+// backlogWorkerCount returns the configured BacklogWorkers, or
+// defaultBacklogWorkers if unset.
+func (c *IRCCloudClient) backlogWorkerCount() int {
+	if c.connConfig == nil || c.connConfig.BacklogWorkers <= 0 {
+		return defaultBacklogWorkers
+	}
+	return c.connConfig.BacklogWorkers
+}
+
+// backlogShard hashes channel to one of n worker shards, so every message
+// for a given channel is always handled by the same worker and therefore
+// replayed in the EID order it was decoded in, while unrelated channels can
+// be processed concurrently across the other shards.
+func backlogShard(channel string, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(channel))
+	return int(h.Sum32()) % n
+}
+
+// resolveBacklogURL prepends the API host to a bare backlog path, the same
+// way an oob_include URL and a Resync per-channel history request both need.
+func (c *IRCCloudClient) resolveBacklogURL(backlogURL string) string {
+	if strings.HasPrefix(backlogURL, "http") {
+		return backlogURL
+	}
+	if c.apiHost != "" {
+		// APIHost already includes the protocol (https://)
+		return c.apiHost + backlogURL
 	}
+	// Fallback to www.irccloud.com if no API host is available
+	return "https://www.irccloud.com" + backlogURL
+}
 
-	log.Printf("🔍 Requesting backlog from URL: %s", backlogURL)
+// openBacklogReader performs a GET against backlogURL - an oob_include URL,
+// or a Resync per-channel history request - and returns its (transparently
+// gunzipped) body for streaming. The caller must call the returned closer
+// once done reading.
+func (c *IRCCloudClient) openBacklogReader(backlogURL string) (io.Reader, io.Closer, error) {
+	backlogURL = c.resolveBacklogURL(backlogURL)
+	c.logger.Infof("requesting backlog url=%s", backlogURL)
 
 	req, err := http.NewRequest("GET", backlogURL, http.NoBody)
 	if err != nil {
-		return fmt.Errorf("could not create backlog request: %w", err)
+		return nil, nil, fmt.Errorf("could not create backlog request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "irccloud-watcher/0.1.0")
-	req.Header.Set("Cookie", "session="+c.session)
+	req.Header.Set(c.authHeaderName, c.authHeaderValue)
 	req.Header.Set("Accept-Encoding", "gzip")
 
-	client := &http.Client{Timeout: 60 * time.Second}
+	client := c.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("could not perform backlog request: %w", err)
+		return nil, nil, fmt.Errorf("could not perform backlog request: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("backlog request failed with status: %s", resp.Status)
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("backlog request failed with status: %s", resp.Status)
 	}
 
-	var reader io.Reader = resp.Body
-
-	// Check if the response is gzipped
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		gzipReader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			return fmt.Errorf("could not create gzip reader: %w", err)
-		}
-		defer gzipReader.Close()
-		reader = gzipReader
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return resp.Body, resp.Body, nil
 	}
 
-	var backlogMessages []IRCMessage
-	decoder := json.NewDecoder(reader)
-	if err := decoder.Decode(&backlogMessages); err != nil {
-		return fmt.Errorf("could not decode backlog messages: %w", err)
+	gzipReader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("could not create gzip reader: %w", err)
 	}
+	return gzipReader, multiCloser{gzipReader, resp.Body}, nil
+}
 
-	log.Printf("Processing %d backlog messages", len(backlogMessages))
+// multiCloser closes every Closer in order, so streamBacklogMessages can
+// defer a single Close() that tears down both the gzip reader and the
+// underlying response body.
+type multiCloser []io.Closer
 
-	for _, ircMsg := range backlogMessages {
-		// Skip message if ignored or not in allowed channels
-		if ircMsg.Type != "buffer_msg" || c.ignoredChannelSet[ircMsg.Chan] || (len(c.channels) > 0 && !c.channelSet[ircMsg.Chan]) {
-			continue
+func (m multiCloser) Close() error {
+	var firstErr error
+	for _, c := range m {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
 		}
+	}
+	return firstErr
+}
 
-		// Check if we've seen this EID before (skip if duplicate)
-		if c.isEIDSeen(ircMsg.EID) {
-			if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-				log.Printf("🔄 Duplicate backlog message filtered: EID=%d, Channel=%s", ircMsg.EID, ircMsg.Chan)
-			}
-			continue
-		}
+// backlogJob is one message a streamBacklogMessages worker replays.
+type backlogJob struct {
+	msg *IRCMessage
+}
 
-		cleanedMsg := utils.CleanIRCMessage(ircMsg.Msg)
-		// Handle timestamp conversion - IRCCloud uses microseconds since Unix epoch
-		// Live messages often have timestamp 0, so we use current time as fallback
-		var msgTime time.Time
-		if ircMsg.Time > 0 {
-			// Convert from microseconds to seconds and nanoseconds
-			seconds := ircMsg.Time / 1000000
-			microseconds := ircMsg.Time % 1000000
-			nanoseconds := microseconds * 1000
-			msgTime = time.Unix(seconds, nanoseconds)
-		} else {
-			// Use current time for live messages (timestamp 0 is normal)
-			msgTime = time.Now()
-		}
+// streamBacklogMessages fetches backlogURL and replays its messages one at
+// a time via json.Decoder.Token/More, rather than decoding the whole JSON
+// array into memory up front - a multi-month history across dozens of
+// channels can otherwise be large enough to spike the heap. Decoded
+// messages are sharded by channel across a bounded worker pool
+// (backlogWorkerCount) so replay throughput scales with channel count
+// while handleMessageSync still sees each channel's own messages in the
+// EID order they were decoded in. Returns the number of messages replayed.
+func (c *IRCCloudClient) streamBacklogMessages(backlogURL string) (int64, error) {
+	reader, closer, err := c.openBacklogReader(backlogURL)
+	if err != nil {
+		return 0, err
+	}
+	defer closer.Close()
 
-		if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-			log.Printf("🔍 Processing backlog message: Channel=%s, From=%s, EID=%d, Time=%d, Converted=%s", ircMsg.Chan, ircMsg.From, ircMsg.EID, ircMsg.Time, msgTime.Format(time.RFC3339))
-		}
+	decoder := json.NewDecoder(reader)
+	if _, err := decoder.Token(); err != nil {
+		return 0, fmt.Errorf("could not decode backlog messages: %w", err)
+	}
 
-		log.Printf("%s <%s> %s", ircMsg.Chan, ircMsg.From, cleanedMsg)
+	workerCount := c.backlogWorkerCount()
+	shards := make([]chan backlogJob, workerCount)
+	var wg sync.WaitGroup
+	for i := range shards {
+		shards[i] = make(chan backlogJob, 64)
+		wg.Add(1)
+		go func(jobs <-chan backlogJob) {
+			defer wg.Done()
+			for job := range jobs {
+				if err := c.handleMessageSync(c.ctx, job.msg); err != nil {
+					c.logger.Warnf("error processing backlog message error=%v", err)
+				}
+			}
+		}(shards[i])
+	}
 
-		dbMsg := &storage.Message{
-			Channel:   ircMsg.Chan,
-			Timestamp: msgTime,
-			Sender:    ircMsg.From,
-			Message:   cleanedMsg,
-			Date:      msgTime.Format("2006-01-02"),
-			EID:       ircMsg.EID,
+	var count int64
+	for decoder.More() {
+		var msg IRCMessage
+		if err := decoder.Decode(&msg); err != nil {
+			for _, shard := range shards {
+				close(shard)
+			}
+			wg.Wait()
+			return count, fmt.Errorf("could not decode backlog messages: %w", err)
 		}
 
-		if err := c.db.InsertMessage(dbMsg); err != nil {
-			log.Printf("❌ Error inserting backlog message into DB: %v", err)
-		} else if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-			log.Printf("✅ Backlog message stored successfully: EID=%d", ircMsg.EID)
+		shards[backlogShard(msg.Chan, workerCount)] <- backlogJob{msg: &msg}
+
+		count++
+		if count%backlogProgressInterval == 0 {
+			c.logger.Infof("processing backlog messages progress=%d", count)
 		}
 	}
 
-	log.Println("Finished processing backlog")
-	return nil
+	for _, shard := range shards {
+		close(shard)
+	}
+	wg.Wait()
+
+	metricBacklogMessagesProcessed.Add(float64(count))
+	c.logger.Infof("finished processing backlog count=%d", count)
+	return count, nil
 }
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/storage"
+)
+
+func TestStreamBacklogMessagesPersistsEachMessageInOrderPerChannel(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "backlog-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp database: %v", err)
+	}
+	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	db, err := storage.NewDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	const messagesPerChannel = 20
+	var backlog []IRCMessage
+	for i := 0; i < messagesPerChannel; i++ {
+		backlog = append(backlog,
+			IRCMessage{Type: "buffer_msg", Chan: "#alpha", From: "user1", Msg: "alpha", Time: now.Unix(), EID: int64(1000 + i)},
+			IRCMessage{Type: "buffer_msg", Chan: "#beta", From: "user2", Msg: "beta", Time: now.Unix(), EID: int64(2000 + i)},
+		)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(backlog)
+	}))
+	defer server.Close()
+
+	client := NewIRCCloudClient(db)
+	client.apiHost = server.URL
+	client.authHeaderName = "Authorization"
+	client.authHeaderValue = "session test-session"
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		ChannelIngestRateLimit: 1000,
+		ChannelIngestBurst:     1000,
+		BacklogWorkers:         3,
+	})
+
+	count, err := client.streamBacklogMessages("/chat/backlog")
+	if err != nil {
+		t.Fatalf("streamBacklogMessages returned error: %v", err)
+	}
+	if count != int64(len(backlog)) {
+		t.Errorf("expected count %d, got %d", len(backlog), count)
+	}
+
+	if eid, err := db.GetLastEID("#alpha"); err != nil || eid != int64(1000+messagesPerChannel-1) {
+		t.Errorf("expected #alpha to land at its last eid, got eid=%d err=%v", eid, err)
+	}
+	if eid, err := db.GetLastEID("#beta"); err != nil || eid != int64(2000+messagesPerChannel-1) {
+		t.Errorf("expected #beta to land at its last eid, got eid=%d err=%v", eid, err)
+	}
+}
+
+func TestBacklogWorkerCountDefaultsWhenUnconfigured(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+
+	if got := client.backlogWorkerCount(); got != defaultBacklogWorkers {
+		t.Errorf("expected default backlog worker count %d, got %d", defaultBacklogWorkers, got)
+	}
+
+	client.connConfig = &config.ConnectionConfig{BacklogWorkers: 7}
+	if got := client.backlogWorkerCount(); got != 7 {
+		t.Errorf("expected configured backlog worker count 7, got %d", got)
+	}
+}
+
+func TestBacklogShardIsStablePerChannel(t *testing.T) {
+	const shardCount = 4
+
+	first := backlogShard("#alpha", shardCount)
+	second := backlogShard("#alpha", shardCount)
+	if first != second {
+		t.Errorf("expected backlogShard to be stable for the same channel, got %d then %d", first, second)
+	}
+	if first < 0 || first >= shardCount {
+		t.Errorf("expected shard in [0, %d), got %d", shardCount, first)
+	}
+}
@@ -2,16 +2,25 @@ package api
 
 import (
 	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"math/rand"
+	"net/http"
 	"sync"
 	"time"
 
+	"irccloud-watcher/internal/auth"
+	"irccloud-watcher/internal/bridge"
 	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/llm"
+	"irccloud-watcher/internal/logging"
+	"irccloud-watcher/internal/plugins"
 	"irccloud-watcher/internal/storage"
 
 	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
 )
 
 // ConnectionState represents the current state of the WebSocket connection
@@ -23,6 +32,10 @@ const (
 	StateConnected
 	StateReconnecting
 	StateError
+	// StateCircuitOpen means too many reconnects happened inside the
+	// configured sliding window, so the client has stopped retrying at
+	// the normal backoff and is waiting out a cooldown instead.
+	StateCircuitOpen
 )
 
 func (s ConnectionState) String() string {
@@ -37,6 +50,8 @@ func (s ConnectionState) String() string {
 		return "reconnecting"
 	case StateError:
 		return "error"
+	case StateCircuitOpen:
+		return "circuit_open"
 	default:
 		return "unknown"
 	}
@@ -45,8 +60,9 @@ func (s ConnectionState) String() string {
 // IRCCloudClient is a client for the IRCCloud API.
 type IRCCloudClient struct {
 	conn              *websocket.Conn
-	db                *storage.DB
-	lastSeenEID       int64
+	wsDialer          *websocket.Dialer
+	httpClient        *http.Client
+	db                storage.MessageStore
 	session           string
 	apiHost           string
 	channels          []string
@@ -54,6 +70,27 @@ type IRCCloudClient struct {
 	channelSet        map[string]bool
 	ignoredChannelSet map[string]bool
 
+	// connectionID identifies this client instance in logs (one process
+	// normally runs a single IRCCloudClient, but the field lets log lines
+	// from a connection's lifecycle - state changes, retries, heartbeats -
+	// be correlated without repeating state/retry_count/last_seen_eid at
+	// every call site). Generated once in NewIRCCloudClient.
+	connectionID string
+
+	// lastSeenEID is the highest EID persisted so far, reported in
+	// heartbeats. Guarded by its own mutex since handlers may update it
+	// concurrently from the handler worker pool.
+	lastSeenEID      int64
+	lastSeenEIDMutex sync.Mutex
+
+	// lastPongTime is when the upstream last answered a ping, used to
+	// declare the connection dead once no pong has landed within
+	// PongTimeoutMultiplier*PingInterval, so a half-open TCP socket
+	// (no pong, no buffer_msg, but no read error either) is detected and
+	// reconnected instead of sitting silently dead.
+	lastPongTime      time.Time
+	lastPongTimeMutex sync.Mutex
+
 	// Connection management
 	connConfig      *config.ConnectionConfig
 	state           ConnectionState
@@ -63,18 +100,175 @@ type IRCCloudClient struct {
 	ctx             context.Context
 	cancelFunc      context.CancelFunc
 
+	// reconnectTimes and backoffMutex back the circuit breaker: each failed
+	// connection attempt is recorded here, and connectWithRetry opens the
+	// circuit once more than CircuitBreakerThreshold fall inside
+	// CircuitBreakerWindow. currentBackoff/nextRetryAt are kept alongside
+	// for Stats().
+	backoffMutex     sync.Mutex
+	reconnectTimes   []time.Time
+	currentBackoff   time.Duration
+	nextRetryAt      time.Time
+	lastBackoffDelay time.Duration
+
+	// rng backs calculateBackoffDelay's jitter. Overridden via
+	// SetRandSource in tests so delay distributions are reproducible.
+	rng *rand.Rand
+
 	// Authentication cache
-	authResp *AuthResponse
-	email    string
-	password string
+	authResp   *AuthResponse
+	email      string
+	password   string
+	authConfig *config.AuthConfig
+
+	// authenticator, if set, is used instead of the built-in
+	// email/password form-token login to obtain credentials in
+	// attemptConnection (e.g. an auth.OAuth2Authenticator for deployments
+	// fronting IRCCloud with an OAuth2 gateway).
+	authenticator auth.Authenticator
+
+	// authHeaderName/authHeaderValue are the header refreshAuth last
+	// obtained (either "Cookie: session=..." from the built-in login or
+	// whatever the configured authenticator returned) and are added to the
+	// WebSocket dial in attemptConnection.
+	authHeaderName  string
+	authHeaderValue string
 
 	// Debug mode
 	debugMode bool
 
-	// EID deduplication cache
-	eidCache      map[int64]bool
-	eidCacheMutex sync.RWMutex
-	maxCacheSize  int
+	// formatRenderer selects how cleanMiddleware handles a message's mIRC
+	// formatting codes: "" (default) strips them via utils.CleanIRCMessage,
+	// "ansi"/"html"/"markdown" preserve them via the matching utils
+	// renderer instead.
+	formatRenderer string
+
+	// logger receives structured log output for this client. Defaults to a
+	// zap-backed console logger; tests can override it with logging.Nop().
+	logger logging.Logger
+
+	// Plugins, if configured, can filter or rewrite messages via the
+	// on_message hook.
+	plugins *plugins.Manager
+
+	// bridge, if configured, fans buffer_msg/join/part events out to
+	// attached downstream IRC clients and relays their PRIVMSGs upstream.
+	bridge *bridge.Server
+
+	// EID deduplication cache: a bounded LRU so dedup windows stay memory-safe
+	// over a long-running watcher without evicting recently-seen EIDs ahead
+	// of older ones.
+	eidCache *eidLRU
+
+	// writeLimiter throttles writes to the upstream WebSocket (heartbeats,
+	// pings, "say" calls) so we don't get disconnected for flooding.
+	writeLimiter *rate.Limiter
+
+	// reconnectLimiter throttles how often connectWithRetry may attempt a
+	// new connection, on top of its backoff delay, so a flapping IRCCloud
+	// endpoint can't cause a hot reconnect loop.
+	reconnectLimiter *rate.Limiter
+
+	// Message enrichment: enricher is nil unless SetEnricher was called
+	// with an enabled config, in which case messages on an opted-in
+	// channel are queued for the LLM-backed worker pool. enrichLimiter
+	// bounds how many enrichment requests per second reach the provider.
+	enricher         llm.Enricher
+	enrichChannelSet map[string]bool
+	enrichQueue      chan *storage.Message
+	enrichLimiter    *rate.Limiter
+
+	// ingestLimiters throttles how fast a single channel may insert
+	// messages into the store, keyed by channel name and created lazily.
+	ingestLimiters      map[string]*rate.Limiter
+	ingestLimitersMutex sync.Mutex
+
+	// rateMetrics counts writes deferred by writeLimiter and messages
+	// dropped by an ingest limiter, so operators can tune the configured
+	// rates.
+	rateMetricsMutex sync.Mutex
+	rateMetrics      RateMetrics
+
+	// Event handler pipeline: middleware runs first, in registration
+	// order, and can veto a message (channel/ignore filtering, dedup,
+	// ...); surviving messages are handed to every handler registered for
+	// their type. Handlers for live events run in a bounded worker pool so
+	// a slow one can't block the WebSocket read loop.
+	middleware      []MiddlewareFunc
+	middlewareMutex sync.RWMutex
+	handlers        map[string][]registeredHandler
+	handlersMutex   sync.RWMutex
+	handlerJobs     chan handlerJob
+	nextHandlerID   int
+}
+
+// LastPongTime reports when the upstream last answered a ping.
+func (c *IRCCloudClient) LastPongTime() time.Time {
+	c.lastPongTimeMutex.Lock()
+	defer c.lastPongTimeMutex.Unlock()
+	return c.lastPongTime
+}
+
+// setLastPongTime records t as the last time the upstream answered a ping
+// and mirrors it into metricLastPongTimestamp.
+func (c *IRCCloudClient) setLastPongTime(t time.Time) {
+	c.lastPongTimeMutex.Lock()
+	c.lastPongTime = t
+	c.lastPongTimeMutex.Unlock()
+	metricLastPongTimestamp.Set(float64(t.Unix()))
+}
+
+// updateLastSeenEID advances lastSeenEID to eid if it's newer.
+func (c *IRCCloudClient) updateLastSeenEID(eid int64) {
+	c.lastSeenEIDMutex.Lock()
+	defer c.lastSeenEIDMutex.Unlock()
+	if eid > c.lastSeenEID {
+		c.lastSeenEID = eid
+	}
+}
+
+// getLastSeenEID returns the highest EID persisted so far.
+func (c *IRCCloudClient) getLastSeenEID() int64 {
+	c.lastSeenEIDMutex.Lock()
+	defer c.lastSeenEIDMutex.Unlock()
+	return c.lastSeenEID
+}
+
+// RateMetrics tracks how often the write and ingest rate limiters had to
+// intervene, so operators can tell whether their configured limits are too
+// tight.
+type RateMetrics struct {
+	DeferredWrites        int64
+	DroppedIngestMessages int64
+}
+
+// RateMetrics returns a snapshot of the client's rate limiter counters.
+func (c *IRCCloudClient) RateMetrics() RateMetrics {
+	c.rateMetricsMutex.Lock()
+	defer c.rateMetricsMutex.Unlock()
+	return c.rateMetrics
+}
+
+// ConnectionStats reports the reconnection loop's current standing, so
+// operators can tell whether a client is healthy, backing off, or tripped
+// the circuit breaker.
+type ConnectionStats struct {
+	State          ConnectionState
+	RetryCount     int
+	CurrentBackoff time.Duration
+	NextRetryAt    time.Time
+}
+
+// Stats returns a snapshot of the client's connection/backoff state.
+func (c *IRCCloudClient) Stats() ConnectionStats {
+	c.backoffMutex.Lock()
+	defer c.backoffMutex.Unlock()
+	return ConnectionStats{
+		State:          c.getState(),
+		RetryCount:     c.retryCount,
+		CurrentBackoff: c.currentBackoff,
+		NextRetryAt:    c.nextRetryAt,
+	}
 }
 
 // AuthResponse is the response from the IRCCloud authentication endpoint.
@@ -88,23 +282,50 @@ type AuthResponse struct {
 	URL           string `json:"url"`
 }
 
-// ErrorResponse represents an API error response.
+// ErrorResponse represents an API error response. It covers both
+// IRCCloud's own {success, message} shape and the RFC 6749 OAuth2
+// {error, error_description, error_uri} shape, since parseAPIResponse
+// probes for either.
 type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success          bool   `json:"success"`
+	Message          string `json:"message"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	ErrorURI         string `json:"error_uri"`
 }
 
-// AuthError represents authentication-related errors.
+// Sentinel error kinds that callers can match against an *AuthError with
+// errors.Is, derived from either the response's `error` code (RFC 6749) or
+// its HTTP status, instead of string-matching Message. Use these to drive
+// retry/backoff policy: ErrRateLimited and ErrServerError are generally
+// worth retrying, ErrInvalidCredentials and ErrTOTPRequired are not.
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrRateLimited        = errors.New("rate limited")
+	ErrTOTPRequired       = errors.New("totp required")
+	ErrServerError        = errors.New("server error")
+)
+
+// AuthError represents authentication-related errors. Kind, if set, is one
+// of the sentinel errors above and is what errors.Is matches against; it is
+// nil when the response didn't map to a known kind.
 type AuthError struct {
 	Type    string
 	Message string
 	Status  int
+	Kind    error
 }
 
 func (e *AuthError) Error() string {
 	return fmt.Sprintf("auth error [%s]: %s (status: %d)", e.Type, e.Message, e.Status)
 }
 
+// Unwrap lets errors.Is(err, ErrInvalidCredentials) (and friends) match
+// against e.Kind without callers needing to type-assert to *AuthError first.
+func (e *AuthError) Unwrap() error {
+	return e.Kind
+}
+
 // IRCMessage represents a message from the IRCCloud WebSocket.
 type IRCMessage struct {
 	Type     string         `json:"type"`
@@ -119,6 +340,11 @@ type IRCMessage struct {
 	Hostmask string         `json:"hostmask"`
 	Ops      map[string]any `json:"ops"`
 	Self     bool           `json:"self"`
+
+	// Tags carries the IRCv3 message-tags IRCCloud forwards on this message
+	// (e.g. "account", "msgid", "+draft/reply", "+draft/react", a
+	// server-time), keyed exactly as the tag name appears on the wire.
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // OOBInclude is a message that contains a URL to the backlog.
@@ -132,17 +358,64 @@ type TokenResponse struct {
 	Token   string `json:"token"`
 }
 
-// NewIRCCloudClient creates a new IRCCloudClient.
-func NewIRCCloudClient(db *storage.DB) *IRCCloudClient {
+// NewIRCCloudClient creates a new IRCCloudClient. Any storage.MessageStore
+// works for message persistence; the EID-cache and enrichment features
+// below only activate when db is the concrete sqlite-backed *storage.DB,
+// since they have no meaning against the fs/memory drivers.
+func NewIRCCloudClient(db storage.MessageStore) *IRCCloudClient {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &IRCCloudClient{
-		db:           db,
-		state:        StateDisconnected,
-		ctx:          ctx,
-		cancelFunc:   cancel,
-		eidCache:     make(map[int64]bool),
-		maxCacheSize: 10000, // Keep track of last 10k EIDs
+
+	defaultLogger, err := logging.New(config.LoggingConfig{})
+	if err != nil {
+		// Should not happen with an empty config, but don't leave the
+		// client without a usable logger.
+		defaultLogger = logging.Nop()
+	}
+
+	c := &IRCCloudClient{
+		db:             db,
+		state:          StateDisconnected,
+		ctx:            ctx,
+		cancelFunc:     cancel,
+		eidCache:       newEIDLRU(10000), // Keep track of last 10k EIDs
+		ingestLimiters: make(map[string]*rate.Limiter),
+		logger:         defaultLogger,
+		handlers:       make(map[string][]registeredHandler),
+		handlerJobs:    make(chan handlerJob, handlerQueueSize),
+		rng:            rand.New(rand.NewSource(time.Now().UnixNano())),
+		connectionID:   newConnectionID(),
+	}
+
+	c.startHandlerWorkers(defaultHandlerWorkers)
+
+	c.AddMiddleware(c.filterChannelsMiddleware)
+	c.AddMiddleware(c.dedupMiddleware)
+	c.AddMiddleware(c.ingestRateLimitMiddleware)
+	c.AddMiddleware(c.cleanMiddleware)
+	c.AddMiddleware(c.pluginMiddleware)
+	c.AddHandler("buffer_msg", c.persistMessageHandler)
+	c.AddHandler("joined_channel", c.membershipHandler)
+	c.AddHandler("parted_channel", c.membershipHandler)
+
+	return c
+}
+
+// newConnectionID generates the short random id logged alongside a
+// client's connection lifecycle, e.g. "a1b2c3d4".
+func newConnectionID() string {
+	buf := make([]byte, 4)
+	if _, err := cryptorand.Read(buf); err != nil {
+		return "unknown"
 	}
+	return hex.EncodeToString(buf)
+}
+
+// sqliteDB returns c.db as a concrete *storage.DB, and whether it actually
+// is one - false under the fs/memory drivers, where EID-cache persistence
+// and enrichment storage have nothing to attach to.
+func (c *IRCCloudClient) sqliteDB() (*storage.DB, bool) {
+	db, ok := c.db.(*storage.DB)
+	return db, ok
 }
 
 // setState safely updates the connection state
@@ -150,8 +423,9 @@ func (c *IRCCloudClient) setState(state ConnectionState) {
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 	if c.state != state {
-		log.Printf("ðŸ”„ Connection state: %s -> %s", c.state, state)
+		c.logger.Infof("connection state change connection_id=%s from=%s to=%s", c.connectionID, c.state, state)
 		c.state = state
+		metricConnectionState.Set(float64(state))
 	}
 }
 
@@ -162,9 +436,23 @@ func (c *IRCCloudClient) getState() ConnectionState {
 	return c.state
 }
 
-// SetConnectionConfig sets the connection configuration
+// SetConnectionConfig sets the connection configuration, (re)builds the
+// upstream write rate limiter from it, and, if PersistEIDCache is enabled,
+// reloads the EID dedup cache snapshot saved by a previous Close.
 func (c *IRCCloudClient) SetConnectionConfig(cfg *config.ConnectionConfig) {
 	c.connConfig = cfg
+	c.writeLimiter = rate.NewLimiter(rate.Limit(cfg.WriteRateLimit), cfg.WriteBurst)
+	c.reconnectLimiter = rate.NewLimiter(rate.Limit(cfg.ReconnectRateLimit), cfg.ReconnectBurst)
+
+	if sqliteDB, ok := c.sqliteDB(); cfg.PersistEIDCache && ok {
+		eids, err := sqliteDB.LoadEIDCache()
+		if err != nil {
+			c.logger.Warnf("failed to load persisted eid cache error=%v", err)
+		} else if len(eids) > 0 {
+			c.eidCache.load(eids)
+			c.logger.Infof("loaded persisted eid cache count=%d", len(eids))
+		}
+	}
 }
 
 // SetDebugMode enables or disables debug mode for printing raw messages
@@ -172,34 +460,153 @@ func (c *IRCCloudClient) SetDebugMode(debug bool) {
 	c.debugMode = debug
 }
 
-// isEIDSeen checks if an EID has been seen before and marks it as seen
+// SetFormatRenderer selects how stored/broadcast messages render mIRC
+// formatting codes: "ansi", "html", or "markdown" to preserve them via the
+// matching utils renderer, or "" (the default) to strip them entirely.
+func (c *IRCCloudClient) SetFormatRenderer(renderer string) {
+	c.formatRenderer = renderer
+}
+
+// SetRandSource overrides the RNG calculateBackoffDelay uses for jitter,
+// e.g. with a seeded rand.Source in tests so delay distributions are
+// reproducible.
+func (c *IRCCloudClient) SetRandSource(source rand.Source) {
+	c.rng = rand.New(source)
+}
+
+// SetLogger overrides the client's structured logger, e.g. to inject
+// logging.Nop() in tests that don't care about log output.
+func (c *IRCCloudClient) SetLogger(l logging.Logger) {
+	c.logger = l
+}
+
+// SetPluginManager attaches a plugin manager so the on_message hook can
+// filter or rewrite messages before they're stored.
+func (c *IRCCloudClient) SetPluginManager(m *plugins.Manager) {
+	c.plugins = m
+}
+
+// SetBridge attaches a downstream IRC gateway, turning this client into the
+// upstream side of a bouncer: buffer_msg/join/part events are fanned out to
+// every attached session, and downstream PRIVMSGs arrive via Say.
+func (c *IRCCloudClient) SetBridge(b *bridge.Server) {
+	c.bridge = b
+}
+
+// SetAuthenticator overrides how attemptConnection obtains credentials,
+// e.g. with an auth.OAuth2Authenticator instead of the default
+// email/password form-token login. Must be called before Connect.
+func (c *IRCCloudClient) SetAuthenticator(a auth.Authenticator) {
+	c.authenticator = a
+}
+
+// SetWebSocketDialer overrides the *websocket.Dialer attemptConnection uses
+// to dial the upstream, e.g. to point TLSClientConfig at a self-signed
+// certificate when driving the client against a fake server in tests.
+// Defaults to nil, in which case attemptConnection builds its own dialer
+// from ConnectionConfig's ConnectionTimeout.
+func (c *IRCCloudClient) SetWebSocketDialer(d *websocket.Dialer) {
+	c.wsDialer = d
+}
+
+// SetHTTPClient overrides the *http.Client openBacklogReader uses to fetch
+// backlog history, e.g. to trust a fake server's self-signed certificate in
+// tests. Defaults to nil, in which case openBacklogReader builds its own
+// client with a 60s timeout.
+func (c *IRCCloudClient) SetHTTPClient(client *http.Client) {
+	c.httpClient = client
+}
+
+// SetAuthConfig supplies the retry knobs (RetryMaxElapsedTime,
+// RetryInitialInterval, RetryMaxInterval) the built-in form-token login
+// uses when retrying a failed login request, and the DebugDumpPath failed
+// auth exchanges are dumped to. The retry knobs have no effect when an
+// authenticator is set via SetAuthenticator, which is responsible for its
+// own retry policy; DebugDumpPath still applies to the WebSocket handshake.
+func (c *IRCCloudClient) SetAuthConfig(cfg *config.AuthConfig) {
+	c.authConfig = cfg
+}
+
+// Authenticate reports whether user/pass match the credentials used for
+// this client's upstream IRCCloud login, so a downstream bridge session can
+// map its SASL PLAIN handshake onto the same account.
+func (c *IRCCloudClient) Authenticate(user, pass string) bool {
+	return user == c.email && pass == c.password
+}
+
+// isEIDSeen checks if an EID has been seen before and marks it as seen,
+// promoting it to most-recently-used either way.
 func (c *IRCCloudClient) isEIDSeen(eid int64) bool {
-	c.eidCacheMutex.Lock()
-	defer c.eidCacheMutex.Unlock()
+	seen := c.eidCache.seen(eid)
+	metricEIDCacheSize.Set(float64(c.eidCache.len()))
+	return seen
+}
 
-	if c.eidCache[eid] {
-		return true
+// waitForWrite blocks until the write limiter has a token for an upstream
+// WebSocket write (heartbeat, ping, or "say"), counting the wait as a
+// deferred send so operators can see how often the configured rate is
+// actually binding.
+func (c *IRCCloudClient) waitForWrite() error {
+	if c.writeLimiter.Allow() {
+		return nil
 	}
 
-	// Add to cache
-	c.eidCache[eid] = true
-
-	// If cache is getting too large, clean it up (simple FIFO-ish cleanup)
-	if len(c.eidCache) > c.maxCacheSize {
-		// Remove roughly 20% of entries to avoid frequent cleanups
-		toRemove := c.maxCacheSize / 5
-		count := 0
-		for k := range c.eidCache {
-			if count >= toRemove {
-				break
-			}
-			delete(c.eidCache, k)
-			count++
-		}
-		if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-			log.Printf("ðŸ§¹ EID cache cleanup: removed %d entries, %d remaining", toRemove, len(c.eidCache))
-		}
+	c.rateMetricsMutex.Lock()
+	c.rateMetrics.DeferredWrites++
+	c.rateMetricsMutex.Unlock()
+
+	return c.waitLimiter(c.writeLimiter, "write")
+}
+
+// waitLimiter blocks until limiter releases a token, recording how long the
+// wait took under name in metricLimiterWaitDuration and warning if it
+// crossed limiterWaitWarnThreshold, since that usually means the
+// configured rate is too tight.
+func (c *IRCCloudClient) waitLimiter(limiter *rate.Limiter, name string) error {
+	start := time.Now()
+	err := limiter.Wait(c.ctx)
+	waited := time.Since(start)
+	metricLimiterWaitDuration.WithLabelValues(name).Observe(waited.Seconds())
+	if waited >= limiterWaitWarnThreshold {
+		c.logger.Warnf("%s limiter wait exceeded threshold wait=%v threshold=%v", name, waited, limiterWaitWarnThreshold)
+	}
+	return err
+}
+
+// allowIngest reports whether channel may insert another message right
+// now, lazily creating its limiter on first use. A channel over its
+// configured rate has the message dropped rather than letting a flood
+// stall SQLite writes for every other channel.
+func (c *IRCCloudClient) allowIngest(channel string) bool {
+	limiter := c.ingestLimiterFor(channel)
+	if limiter.Allow() {
+		return true
 	}
 
+	c.rateMetricsMutex.Lock()
+	c.rateMetrics.DroppedIngestMessages++
+	c.rateMetricsMutex.Unlock()
 	return false
 }
+
+// ingestLimiterFor returns channel's ingest limiter, creating it from the
+// connection config on first use.
+func (c *IRCCloudClient) ingestLimiterFor(channel string) *rate.Limiter {
+	c.ingestLimitersMutex.Lock()
+	defer c.ingestLimitersMutex.Unlock()
+
+	limiter, ok := c.ingestLimiters[channel]
+	if !ok {
+		// connConfig is nil until SetConnectionConfig is called; fall back to
+		// the same defaults setConnectionDefaults applies, so a message
+		// processed before that point is still rate limited rather than
+		// crashing on a nil dereference.
+		rateLimit, burst := 10.0, 20
+		if c.connConfig != nil {
+			rateLimit, burst = c.connConfig.ChannelIngestRateLimit, c.connConfig.ChannelIngestBurst
+		}
+		limiter = rate.NewLimiter(rate.Limit(rateLimit), burst)
+		c.ingestLimiters[channel] = limiter
+	}
+	return limiter
+}
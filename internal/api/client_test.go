@@ -0,0 +1,66 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"irccloud-watcher/internal/config"
+)
+
+func TestLastPongTimeReflectsMostRecentSet(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	if !client.LastPongTime().IsZero() {
+		t.Fatalf("expected zero LastPongTime before any pong, got %v", client.LastPongTime())
+	}
+
+	now := time.Now()
+	client.setLastPongTime(now)
+	if !client.LastPongTime().Equal(now) {
+		t.Errorf("expected LastPongTime %v, got %v", now, client.LastPongTime())
+	}
+}
+
+func TestAllowIngestPerChannelLimiting(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		ChannelIngestRateLimit: 1,
+		ChannelIngestBurst:     1,
+	})
+
+	if !client.allowIngest("#test") {
+		t.Fatal("first message should be allowed within burst")
+	}
+	if client.allowIngest("#test") {
+		t.Fatal("second message should be dropped once the burst is exhausted")
+	}
+
+	// A different channel gets its own limiter and is unaffected.
+	if !client.allowIngest("#other") {
+		t.Fatal("a different channel should have its own limiter")
+	}
+
+	metrics := client.RateMetrics()
+	if metrics.DroppedIngestMessages != 1 {
+		t.Errorf("expected 1 dropped ingest message, got %d", metrics.DroppedIngestMessages)
+	}
+}
+
+func TestWaitForWriteAllowsWithinBurst(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		WriteRateLimit: 1,
+		WriteBurst:     2,
+	})
+
+	if err := client.waitForWrite(); err != nil {
+		t.Fatalf("unexpected error within burst: %v", err)
+	}
+	if err := client.waitForWrite(); err != nil {
+		t.Fatalf("unexpected error within burst: %v", err)
+	}
+
+	metrics := client.RateMetrics()
+	if metrics.DeferredWrites != 0 {
+		t.Errorf("expected no deferred writes within burst, got %d", metrics.DeferredWrites)
+	}
+}
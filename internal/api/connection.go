@@ -3,12 +3,14 @@ package api
 import (
 	"fmt"
 	"io"
-	"log"
 	"math"
+	"math/rand"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"irccloud-watcher/internal/auth"
 )
 
 // Connect connects to the IRCCloud WebSocket API with retry logic.
@@ -20,19 +22,61 @@ func (c *IRCCloudClient) Connect(email, password string) error {
 	return c.connectWithRetry()
 }
 
-// connectWithRetry implements exponential backoff retry logic
+// ConnectWithToken connects using a pre-issued IRCCloud session token,
+// skipping the auth-formtoken + login round-trip entirely.
+func (c *IRCCloudClient) ConnectWithToken(session string) error {
+	c.SetAuthenticator(auth.NewTokenAuthenticator(auth.TokenConfig{Session: session}))
+	return c.connectWithRetry()
+}
+
+// ConnectWithJWT connects using a signed JWT, verified locally, that
+// carries email/uid/exp claims. The verified token is presented upstream
+// as a bearer credential, also skipping the auth-formtoken + login
+// round-trip.
+func (c *IRCCloudClient) ConnectWithJWT(tokenString, key string) error {
+	authenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{Token: tokenString, Key: key})
+	if err != nil {
+		return fmt.Errorf("jwt verification failed: %w", err)
+	}
+	c.SetAuthenticator(authenticator)
+	return c.connectWithRetry()
+}
+
+// connectWithRetry implements full-jitter exponential backoff retry logic,
+// with a circuit breaker that opens once too many reconnects happen inside
+// a sliding window. While the circuit is open, it waits out a cooldown
+// instead of burning through MaxRetryAttempts and giving up.
 func (c *IRCCloudClient) connectWithRetry() error {
 	c.retryCount = 0
 
-	for c.retryCount < c.connConfig.MaxRetryAttempts {
+	for {
 		if c.ctx.Err() != nil {
 			return fmt.Errorf("connection cancelled")
 		}
 
+		if c.circuitShouldOpen() {
+			cooldown := c.circuitBreakerCooldown()
+			c.setState(StateCircuitOpen)
+			c.logger.Errorf("circuit breaker open, too many reconnects connection_id=%s cooldown=%v", c.connectionID, cooldown)
+
+			select {
+			case <-time.After(cooldown):
+			case <-c.ctx.Done():
+				return fmt.Errorf("connection cancelled during circuit breaker cooldown")
+			}
+
+			// Give the client a clean slate after the cooldown: reset both
+			// the breaker's history and the retry count so a string of
+			// cooldowns doesn't itself burn through MaxRetryAttempts.
+			c.resetCircuitBreaker()
+			c.retryCount = 0
+		}
+
 		if c.retryCount > 0 {
 			c.setState(StateReconnecting)
 			delay := c.calculateBackoffDelay()
-			log.Printf("🔄 Retry attempt %d/%d in %v", c.retryCount+1, c.connConfig.MaxRetryAttempts, delay)
+			c.setNextRetry(delay)
+			c.logger.Infof("retrying connection connection_id=%s attempt=%d/%d delay=%v last_seen_eid=%d", c.connectionID, c.retryCount+1, c.connConfig.MaxRetryAttempts, delay, c.getLastSeenEID())
 
 			select {
 			case <-time.After(delay):
@@ -43,16 +87,25 @@ func (c *IRCCloudClient) connectWithRetry() error {
 			c.setState(StateConnecting)
 		}
 
+		if err := c.waitLimiter(c.reconnectLimiter, "reconnect"); err != nil {
+			return fmt.Errorf("reconnect limiter wait cancelled: %w", err)
+		}
+
 		err := c.attemptConnection()
 		if err == nil {
+			if c.retryCount > 0 {
+				metricReconnectsTotal.Inc()
+			}
 			c.setState(StateConnected)
 			c.retryCount = 0
 			c.lastConnectTime = time.Now()
-			log.Println("✅ WebSocket connection established!")
+			c.resetCircuitBreaker()
+			c.logger.Infof("websocket connection established connection_id=%s", c.connectionID)
 			return nil
 		}
 
-		log.Printf("❌ Connection attempt failed: %v", err)
+		c.logger.Errorf("connection attempt failed connection_id=%s retry_count=%d error=%v", c.connectionID, c.retryCount, err)
+		c.recordReconnectAttempt()
 		c.retryCount++
 
 		if c.retryCount >= c.connConfig.MaxRetryAttempts {
@@ -60,33 +113,26 @@ func (c *IRCCloudClient) connectWithRetry() error {
 			return fmt.Errorf("failed to connect after %d attempts: %w", c.connConfig.MaxRetryAttempts, err)
 		}
 	}
-
-	return fmt.Errorf("connection failed")
 }
 
 // attemptConnection tries to establish a single connection
 func (c *IRCCloudClient) attemptConnection() error {
 	// Step 1: Authenticate if we don't have a cached auth response or it's stale
 	if c.authResp == nil || time.Since(c.lastConnectTime) > 30*time.Minute {
-		log.Println("🔐 Authenticating...")
-		authResp, err := c.authenticate(c.email, c.password)
-		if err != nil {
+		c.logger.Infof("authenticating")
+		if err := c.refreshAuth(); err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
 		}
-		c.authResp = authResp
-		c.session = authResp.Session
-		c.apiHost = authResp.APIHost
 	}
 
 	// Step 2: Connect to the WebSocket API
-	log.Println("🌐 Connecting to WebSocket...")
 	wsURL := c.buildWebSocketURL(c.authResp)
-	log.Printf("🌐 WebSocket URL: %s", wsURL)
+	c.logger.Infof("connecting to websocket url=%s", wsURL)
 
 	header := http.Header{}
 	header.Add("Origin", "https://www.irccloud.com")
 	header.Add("User-Agent", "irccloud-watcher/0.1.0")
-	header.Add("Cookie", "session="+c.authResp.Session)
+	header.Add(c.authHeaderName, c.authHeaderValue)
 
 	// Parse connection timeout
 	timeout, err := time.ParseDuration(c.connConfig.ConnectionTimeout)
@@ -94,22 +140,31 @@ func (c *IRCCloudClient) attemptConnection() error {
 		timeout = 45 * time.Second
 	}
 
-	dialer := &websocket.Dialer{
-		Proxy:             http.ProxyFromEnvironment,
-		HandshakeTimeout:  timeout,
-		EnableCompression: true,
+	dialer := c.wsDialer
+	if dialer == nil {
+		dialer = &websocket.Dialer{
+			Proxy:             http.ProxyFromEnvironment,
+			HandshakeTimeout:  timeout,
+			EnableCompression: true,
+		}
 	}
 
+	dialStart := time.Now()
 	conn, resp, err := dialer.Dial(wsURL, header)
+	metricWebsocketDialDuration.Observe(time.Since(dialStart).Seconds())
 	if err != nil {
 		if resp != nil {
-			log.Printf("❌ WebSocket handshake failed with status: %s", resp.Status)
+			c.logger.Errorf("websocket handshake failed status=%s", resp.Status)
 			if location := resp.Header.Get("Location"); location != "" {
-				log.Printf("❌ Redirect location: %s", location)
+				c.logger.Errorf("websocket handshake redirect location=%s", location)
 			}
 			errorBody, readErr := io.ReadAll(resp.Body)
 			if readErr == nil && len(errorBody) < 500 {
-				log.Printf("❌ WebSocket response body: %s", string(errorBody))
+				c.logger.Errorf("websocket handshake response body=%s", string(errorBody))
+			}
+			if c.authConfig != nil {
+				DumpAuthTranscript(c.logger, c.authConfig.DebugDumpPath, "websocket-handshake", "GET", wsURL,
+					header, nil, resp.Status, resp.Header, errorBody)
 			}
 		}
 		return fmt.Errorf("websocket dial failed: %w", err)
@@ -124,7 +179,18 @@ func (c *IRCCloudClient) attemptConnection() error {
 	return nil
 }
 
-// calculateBackoffDelay calculates the delay for exponential backoff
+// calculateBackoffDelay picks the next reconnect delay according to
+// connConfig.BackoffStrategy, so a herd of simultaneously-dropped clients'
+// retries spread out instead of reconnecting in lockstep:
+//
+//   - "exponential": the plain deterministic delay, initialDelay *
+//     BackoffMultiplier^retryCount, capped at maxDelay.
+//   - "exponential_jitter" (default): the same delay, randomized uniformly
+//     within JitterFactor of it and clamped to [0, maxDelay].
+//   - "decorrelated_jitter": AWS's "Full Jitter" follow-up algorithm —
+//     next = random between initialDelay and 3x the previous delay, capped
+//     at maxDelay. lastBackoffDelay reseeds to initialDelay on the first
+//     attempt of a new retry sequence (retryCount == 0).
 func (c *IRCCloudClient) calculateBackoffDelay() time.Duration {
 	initialDelay, err := time.ParseDuration(c.connConfig.InitialRetryDelay)
 	if err != nil {
@@ -136,23 +202,146 @@ func (c *IRCCloudClient) calculateBackoffDelay() time.Duration {
 		maxDelay = 5 * time.Minute
 	}
 
-	// Calculate exponential backoff: initial * (multiplier ^ retryCount)
-	delay := time.Duration(float64(initialDelay) * math.Pow(c.connConfig.BackoffMultiplier, float64(c.retryCount)))
+	if c.connConfig.BackoffStrategy == "decorrelated_jitter" {
+		return c.decorrelatedJitterDelay(initialDelay, maxDelay)
+	}
 
-	// Cap at maximum delay
+	delay := time.Duration(float64(initialDelay) * math.Pow(c.connConfig.BackoffMultiplier, float64(c.retryCount)))
 	delay = min(delay, maxDelay)
+	if delay <= 0 {
+		return 0
+	}
+
+	if c.connConfig.BackoffStrategy == "exponential" {
+		return delay
+	}
+
+	return jitterDelay(c.rng, delay, c.connConfig.JitterFactor, maxDelay)
+}
+
+// decorrelatedJitterDelay implements AWS's decorrelated jitter backoff:
+// next = random in [initialDelay, 3*lastBackoffDelay], capped at maxDelay.
+// lastBackoffDelay is reseeded to initialDelay at the start of a new retry
+// sequence (retryCount == 0) so a prior sequence's delay doesn't leak in.
+func (c *IRCCloudClient) decorrelatedJitterDelay(initialDelay, maxDelay time.Duration) time.Duration {
+	c.backoffMutex.Lock()
+	defer c.backoffMutex.Unlock()
+
+	if c.retryCount == 0 || c.lastBackoffDelay <= 0 {
+		c.lastBackoffDelay = initialDelay
+	}
 
-	return delay
+	next := randBetween(c.rng, initialDelay, c.lastBackoffDelay*3)
+	next = min(next, maxDelay)
+	c.lastBackoffDelay = next
+	return next
+}
+
+// jitterDelay randomizes delay uniformly within jitterFactor of itself
+// (e.g. jitterFactor 0.5 spreads [0.5x, 1.5x]), clamped to [0, maxDelay]. A
+// non-positive jitterFactor disables jitter and returns delay unchanged.
+func jitterDelay(rng *rand.Rand, delay time.Duration, jitterFactor float64, maxDelay time.Duration) time.Duration {
+	if jitterFactor <= 0 {
+		return min(delay, maxDelay)
+	}
+
+	lo := time.Duration(float64(delay) * (1 - jitterFactor))
+	if lo < 0 {
+		lo = 0
+	}
+	hi := time.Duration(float64(delay) * (1 + jitterFactor))
+
+	return min(randBetween(rng, lo, hi), maxDelay)
+}
+
+// randBetween returns a uniformly random duration in [lo, hi], or lo if the
+// range is empty or inverted.
+func randBetween(rng *rand.Rand, lo, hi time.Duration) time.Duration {
+	if hi <= lo {
+		return lo
+	}
+	return lo + time.Duration(rng.Int63n(int64(hi-lo)+1))
+}
+
+// setNextRetry records the upcoming retry delay for Stats().
+func (c *IRCCloudClient) setNextRetry(delay time.Duration) {
+	c.backoffMutex.Lock()
+	defer c.backoffMutex.Unlock()
+	c.currentBackoff = delay
+	c.nextRetryAt = time.Now().Add(delay)
+}
+
+// circuitBreakerWindow and circuitBreakerCooldown parse their respective
+// ConnectionConfig durations, falling back to sane defaults if unset or
+// malformed.
+func (c *IRCCloudClient) circuitBreakerWindow() time.Duration {
+	window, err := time.ParseDuration(c.connConfig.CircuitBreakerWindow)
+	if err != nil {
+		return 2 * time.Minute
+	}
+	return window
+}
+
+func (c *IRCCloudClient) circuitBreakerCooldown() time.Duration {
+	cooldown, err := time.ParseDuration(c.connConfig.CircuitBreakerCooldown)
+	if err != nil {
+		return 10 * time.Minute
+	}
+	return cooldown
+}
+
+// recordReconnectAttempt notes a failed connection attempt for the circuit
+// breaker's sliding window.
+func (c *IRCCloudClient) recordReconnectAttempt() {
+	c.backoffMutex.Lock()
+	defer c.backoffMutex.Unlock()
+	c.reconnectTimes = append(c.reconnectTimes, time.Now())
+}
+
+// circuitShouldOpen reports whether more than CircuitBreakerThreshold
+// reconnect attempts fall inside the current CircuitBreakerWindow, pruning
+// attempts that have aged out of the window as it goes.
+func (c *IRCCloudClient) circuitShouldOpen() bool {
+	c.backoffMutex.Lock()
+	defer c.backoffMutex.Unlock()
+
+	cutoff := time.Now().Add(-c.circuitBreakerWindow())
+	recent := c.reconnectTimes[:0]
+	for _, t := range c.reconnectTimes {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	c.reconnectTimes = recent
+
+	return len(recent) > c.connConfig.CircuitBreakerThreshold
+}
+
+// resetCircuitBreaker clears the reconnect-attempt history, giving the
+// client a clean slate after a successful connection or a cooldown.
+func (c *IRCCloudClient) resetCircuitBreaker() {
+	c.backoffMutex.Lock()
+	defer c.backoffMutex.Unlock()
+	c.reconnectTimes = nil
 }
 
 // Close closes the WebSocket connection and cancels reconnection attempts.
+// If PersistEIDCache is enabled, it snapshots the EID dedup cache to the
+// database first, so SetConnectionConfig can reload it on the next start.
 func (c *IRCCloudClient) Close() {
 	c.setState(StateDisconnected)
 	c.cancelFunc() // Cancel any ongoing operations
 
+	if sqliteDB, ok := c.sqliteDB(); c.connConfig != nil && c.connConfig.PersistEIDCache && ok {
+		eids := c.eidCache.snapshot(c.eidCache.len())
+		if err := sqliteDB.SaveEIDCache(eids); err != nil {
+			c.logger.Warnf("failed to persist eid cache error=%v", err)
+		}
+	}
+
 	if c.conn != nil {
 		if err := c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
-			log.Printf("⚠️ Error writing close message: %v", err)
+			c.logger.Warnf("error writing close message error=%v", err)
 		}
 		c.conn.Close()
 		c.conn = nil
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"irccloud-watcher/internal/config"
+)
+
+func TestCalculateBackoffDelayExponentialJitterStaysWithinRange(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetRandSource(rand.NewSource(42))
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		InitialRetryDelay: "1s",
+		MaxRetryDelay:     "5m",
+		BackoffMultiplier: 2.0,
+		BackoffStrategy:   "exponential_jitter",
+		JitterFactor:      1.0,
+	})
+
+	client.retryCount = 3
+	delay := 1 * time.Second * 8 // initial * multiplier^retryCount
+	lo, hi := time.Duration(0), delay*2
+
+	for i := 0; i < 20; i++ {
+		got := client.calculateBackoffDelay()
+		if got < lo || got > hi {
+			t.Fatalf("expected delay in [%v, %v], got %v", lo, hi, got)
+		}
+	}
+}
+
+func TestCalculateBackoffDelayExponentialHasNoJitter(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		InitialRetryDelay: "1s",
+		MaxRetryDelay:     "5m",
+		BackoffMultiplier: 2.0,
+		BackoffStrategy:   "exponential",
+	})
+
+	client.retryCount = 3
+	want := 1 * time.Second * 8 // initial * multiplier^retryCount
+
+	for i := 0; i < 5; i++ {
+		if got := client.calculateBackoffDelay(); got != want {
+			t.Fatalf("expected deterministic delay %v, got %v", want, got)
+		}
+	}
+}
+
+func TestCalculateBackoffDelayExponentialClampsToMaxDelay(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		InitialRetryDelay: "1s",
+		MaxRetryDelay:     "5s",
+		BackoffMultiplier: 2.0,
+		BackoffStrategy:   "exponential",
+	})
+
+	client.retryCount = 10 // initial * multiplier^10 vastly exceeds MaxRetryDelay
+
+	if got := client.calculateBackoffDelay(); got != 5*time.Second {
+		t.Errorf("expected delay clamped to MaxRetryDelay 5s, got %v", got)
+	}
+}
+
+func TestCalculateBackoffDelayDecorrelatedJitterStaysWithinRange(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetRandSource(rand.NewSource(7))
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		InitialRetryDelay: "1s",
+		MaxRetryDelay:     "1m",
+		BackoffStrategy:   "decorrelated_jitter",
+	})
+
+	client.retryCount = 0
+	for i := 0; i < 20; i++ {
+		client.retryCount = i
+		delay := client.calculateBackoffDelay()
+		if delay < time.Second || delay > time.Minute {
+			t.Fatalf("expected delay in [1s, 1m], got %v", delay)
+		}
+	}
+}
+
+func TestCalculateBackoffDelayDecorrelatedJitterReseedsOnNewSequence(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetRandSource(rand.NewSource(7))
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		InitialRetryDelay: "1s",
+		MaxRetryDelay:     "1m",
+		BackoffStrategy:   "decorrelated_jitter",
+	})
+
+	client.retryCount = 5
+	client.lastBackoffDelay = 45 * time.Second
+	client.retryCount = 0
+
+	delay := client.calculateBackoffDelay()
+	if delay > 3*time.Second {
+		t.Fatalf("expected a reseeded delay near initialDelay, got %v (stale lastBackoffDelay would allow up to the 1m cap)", delay)
+	}
+}
+
+func TestCalculateBackoffDelayIsReproducibleWithSeededSource(t *testing.T) {
+	cfg := &config.ConnectionConfig{
+		InitialRetryDelay: "1s",
+		MaxRetryDelay:     "5m",
+		BackoffMultiplier: 2.0,
+		BackoffStrategy:   "exponential_jitter",
+		JitterFactor:      0.5,
+	}
+
+	run := func() []time.Duration {
+		client := NewIRCCloudClient(nil)
+		client.SetRandSource(rand.NewSource(99))
+		client.SetConnectionConfig(cfg)
+		client.retryCount = 2
+
+		delays := make([]time.Duration, 5)
+		for i := range delays {
+			delays[i] = client.calculateBackoffDelay()
+		}
+		return delays
+	}
+
+	first, second := run(), run()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("expected reproducible delays with the same seed, got %v and %v at index %d", first[i], second[i], i)
+		}
+	}
+}
+
+func TestCircuitBreakerOpensAfterThresholdReconnects(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		CircuitBreakerThreshold: 2,
+		CircuitBreakerWindow:    "1m",
+		CircuitBreakerCooldown:  "1s",
+	})
+
+	for i := 0; i < 2; i++ {
+		if client.circuitShouldOpen() {
+			t.Fatalf("circuit should not open before exceeding the threshold, attempt=%d", i)
+		}
+		client.recordReconnectAttempt()
+	}
+
+	// The threshold (2) reconnects recorded above must not open the circuit
+	// on their own - it opens only once attempts exceed the threshold.
+	client.recordReconnectAttempt()
+	if !client.circuitShouldOpen() {
+		t.Fatal("expected circuit to open after exceeding the threshold")
+	}
+
+	client.resetCircuitBreaker()
+	if client.circuitShouldOpen() {
+		t.Fatal("expected circuit to stay closed after reset")
+	}
+}
+
+func TestWaitLimiterAllowsWithinBurstAndBlocksBeyondIt(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		ReconnectRateLimit: 1,
+		ReconnectBurst:     1,
+	})
+
+	if err := client.waitLimiter(client.reconnectLimiter, "reconnect"); err != nil {
+		t.Fatalf("unexpected error within burst: %v", err)
+	}
+
+	start := time.Now()
+	if err := client.waitLimiter(client.reconnectLimiter, "reconnect"); err != nil {
+		t.Fatalf("unexpected error waiting for a token: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("expected the second call to block for close to 1s at 1 token/sec, only waited %v", elapsed)
+	}
+}
+
+func TestStatsReportsRetryStateAfterSetNextRetry(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.retryCount = 2
+	client.setNextRetry(5 * time.Second)
+
+	stats := client.Stats()
+	if stats.RetryCount != 2 {
+		t.Errorf("expected retry count 2, got %d", stats.RetryCount)
+	}
+	if stats.CurrentBackoff != 5*time.Second {
+		t.Errorf("expected current backoff 5s, got %v", stats.CurrentBackoff)
+	}
+	if stats.NextRetryAt.Before(time.Now()) {
+		t.Errorf("expected NextRetryAt to be in the future")
+	}
+}
@@ -1,33 +1,34 @@
 package api
 
 import (
-	"log"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"time"
+
+	"irccloud-watcher/internal/logging"
 )
 
-// debugLogRequest logs HTTP request details when debug mode is enabled
-func debugLogRequest(method, requestURL string, headers http.Header) {
-	if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-		log.Printf("🔍 %s %s", method, requestURL)
-		for key, values := range headers {
-			if !isSensitiveHeader(key) {
-				log.Printf("🔍   %s: %s", key, strings.Join(values, ", "))
-			}
+// debugLogRequest logs HTTP request details at debug level
+func debugLogRequest(logger logging.Logger, method, requestURL string, headers http.Header) {
+	logger.Debugf("%s %s", method, requestURL)
+	for key, values := range headers {
+		if !isSensitiveHeader(key) {
+			logger.Debugf("  %s: %s", key, strings.Join(values, ", "))
 		}
 	}
 }
 
-// debugLogResponse logs HTTP response details when debug mode is enabled
-func debugLogResponse(resp *http.Response, body []byte) {
-	if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-		log.Printf("🔍 Response: %s", resp.Status)
-		if len(body) > 200 {
-			log.Printf("🔍 Body: %s...", string(body[:200]))
-		} else {
-			log.Printf("🔍 Body: %s", string(body))
-		}
+// debugLogResponse logs HTTP response details at debug level
+func debugLogResponse(logger logging.Logger, resp *http.Response, body []byte) {
+	logger.Debugf("response status=%s", resp.Status)
+	if len(body) > 200 {
+		logger.Debugf("body=%s...", string(body[:200]))
+	} else {
+		logger.Debugf("body=%s", string(body))
 	}
 }
 
@@ -41,3 +42,113 @@ func isSensitiveHeader(key string) bool {
 	}
 	return false
 }
+
+// redactedBodyFields are the JSON/form fields masked by redactBody before a
+// request or response body is written to a debug dump.
+var redactedBodyFields = []string{"password", "session", "access_token", "email"}
+
+const redactedPlaceholder = "***REDACTED***"
+
+// redactBody masks redactedBodyFields in a JSON or form-urlencoded body,
+// guessing the encoding from contentType. Bodies in another encoding (or
+// that fail to parse) are returned unchanged, since there's nothing
+// structured to redact.
+func redactBody(body []byte, contentType string) []byte {
+	switch {
+	case strings.Contains(contentType, "json"):
+		return redactJSONBody(body)
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		return redactFormBody(body)
+	default:
+		return body
+	}
+}
+
+func redactJSONBody(body []byte) []byte {
+	var parsed map[string]any
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return body
+	}
+	redacted := false
+	for _, field := range redactedBodyFields {
+		if _, ok := parsed[field]; ok {
+			parsed[field] = redactedPlaceholder
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+	out, err := json.Marshal(parsed)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func redactFormBody(body []byte) []byte {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return body
+	}
+	redacted := false
+	for _, field := range redactedBodyFields {
+		if values.Has(field) {
+			values.Set(field, redactedPlaceholder)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+	return []byte(values.Encode())
+}
+
+// DumpAuthTranscript appends a redacted request/response transcript for an
+// auth-related HTTP exchange to the file at path, for a user to attach to a
+// bug report without leaking credentials: headers matching isSensitiveHeader
+// are dropped, and redactedBodyFields are masked in both bodies. label
+// identifies which step of the auth flow this came from (e.g.
+// "auth-formtoken", "login", "websocket-handshake").
+//
+// Failures to write are logged and otherwise swallowed, since a missing
+// debug artifact shouldn't fail the auth flow itself.
+func DumpAuthTranscript(logger logging.Logger, path, label, method, requestURL string, reqHeaders http.Header, reqBody []byte, status string, respHeaders http.Header, respBody []byte) {
+	if path == "" {
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		logger.Warnf("could not open debug dump path=%s error=%v", path, err)
+		return
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "=== %s %s ===\n", time.Now().UTC().Format(time.RFC3339), label)
+	fmt.Fprintf(&b, "> %s %s\n", method, requestURL)
+	writeRedactedHeaders(&b, reqHeaders)
+	if len(reqBody) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", redactBody(reqBody, reqHeaders.Get("Content-Type")))
+	}
+	fmt.Fprintf(&b, "< %s\n", status)
+	writeRedactedHeaders(&b, respHeaders)
+	if len(respBody) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", redactBody(respBody, respHeaders.Get("Content-Type")))
+	}
+	b.WriteString("\n")
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		logger.Warnf("could not write debug dump path=%s error=%v", path, err)
+	}
+}
+
+func writeRedactedHeaders(b *strings.Builder, headers http.Header) {
+	for key, values := range headers {
+		if isSensitiveHeader(key) {
+			continue
+		}
+		fmt.Fprintf(b, "%s: %s\n", key, strings.Join(values, ", "))
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"irccloud-watcher/internal/logging"
+)
+
+func TestRedactBodyJSON(t *testing.T) {
+	body := []byte(`{"email":"user@example.com","password":"hunter2","other":"kept"}`)
+	redacted := string(redactBody(body, "application/json"))
+
+	if !strings.Contains(redacted, redactedPlaceholder) {
+		t.Fatalf("expected redacted placeholder in %s", redacted)
+	}
+	if strings.Contains(redacted, "hunter2") || strings.Contains(redacted, "user@example.com") {
+		t.Errorf("expected password/email to be redacted, got %s", redacted)
+	}
+	if !strings.Contains(redacted, "kept") {
+		t.Errorf("expected non-sensitive fields to survive, got %s", redacted)
+	}
+}
+
+func TestRedactBodyForm(t *testing.T) {
+	body := []byte("email=user%40example.com&password=hunter2&token=abc123")
+	redacted := string(redactBody(body, "application/x-www-form-urlencoded"))
+
+	if strings.Contains(redacted, "hunter2") || strings.Contains(redacted, "user@example.com") {
+		t.Errorf("expected password/email to be redacted, got %s", redacted)
+	}
+	if !strings.Contains(redacted, "token=abc123") {
+		t.Errorf("expected non-sensitive fields to survive, got %s", redacted)
+	}
+}
+
+func TestRedactBodyUnknownEncodingUnchanged(t *testing.T) {
+	body := []byte("plain text body")
+	if got := string(redactBody(body, "text/plain")); got != string(body) {
+		t.Errorf("expected unchanged body, got %s", got)
+	}
+}
+
+func TestDumpAuthTranscriptNoopWithoutPath(t *testing.T) {
+	// Should not panic or attempt any I/O when no path is configured.
+	DumpAuthTranscript(logging.Nop(), "", "login", "POST", "https://example.com", nil, nil, "200 OK", nil, nil)
+}
+
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"container/list"
+	"sync"
+)
+
+// eidLRU is a fixed-capacity LRU set of EIDs backing isEIDSeen. Unlike the
+// map it replaces, eviction always removes the actual least-recently-seen
+// entries instead of an arbitrary, map-iteration-order-dependent subset -
+// which could otherwise evict EIDs seen moments ago and re-admit them as
+// "new" duplicates right after a cleanup.
+type eidLRU struct {
+	mu      sync.Mutex
+	maxSize int
+	ll      *list.List
+	items   map[int64]*list.Element
+}
+
+// newEIDLRU creates an eidLRU holding at most maxSize entries.
+func newEIDLRU(maxSize int) *eidLRU {
+	return &eidLRU{
+		maxSize: maxSize,
+		ll:      list.New(),
+		items:   make(map[int64]*list.Element),
+	}
+}
+
+// seen reports whether eid has been recorded before, promoting it to
+// most-recently-used if so, and otherwise inserts it at the front and
+// evicts from the back until the cache is back within maxSize.
+func (c *eidLRU) seen(eid int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[eid]; ok {
+		c.ll.MoveToFront(el)
+		return true
+	}
+
+	c.items[eid] = c.ll.PushFront(eid)
+	for c.ll.Len() > c.maxSize {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(int64))
+	}
+	return false
+}
+
+// len returns the number of EIDs currently held.
+func (c *eidLRU) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// snapshot returns up to n of the most-recently-used EIDs, most-recent
+// first, for persisting across restarts.
+func (c *eidLRU) snapshot(n int) []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	eids := make([]int64, 0, min(n, c.ll.Len()))
+	for el := c.ll.Front(); el != nil && len(eids) < n; el = el.Next() {
+		eids = append(eids, el.Value.(int64))
+	}
+	return eids
+}
+
+// load seeds the cache from a previously saved snapshot, oldest first, so
+// the most-recent entry ends up at the front (most-recently-used) as if it
+// had just been seen.
+func (c *eidLRU) load(eids []int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := len(eids) - 1; i >= 0; i-- {
+		eid := eids[i]
+		if _, ok := c.items[eid]; ok {
+			continue
+		}
+		c.items[eid] = c.ll.PushFront(eid)
+	}
+	for c.ll.Len() > c.maxSize {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(int64))
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import "testing"
+
+func TestEIDLRUReportsNewEntriesAsUnseen(t *testing.T) {
+	c := newEIDLRU(10)
+
+	if c.seen(1) {
+		t.Error("expected a new EID to be unseen")
+	}
+	if !c.seen(1) {
+		t.Error("expected a repeated EID to be seen")
+	}
+}
+
+func TestEIDLRUEvictsLeastRecentlyUsedOnce(t *testing.T) {
+	c := newEIDLRU(2)
+
+	c.seen(1)
+	c.seen(2)
+	c.seen(1) // promote 1, so 2 is now the least-recently-used
+	c.seen(3) // evicts 2, not 1
+
+	if c.seen(1) != true {
+		t.Error("expected recently-promoted EID 1 to survive eviction")
+	}
+	if c.seen(2) != false {
+		t.Error("expected least-recently-used EID 2 to have been evicted")
+	}
+}
+
+func TestEIDLRULenStaysWithinMaxSize(t *testing.T) {
+	c := newEIDLRU(5)
+	for i := int64(0); i < 100; i++ {
+		c.seen(i)
+	}
+	if got := c.len(); got != 5 {
+		t.Errorf("expected len 5, got %d", got)
+	}
+}
+
+func TestEIDLRUSnapshotAndLoadRoundTrip(t *testing.T) {
+	c := newEIDLRU(10)
+	c.seen(1)
+	c.seen(2)
+	c.seen(3)
+
+	snap := c.snapshot(10)
+	if len(snap) != 3 || snap[0] != 3 || snap[1] != 2 || snap[2] != 1 {
+		t.Fatalf("expected snapshot [3 2 1], got %v", snap)
+	}
+
+	loaded := newEIDLRU(10)
+	loaded.load(snap)
+
+	for _, eid := range []int64{1, 2, 3} {
+		if !loaded.seen(eid) {
+			t.Errorf("expected loaded cache to already contain eid %d", eid)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/llm"
+	"irccloud-watcher/internal/storage"
+)
+
+// SetEnricher attaches an llm.Enricher and starts its worker pool and
+// background reconciler according to cfg. A nil enricher or a disabled cfg
+// leaves enrichment off, matching SetPluginManager/SetBridge's nil-means-off
+// convention.
+func (c *IRCCloudClient) SetEnricher(enricher llm.Enricher, cfg *config.EnrichmentConfig) {
+	if enricher == nil || cfg == nil || !cfg.Enabled {
+		return
+	}
+
+	c.enricher = enricher
+	c.enrichChannelSet = make(map[string]bool, len(cfg.Channels))
+	for _, ch := range cfg.Channels {
+		c.enrichChannelSet[ch] = true
+	}
+	c.enrichQueue = make(chan *storage.Message, cfg.QueueSize)
+	c.enrichLimiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), cfg.Burst)
+
+	for i := 0; i < cfg.WorkerCount; i++ {
+		go c.enrichmentWorker()
+	}
+
+	reconcileInterval, err := time.ParseDuration(cfg.ReconcileInterval)
+	if err != nil {
+		reconcileInterval = 5 * time.Minute
+	}
+	go c.runEnrichmentReconciler(reconcileInterval)
+}
+
+// enqueueEnrichment queues msg for enrichment if enrichment is enabled for
+// its channel, dropping it (and logging) rather than blocking persistence
+// if the queue is full; the reconciler will pick it up later.
+func (c *IRCCloudClient) enqueueEnrichment(msg *storage.Message) {
+	if c.enricher == nil || !c.enrichChannelSet[msg.Channel] {
+		return
+	}
+
+	select {
+	case c.enrichQueue <- msg:
+	default:
+		c.logger.Warnf("enrichment queue full, dropping message channel=%s eid=%d", msg.Channel, msg.EID)
+	}
+}
+
+// enrichmentWorker drains c.enrichQueue, producing and storing an
+// enrichment for each message. A failed enrichment (provider unavailable,
+// malformed response) is logged and left for runEnrichmentReconciler to
+// retry, rather than failing the message itself.
+func (c *IRCCloudClient) enrichmentWorker() {
+	for msg := range c.enrichQueue {
+		c.enrichMessage(msg)
+	}
+}
+
+// enrichMessage waits for the shared enrichment rate limiter, then asks the
+// enricher for and stores an enrichment for msg.
+func (c *IRCCloudClient) enrichMessage(msg *storage.Message) {
+	if err := c.waitLimiter(c.enrichLimiter, "enrichment"); err != nil {
+		return
+	}
+
+	enrichment, err := c.enricher.Enrich(c.ctx, msg)
+	if err != nil {
+		c.logger.Warnf("enrichment failed channel=%s eid=%d error=%v", msg.Channel, msg.EID, err)
+		return
+	}
+
+	sqliteDB, ok := c.sqliteDB()
+	if !ok {
+		c.logger.Warnf("enrichment storage requires the sqlite storage driver, dropping result channel=%s eid=%d", msg.Channel, msg.EID)
+		return
+	}
+
+	if err := sqliteDB.InsertEnrichment(enrichment); err != nil {
+		c.logger.Errorf("failed to store enrichment channel=%s eid=%d error=%v", msg.Channel, msg.EID, err)
+	}
+}
+
+// runEnrichmentReconciler periodically retries messages that were stored
+// without an enrichment, e.g. because the LLM provider was unavailable when
+// they were first ingested.
+func (c *IRCCloudClient) runEnrichmentReconciler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.reconcileMissingEnrichments()
+		}
+	}
+}
+
+// reconcileMissingEnrichments fetches one batch of messages lacking an
+// enrichment and enqueues each for retry.
+func (c *IRCCloudClient) reconcileMissingEnrichments() {
+	const batchSize = 50
+
+	sqliteDB, ok := c.sqliteDB()
+	if !ok {
+		return
+	}
+
+	messages, err := sqliteDB.GetMessagesMissingEnrichment(batchSize)
+	if err != nil {
+		c.logger.Warnf("enrichment reconciler failed to query pending messages error=%v", err)
+		return
+	}
+
+	for i := range messages {
+		c.enqueueEnrichment(&messages[i])
+	}
+}
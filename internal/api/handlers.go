@@ -0,0 +1,327 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"irccloud-watcher/internal/storage"
+	"irccloud-watcher/internal/utils"
+)
+
+const (
+	// defaultHandlerWorkers is the size of the worker pool that runs
+	// registered message handlers, so a slow handler (e.g. a webhook
+	// notifier) can't block the WebSocket read loop.
+	defaultHandlerWorkers = 4
+
+	// handlerQueueSize bounds how many dispatched messages may be queued
+	// for the worker pool before new ones are dropped.
+	handlerQueueSize = 256
+)
+
+// MiddlewareFunc inspects or rewrites msg before it reaches any handler.
+// Returning keep=false vetoes the message (e.g. channel/ignore filtering,
+// deduplication) and stops the chain; later middleware and all handlers are
+// skipped.
+type MiddlewareFunc func(ctx context.Context, msg *IRCMessage) (keep bool, err error)
+
+// MessageHandlerFunc reacts to a message that survived the middleware chain
+// (persistence, notifiers, bridges, ...). An error from one handler is
+// logged but does not stop the others.
+type MessageHandlerFunc func(ctx context.Context, msg *IRCMessage) error
+
+// handlerJob is one message queued for the handler worker pool.
+type handlerJob struct {
+	ctx context.Context
+	msg *IRCMessage
+}
+
+// registeredHandler pairs a handler with the id AddCallback handed back, so
+// RemoveCallback can find and drop it again.
+type registeredHandler struct {
+	id int
+	fn MessageHandlerFunc
+}
+
+// AddMiddleware registers fn to run, in registration order, before any
+// handler sees a message.
+func (c *IRCCloudClient) AddMiddleware(fn MiddlewareFunc) {
+	c.middlewareMutex.Lock()
+	defer c.middlewareMutex.Unlock()
+	c.middleware = append(c.middleware, fn)
+}
+
+// AddHandler registers fn to run for every message of msgType that survives
+// the middleware chain. Handlers run on the bounded worker pool, not the
+// WebSocket read goroutine. Equivalent to AddCallback, but for internal
+// callers that don't need the id back (e.g. the built-in handlers
+// registered by NewIRCCloudClient).
+func (c *IRCCloudClient) AddHandler(msgType string, fn MessageHandlerFunc) {
+	_, _ = c.AddCallback(msgType, fn)
+}
+
+// AddCallback registers fn to run for every message of msgType that
+// survives the middleware chain (channel/ignore filtering, dedup, rate
+// limiting, ...), in registration order alongside any other callback for
+// that type. Modeled on go-ircevent's callback registry: new message types
+// (channel op tracking, presence, topic history, ...) can hook in here
+// without editing processMessage. The returned id can be passed to
+// RemoveCallback to unregister fn later.
+func (c *IRCCloudClient) AddCallback(msgType string, fn MessageHandlerFunc) (int, error) {
+	if msgType == "" {
+		return 0, fmt.Errorf("msgType must not be empty")
+	}
+
+	c.handlersMutex.Lock()
+	defer c.handlersMutex.Unlock()
+	c.nextHandlerID++
+	id := c.nextHandlerID
+	c.handlers[msgType] = append(c.handlers[msgType], registeredHandler{id: id, fn: fn})
+	return id, nil
+}
+
+// RemoveCallback unregisters the callback AddCallback returned id for, if
+// it's still registered. Unknown ids are a no-op.
+func (c *IRCCloudClient) RemoveCallback(id int) {
+	c.handlersMutex.Lock()
+	defer c.handlersMutex.Unlock()
+
+	for msgType, handlers := range c.handlers {
+		for i, h := range handlers {
+			if h.id == id {
+				c.handlers[msgType] = append(handlers[:i:i], handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// startHandlerWorkers launches n goroutines draining handlerJobs.
+func (c *IRCCloudClient) startHandlerWorkers(n int) {
+	for i := 0; i < n; i++ {
+		go c.handlerWorker()
+	}
+}
+
+func (c *IRCCloudClient) handlerWorker() {
+	for job := range c.handlerJobs {
+		c.dispatch(job.ctx, job.msg)
+	}
+}
+
+// dispatch runs every handler registered for msg.Type, logging (rather than
+// propagating) individual handler errors so one bad handler can't stop the
+// rest.
+func (c *IRCCloudClient) dispatch(ctx context.Context, msg *IRCMessage) {
+	c.handlersMutex.RLock()
+	handlers := append([]registeredHandler(nil), c.handlers[msg.Type]...)
+	c.handlersMutex.RUnlock()
+
+	for _, h := range handlers {
+		if err := h.fn(ctx, msg); err != nil {
+			c.logger.Warnf("handler error type=%s channel=%s eid=%d error=%v", msg.Type, msg.Chan, msg.EID, err)
+		}
+	}
+}
+
+// runMiddleware runs msg through the middleware chain in registration
+// order, stopping (and reporting keep=false) as soon as one vetoes it.
+func (c *IRCCloudClient) runMiddleware(ctx context.Context, msg *IRCMessage) (keep bool, err error) {
+	c.middlewareMutex.RLock()
+	chain := append([]MiddlewareFunc(nil), c.middleware...)
+	c.middlewareMutex.RUnlock()
+
+	for _, mw := range chain {
+		keep, err := mw(ctx, msg)
+		if err != nil {
+			return false, err
+		}
+		if !keep {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// handleMessage runs msg through the middleware chain and, if it survives,
+// queues it for the handler worker pool. Used for live WebSocket traffic:
+// middleware runs synchronously on the read loop (it's cheap filtering),
+// while handlers run off of it so a slow one can't stall reads.
+func (c *IRCCloudClient) handleMessage(ctx context.Context, msg *IRCMessage) error {
+	keep, err := c.runMiddleware(ctx, msg)
+	if err != nil || !keep {
+		return err
+	}
+
+	select {
+	case c.handlerJobs <- handlerJob{ctx: ctx, msg: msg}:
+	default:
+		c.logger.Warnf("handler queue full, dropping message type=%s channel=%s eid=%d", msg.Type, msg.Chan, msg.EID)
+	}
+	return nil
+}
+
+// handleMessageSync runs msg through the middleware chain and, if it
+// survives, dispatches it to handlers on the caller's goroutine instead of
+// the worker pool. Used for backlog replay, which must finish storing and
+// broadcasting history in EID order before any live message reaches it.
+func (c *IRCCloudClient) handleMessageSync(ctx context.Context, msg *IRCMessage) error {
+	keep, err := c.runMiddleware(ctx, msg)
+	if err != nil || !keep {
+		return err
+	}
+	c.dispatch(ctx, msg)
+	return nil
+}
+
+// filterChannelsMiddleware drops events for ignored or not-explicitly-
+// allowed channels. Channel-scope filtering applies uniformly to any event
+// that carries a channel (buffer_msg, joined_channel, parted_channel, ...);
+// events with no channel (e.g. backlog-starting/oob_include) aren't
+// channel-scoped and always pass through.
+func (c *IRCCloudClient) filterChannelsMiddleware(ctx context.Context, msg *IRCMessage) (bool, error) {
+	if msg.Chan == "" {
+		return true, nil
+	}
+	if c.ignoredChannelSet[msg.Chan] {
+		c.logger.Debugf("message filtered type=%s channel=%s ignored=true", msg.Type, msg.Chan)
+		metricMessagesIgnored.WithLabelValues("channel_filter").Inc()
+		return false, nil
+	}
+	if len(c.channels) > 0 && !c.channelSet[msg.Chan] {
+		c.logger.Debugf("message filtered type=%s channel=%s channel_allowed=false", msg.Type, msg.Chan)
+		metricMessagesIgnored.WithLabelValues("channel_filter").Inc()
+		return false, nil
+	}
+	return true, nil
+}
+
+// dedupMiddleware drops buffer_msg events whose EID has already been seen.
+func (c *IRCCloudClient) dedupMiddleware(ctx context.Context, msg *IRCMessage) (bool, error) {
+	if msg.Type != "buffer_msg" {
+		return true, nil
+	}
+	if c.isEIDSeen(msg.EID) {
+		c.logger.Debugf("duplicate message filtered eid=%d channel=%s", msg.EID, msg.Chan)
+		metricMessagesIgnored.WithLabelValues("eid_duplicate").Inc()
+		return false, nil
+	}
+	return true, nil
+}
+
+// ingestRateLimitMiddleware drops buffer_msg events once a channel exceeds
+// its configured ingest rate.
+func (c *IRCCloudClient) ingestRateLimitMiddleware(ctx context.Context, msg *IRCMessage) (bool, error) {
+	if msg.Type != "buffer_msg" {
+		return true, nil
+	}
+	if !c.allowIngest(msg.Chan) {
+		c.logger.Debugf("message dropped by ingest rate limiter channel=%s eid=%d", msg.Chan, msg.EID)
+		metricMessagesIgnored.WithLabelValues("rate_limited").Inc()
+		return false, nil
+	}
+	return true, nil
+}
+
+// cleanMiddleware strips or renders IRC formatting codes (depending on
+// formatRenderer) from the message bodies that get persisted or broadcast
+// to the bridge.
+func (c *IRCCloudClient) cleanMiddleware(ctx context.Context, msg *IRCMessage) (bool, error) {
+	switch msg.Type {
+	case "buffer_msg", "parted_channel":
+		msg.Msg = c.renderMessage(msg.Msg)
+	}
+	return true, nil
+}
+
+// renderMessage renders msg's mIRC formatting codes according to
+// formatRenderer ("ansi", "html", or "markdown"), or strips them entirely
+// (the default, and the behavior of the empty/"plain" value), trimming
+// surrounding whitespace either way.
+func (c *IRCCloudClient) renderMessage(msg string) string {
+	var rendered string
+	switch c.formatRenderer {
+	case "ansi":
+		rendered = utils.RenderANSI(utils.ParseFormatting(msg))
+	case "html":
+		rendered = utils.RenderHTML(utils.ParseFormatting(msg))
+	case "markdown":
+		rendered = utils.RenderMarkdown(utils.ParseFormatting(msg))
+	default:
+		return utils.CleanIRCMessage(msg)
+	}
+	return strings.TrimSpace(rendered)
+}
+
+// pluginMiddleware runs the configured plugin manager's on_message hook over
+// buffer_msg events, letting plugins rewrite or drop them.
+func (c *IRCCloudClient) pluginMiddleware(ctx context.Context, msg *IRCMessage) (bool, error) {
+	if msg.Type != "buffer_msg" || c.plugins == nil {
+		return true, nil
+	}
+
+	rewritten, keep, err := c.plugins.OnMessage(msg.Chan, msg.From, msg.Msg)
+	if err != nil {
+		c.logger.Warnf("plugin on_message hook error=%v", err)
+		return true, nil
+	}
+	if !keep {
+		c.logger.Debugf("message dropped by plugin channel=%s eid=%d", msg.Chan, msg.EID)
+		return false, nil
+	}
+	msg.Msg = rewritten
+	return true, nil
+}
+
+// persistMessageHandler is the built-in buffer_msg handler: it stores the
+// message in the DB, advances lastSeenEID, and fans it out to the bridge if
+// one is attached. It's registered by default, but is just another handler
+// - callers can add their own (webhooks, keyword highlights, ...) alongside
+// it via AddHandler.
+func (c *IRCCloudClient) persistMessageHandler(ctx context.Context, msg *IRCMessage) error {
+	msgTime := ircTimestamp(msg.Time)
+
+	c.logger.Debugf("processing message channel=%s from=%s eid=%d time=%d converted=%s", msg.Chan, msg.From, msg.EID, msg.Time, msgTime.Format(time.RFC3339))
+	c.logger.Infof("%s <%s> %s", msg.Chan, msg.From, msg.Msg)
+
+	dbMsg := &storage.Message{
+		Channel:   msg.Chan,
+		Timestamp: msgTime,
+		Sender:    msg.From,
+		Message:   msg.Msg,
+		Date:      msgTime.Format("2006-01-02"),
+		EID:       msg.EID,
+		Tags:      storage.Tags(msg.Tags),
+	}
+
+	insertStart := time.Now()
+	err := c.db.InsertMessage(dbMsg)
+	metricMessageProcessingDuration.Observe(time.Since(insertStart).Seconds())
+	if err != nil {
+		c.logger.Errorf("error inserting message into db eid=%d error=%v", msg.EID, err)
+		return fmt.Errorf("error inserting message into DB: %w", err)
+	}
+
+	metricMessagesIngested.WithLabelValues(msg.Chan).Inc()
+	c.logger.Debugf("message stored successfully eid=%d", msg.EID)
+	c.updateLastSeenEID(msg.EID)
+	c.enqueueEnrichment(dbMsg)
+
+	if c.bridge != nil {
+		c.bridge.BroadcastMessage(msg.From, msg.Chan, msg.Msg, false)
+	}
+
+	return nil
+}
+
+// membershipHandler relays joined_channel/parted_channel events to the
+// bridge, if one is attached.
+func (c *IRCCloudClient) membershipHandler(ctx context.Context, msg *IRCMessage) error {
+	if c.bridge == nil {
+		return nil
+	}
+	c.broadcastMembershipEvent(*msg)
+	return nil
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"irccloud-watcher/internal/storage"
+)
+
+func TestFilterChannelsMiddlewareDropsIgnoredAndDisallowedChannels(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.channels = []string{"#allowed"}
+	client.channelSet = map[string]bool{"#allowed": true}
+	client.ignoredChannelSet = map[string]bool{"#ignored": true}
+
+	cases := []struct {
+		chan_ string
+		keep  bool
+	}{
+		{"#allowed", true},
+		{"#other", false},
+		{"#ignored", false},
+	}
+
+	for _, tc := range cases {
+		msg := &IRCMessage{Type: "buffer_msg", Chan: tc.chan_}
+		keep, err := client.filterChannelsMiddleware(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("unexpected error for channel %s: %v", tc.chan_, err)
+		}
+		if keep != tc.keep {
+			t.Errorf("channel %s: expected keep=%t, got %t", tc.chan_, tc.keep, keep)
+		}
+	}
+}
+
+func TestDedupMiddlewareDropsRepeatedEID(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	msg := &IRCMessage{Type: "buffer_msg", EID: 42}
+
+	keep, err := client.dedupMiddleware(context.Background(), msg)
+	if err != nil || !keep {
+		t.Fatalf("expected first occurrence to be kept, got keep=%t err=%v", keep, err)
+	}
+
+	keep, err = client.dedupMiddleware(context.Background(), msg)
+	if err != nil || keep {
+		t.Fatalf("expected duplicate EID to be dropped, got keep=%t err=%v", keep, err)
+	}
+}
+
+func TestCleanMiddlewareStripsColorsByDefault(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	msg := &IRCMessage{Type: "buffer_msg", Msg: "\x0304red text"}
+
+	if _, err := client.cleanMiddleware(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Msg != "red text" {
+		t.Errorf("expected colors stripped, got %q", msg.Msg)
+	}
+}
+
+func TestCleanMiddlewarePreservesFormattingWhenRendererSet(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.SetFormatRenderer("markdown")
+	msg := &IRCMessage{Type: "buffer_msg", Msg: "\x02bold\x02"}
+
+	if _, err := client.cleanMiddleware(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Msg != "**bold**" {
+		t.Errorf("expected markdown-rendered text, got %q", msg.Msg)
+	}
+}
+
+func TestAddHandlerRunsRegisteredHandlersForMessageType(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+
+	seen := make(chan string, 1)
+	client.AddHandler("highlight", func(ctx context.Context, msg *IRCMessage) error {
+		seen <- msg.Msg
+		return nil
+	})
+
+	if err := client.handleMessageSync(context.Background(), &IRCMessage{Type: "highlight", Msg: "hello"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case msg := <-seen:
+		if msg != "hello" {
+			t.Errorf("expected handler to see %q, got %q", "hello", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was not invoked")
+	}
+}
+
+func TestAddCallbackRunsInRegistrationOrderAndRemoveCallbackStopsIt(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+
+	var order []string
+	id1, err := client.AddCallback("highlight", func(ctx context.Context, msg *IRCMessage) error {
+		order = append(order, "first")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering first callback: %v", err)
+	}
+	if _, err := client.AddCallback("highlight", func(ctx context.Context, msg *IRCMessage) error {
+		order = append(order, "second")
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error registering second callback: %v", err)
+	}
+
+	if err := client.handleMessageSync(context.Background(), &IRCMessage{Type: "highlight"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"first", "second"}; len(order) != len(want) || order[0] != want[0] || order[1] != want[1] {
+		t.Fatalf("expected callbacks to run in registration order %v, got %v", want, order)
+	}
+
+	client.RemoveCallback(id1)
+	order = nil
+	if err := client.handleMessageSync(context.Background(), &IRCMessage{Type: "highlight"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"second"}; len(order) != len(want) || order[0] != want[0] {
+		t.Fatalf("expected only remaining callback to run, got %v", order)
+	}
+}
+
+func TestFilterChannelsMiddlewareAppliesToAnyChannelScopedEventType(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+	client.ignoredChannelSet = map[string]bool{"#ignored": true}
+
+	msg := &IRCMessage{Type: "joined_channel", Chan: "#ignored", Nick: "alice"}
+	keep, err := client.filterChannelsMiddleware(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keep {
+		t.Error("expected joined_channel event for an ignored channel to be dropped")
+	}
+
+	msg = &IRCMessage{Type: "oob_include"}
+	keep, err = client.filterChannelsMiddleware(context.Background(), msg)
+	if err != nil || !keep {
+		t.Errorf("expected channel-less event to pass through, got keep=%t err=%v", keep, err)
+	}
+}
+
+func TestHandleMessageSyncPersistsBufferMsg(t *testing.T) {
+	db, err := storage.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("could not create in-memory db: %v", err)
+	}
+	defer db.Close()
+
+	client := NewIRCCloudClient(db)
+	msg := &IRCMessage{Type: "buffer_msg", Chan: "#test", From: "alice", Msg: "hi there", EID: 1}
+
+	if err := client.handleMessageSync(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := client.getLastSeenEID(); got != 1 {
+		t.Errorf("expected lastSeenEID to advance to 1, got %d", got)
+	}
+}
@@ -3,14 +3,11 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"time"
 
 	"irccloud-watcher/internal/config"
-	"irccloud-watcher/internal/storage"
-	"irccloud-watcher/internal/utils"
 
 	"github.com/gorilla/websocket"
 )
@@ -36,18 +33,18 @@ func (c *IRCCloudClient) Run(channels, ignoredChannels []string, connConfig *con
 	for {
 		select {
 		case <-interrupt:
-			log.Println("🛑 Interrupt received, shutting down...")
+			c.logger.Infof("interrupt received, shutting down")
 			c.Close()
 			return
 		case <-c.ctx.Done():
-			log.Println("🛑 Context cancelled, shutting down...")
+			c.logger.Infof("context cancelled, shutting down")
 			return
 		default:
 			switch {
 			case c.getState() == StateConnected && c.conn != nil:
 				// Run the message loop until connection fails
 				if err := c.runMessageLoop(); err != nil {
-					log.Printf("❌ Message loop error: %v", err)
+					c.logger.Errorf("message loop error=%v", err)
 					c.setState(StateError)
 
 					// Close broken connection
@@ -57,11 +54,11 @@ func (c *IRCCloudClient) Run(channels, ignoredChannels []string, connConfig *con
 					}
 
 					// Attempt reconnection
-					log.Println("🔄 Attempting to reconnect...")
+					c.logger.Infof("attempting to reconnect")
 					if reconnectErr := c.connectWithRetry(); reconnectErr != nil {
-						log.Printf("❌ Reconnection failed: %v", reconnectErr)
+						c.logger.Errorf("reconnection failed error=%v", reconnectErr)
 						if c.retryCount >= c.connConfig.MaxRetryAttempts {
-							log.Println("❌ Max retry attempts reached, exiting...")
+							c.logger.Errorf("max retry attempts reached, exiting")
 							return
 						}
 					}
@@ -96,19 +93,36 @@ func (c *IRCCloudClient) runMessageLoop() error {
 	pingTicker := time.NewTicker(pingInterval)
 	defer pingTicker.Stop()
 
-	// Set up ping/pong handlers
+	// A read deadline of pingInterval+heartbeatInterval catches a half-open
+	// connection: if the upstream stops answering pings, ReadMessage starts
+	// returning a timeout error instead of blocking forever, so the loop
+	// below exits and Run reconnects. It's reset before every
+	// ReadMessage call and by every pong received, mirroring go-ircevent's
+	// deadline-per-read pattern.
+	readDeadline := pingInterval + heartbeatInterval
+	c.setLastPongTime(time.Now())
 	c.conn.SetPongHandler(func(string) error {
-		if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-			log.Println("🏓 Received pong")
-		}
-		return nil
+		c.logger.Debugf("received pong")
+		c.setLastPongTime(time.Now())
+		return c.conn.SetReadDeadline(time.Now().Add(readDeadline))
 	})
 
+	// pongTimeout bounds how long a ping may go unanswered before the
+	// connection is declared dead: if a live pong hasn't landed within
+	// PongTimeoutMultiplier*pingInterval, the loop below returns an error
+	// to force a reconnect rather than waiting on the read deadline alone.
+	pongTimeout := time.Duration(c.connConfig.PongTimeoutMultiplier) * pingInterval
+
 	// Message reading goroutine
 	done := make(chan error, 1)
 	go func() {
 		defer close(done)
 		for {
+			if err := c.conn.SetReadDeadline(time.Now().Add(readDeadline)); err != nil {
+				done <- fmt.Errorf("failed to set read deadline: %w", err)
+				return
+			}
+
 			_, message, err := c.conn.ReadMessage()
 			if err != nil {
 				done <- fmt.Errorf("read error: %w", err)
@@ -116,7 +130,7 @@ func (c *IRCCloudClient) runMessageLoop() error {
 			}
 
 			if err := c.processMessage(message); err != nil {
-				log.Printf("⚠️ Error processing message: %v", err)
+				c.logger.Warnf("error processing message error=%v", err)
 				// Continue processing other messages
 			}
 		}
@@ -134,6 +148,9 @@ func (c *IRCCloudClient) runMessageLoop() error {
 				return fmt.Errorf("heartbeat failed: %w", err)
 			}
 		case <-pingTicker.C:
+			if sincePong := time.Since(c.LastPongTime()); sincePong >= pongTimeout {
+				return fmt.Errorf("no pong received in %s (timeout %s)", sincePong, pongTimeout)
+			}
 			if err := c.sendPing(); err != nil {
 				return fmt.Errorf("ping failed: %w", err)
 			}
@@ -143,9 +160,8 @@ func (c *IRCCloudClient) runMessageLoop() error {
 
 // processMessage handles individual WebSocket messages
 func (c *IRCCloudClient) processMessage(message []byte) error {
-	// Print raw message if debug mode is enabled
 	if c.debugMode {
-		fmt.Printf("RAW: %s\n", string(message))
+		c.logger.Debugf("raw message=%s", string(message))
 	}
 
 	var ircMsg IRCMessage
@@ -153,80 +169,33 @@ func (c *IRCCloudClient) processMessage(message []byte) error {
 		return fmt.Errorf("unmarshal error: %w", err)
 	}
 
+	metricMessagesReceived.WithLabelValues(ircMsg.Type).Inc()
+
 	if ircMsg.Type == "oob_include" {
 		var oob OOBInclude
 		if err := json.Unmarshal(message, &oob); err != nil {
 			return fmt.Errorf("unmarshal oob error: %w", err)
 		}
-		log.Printf("🔍 Received oob_include with URL: %s", oob.URL)
+		c.logger.Infof("received oob_include url=%s", oob.URL)
 		if err := c.processBacklog(oob.URL); err != nil {
-			log.Printf("⚠️ Error processing backlog: %v", err)
+			c.logger.Warnf("error processing backlog error=%v", err)
 		}
 		return nil
 	}
 
-	// Accept message if not ignored and either no channels specified (accept all) or channel is in allowed list
-	if ircMsg.Type == "buffer_msg" && !c.ignoredChannelSet[ircMsg.Chan] && (len(c.channels) == 0 || c.channelSet[ircMsg.Chan]) {
-		// Check if we've seen this EID before (skip if duplicate)
-		if c.isEIDSeen(ircMsg.EID) {
-			if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-				log.Printf("🔄 Duplicate message filtered: EID=%d, Channel=%s", ircMsg.EID, ircMsg.Chan)
-			}
-			return nil
-		}
-
-		cleanedMsg := utils.CleanIRCMessage(ircMsg.Msg)
-
-		// Handle timestamp conversion - IRCCloud uses microseconds since Unix epoch
-		// Live messages often have timestamp 0, so we use current time as fallback
-		var msgTime time.Time
-		if ircMsg.Time > 0 {
-			// Convert from microseconds to seconds and nanoseconds
-			seconds := ircMsg.Time / 1000000
-			microseconds := ircMsg.Time % 1000000
-			nanoseconds := microseconds * 1000
-			msgTime = time.Unix(seconds, nanoseconds)
-		} else {
-			// Use current time for live messages (timestamp 0 is normal)
-			msgTime = time.Now()
-		}
-
-		if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-			log.Printf("🔍 Processing message: Channel=%s, From=%s, EID=%d, Time=%d, Converted=%s", ircMsg.Chan, ircMsg.From, ircMsg.EID, ircMsg.Time, msgTime.Format(time.RFC3339))
-		}
-
-		log.Printf("%s <%s> %s", ircMsg.Chan, ircMsg.From, cleanedMsg)
-
-		dbMsg := &storage.Message{
-			Channel:   ircMsg.Chan,
-			Timestamp: msgTime,
-			Sender:    ircMsg.From,
-			Message:   cleanedMsg,
-			Date:      msgTime.Format("2006-01-02"),
-			EID:       ircMsg.EID,
-		}
-
-		if err := c.db.InsertMessage(dbMsg); err != nil {
-			log.Printf("❌ Error inserting message into DB: %v", err)
-			return fmt.Errorf("error inserting message into DB: %w", err)
-		}
-
-		if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-			log.Printf("✅ Message stored successfully: EID=%d", ircMsg.EID)
-		}
+	return c.handleMessage(c.ctx, &ircMsg)
+}
 
-		// Fix: Use EID instead of Time for lastSeenEID tracking
-		if ircMsg.EID > c.lastSeenEID {
-			c.lastSeenEID = ircMsg.EID
-		}
-	} else if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-		// Debug why message was filtered out
-		log.Printf("🚫 Message filtered: Type=%s, Channel=%s, Ignored=%t, ChannelAllowed=%t",
-			ircMsg.Type, ircMsg.Chan, c.ignoredChannelSet[ircMsg.Chan],
-			(len(c.channels) == 0 || c.channelSet[ircMsg.Chan]))
+// ircTimestamp converts an IRCCloud time field (microseconds since the Unix
+// epoch) to a time.Time. Live messages often carry time 0, in which case we
+// fall back to the current time.
+func ircTimestamp(microseconds int64) time.Time {
+	if microseconds <= 0 {
+		return time.Now()
 	}
-
-	return nil
+	seconds := microseconds / 1000000
+	remainder := microseconds % 1000000
+	return time.Unix(seconds, remainder*1000)
 }
 
 // sendHeartbeat sends a heartbeat message to keep the connection alive
@@ -234,27 +203,74 @@ func (c *IRCCloudClient) sendHeartbeat() error {
 	heartbeat := map[string]any{
 		"_method":       "heartbeat",
 		"_reqid":        time.Now().Unix(),
-		"last_seen_eid": c.lastSeenEID,
+		"last_seen_eid": c.getLastSeenEID(),
 	}
 
-	if err := c.conn.WriteJSON(heartbeat); err != nil {
+	if err := c.waitForWrite(); err != nil {
 		return fmt.Errorf("failed to send heartbeat: %w", err)
 	}
 
-	if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-		log.Println("💓 Heartbeat sent")
+	if err := c.conn.WriteJSON(heartbeat); err != nil {
+		return fmt.Errorf("failed to send heartbeat: %w", err)
 	}
+
+	metricHeartbeatsSent.Inc()
+	c.logger.Debugf("heartbeat sent")
 	return nil
 }
 
 // sendPing sends a WebSocket ping frame
 func (c *IRCCloudClient) sendPing() error {
+	if err := c.waitForWrite(); err != nil {
+		return fmt.Errorf("failed to send ping: %w", err)
+	}
+
 	if err := c.conn.WriteMessage(websocket.PingMessage, []byte("ping")); err != nil {
 		return fmt.Errorf("failed to send ping: %w", err)
 	}
 
-	if os.Getenv("IRCCLOUD_DEBUG") == "true" {
-		log.Println("🏓 Ping sent")
+	metricPingsSent.Inc()
+	c.logger.Debugf("ping sent")
+	return nil
+}
+
+// broadcastMembershipEvent translates an IRCCloud joined_channel /
+// parted_channel event into the downstream JOIN/PART a bridge session
+// expects. ircMsg.Msg (the part message) has already been run through
+// cleanMiddleware by the time a handler sees it.
+func (c *IRCCloudClient) broadcastMembershipEvent(ircMsg IRCMessage) {
+	switch ircMsg.Type {
+	case "joined_channel":
+		c.bridge.BroadcastJoin(ircMsg.Nick, ircMsg.Chan)
+	case "parted_channel":
+		c.bridge.BroadcastPart(ircMsg.Nick, ircMsg.Chan, ircMsg.Msg)
+	}
+}
+
+// Say sends message to target (a channel or nick) via IRCCloud's "say" RPC
+// method over the upstream WebSocket, so it reaches IRCCloud the same way a
+// message typed in the official client would. It's the path a downstream
+// bridge session's PRIVMSG takes upstream.
+func (c *IRCCloudClient) Say(target, message string) error {
+	if c.conn == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	say := map[string]any{
+		"_method": "say",
+		"_reqid":  time.Now().Unix(),
+		"to":      target,
+		"msg":     message,
 	}
+
+	if err := c.waitForWrite(); err != nil {
+		return fmt.Errorf("failed to send say: %w", err)
+	}
+
+	if err := c.conn.WriteJSON(say); err != nil {
+		return fmt.Errorf("failed to send say: %w", err)
+	}
+
+	c.logger.Debugf("say sent target=%s", target)
 	return nil
 }
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus collectors for a running IRCCloudClient. These are package
+// globals (the usual client_golang pattern) since a process only ever runs
+// one client; promauto registers them with the default registry on init.
+var (
+	metricConnectionState = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "irccloud_watcher_connection_state",
+		Help: "Current WebSocket connection state, numbered to match api.ConnectionState's iota order (0=disconnected .. 5=circuit_open).",
+	})
+
+	metricMessagesIngested = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "irccloud_watcher_messages_ingested_total",
+		Help: "buffer_msg events stored into the message database, per channel.",
+	}, []string{"channel"})
+
+	metricBacklogMessagesProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "irccloud_watcher_backlog_messages_processed_total",
+		Help: "Messages replayed from an oob_include backlog fetch.",
+	})
+
+	metricHeartbeatsSent = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "irccloud_watcher_heartbeats_sent_total",
+		Help: "Heartbeats sent to report last_seen_eid to IRCCloud.",
+	})
+
+	metricPingsSent = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "irccloud_watcher_pings_sent_total",
+		Help: "WebSocket ping frames sent to keep the connection alive.",
+	})
+
+	metricReconnectsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "irccloud_watcher_reconnects_total",
+		Help: "Successful WebSocket reconnects after a dropped connection.",
+	})
+
+	metricAuthAttemptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "irccloud_watcher_auth_attempts_total",
+		Help: "IRCCloud authentication attempts, by result (success, failure).",
+	}, []string{"result"})
+
+	metricMessageProcessingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "irccloud_watcher_message_processing_duration_seconds",
+		Help:    "Time spent storing a message via db.InsertMessage.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	metricMessagesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "irccloud_watcher_messages_received_total",
+		Help: "WebSocket messages received from IRCCloud, per event type, before any filtering.",
+	}, []string{"type"})
+
+	metricMessagesIgnored = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "irccloud_watcher_messages_ignored_total",
+		Help: "Events dropped by the middleware chain, by reason (channel_filter, eid_duplicate, rate_limited).",
+	}, []string{"reason"})
+
+	metricWebsocketDialDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "irccloud_watcher_websocket_dial_duration_seconds",
+		Help:    "Time spent dialing the upstream WebSocket, including failed attempts.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	metricEIDCacheSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "irccloud_watcher_eid_cache_size",
+		Help: "Number of EIDs currently held in the deduplication cache.",
+	})
+
+	metricLastPongTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "irccloud_watcher_last_pong_timestamp_seconds",
+		Help: "Unix timestamp of the last pong received from the upstream WebSocket.",
+	})
+
+	metricLimiterWaitDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "irccloud_watcher_limiter_wait_duration_seconds",
+		Help:    "Time spent blocked waiting for a token-bucket limiter, by limiter name (write, reconnect, enrichment).",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"limiter"})
+)
+
+// limiterWaitWarnThreshold is how long a limiter.Wait call may block before
+// it's logged as a warning, on top of always being recorded in
+// metricLimiterWaitDuration. Long waits usually mean the configured rate is
+// too tight for how the client is actually being used.
+const limiterWaitWarnThreshold = time.Second
+
+// MetricsServer serves Prometheus metrics for a running IRCCloudClient.
+// Like bridge.Server, it's meant to be run via ListenAndServe in its own
+// goroutine from main.
+type MetricsServer struct {
+	addr string
+}
+
+// NewMetricsServer builds a MetricsServer listening on addr.
+func NewMetricsServer(addr string) *MetricsServer {
+	return &MetricsServer{addr: addr}
+}
+
+// ListenAndServe starts the metrics HTTP server. It blocks until the server
+// stops, so callers run it in a goroutine.
+func (m *MetricsServer) ListenAndServe() error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return http.ListenAndServe(m.addr, mux)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestMetricsServerServesPrometheusFormat(t *testing.T) {
+	metricConnectionState.Set(float64(StateConnected))
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("unexpected error fetching metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.Contains(ct, "text/plain") {
+		t.Errorf("expected a text/plain content type, got %q", ct)
+	}
+}
+
+func TestMetricsServerExposesObservabilityMetrics(t *testing.T) {
+	metricMessagesReceived.WithLabelValues("buffer_msg").Inc()
+	metricMessagesIgnored.WithLabelValues("eid_duplicate").Inc()
+	metricWebsocketDialDuration.Observe(0.1)
+	metricEIDCacheSize.Set(3)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("unexpected error fetching metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading body: %v", err)
+	}
+
+	for _, name := range []string{
+		"irccloud_watcher_messages_received_total",
+		"irccloud_watcher_messages_ignored_total",
+		"irccloud_watcher_websocket_dial_duration_seconds",
+		"irccloud_watcher_eid_cache_size",
+	} {
+		if !strings.Contains(string(body), name) {
+			t.Errorf("expected scrape output to contain %s", name)
+		}
+	}
+}
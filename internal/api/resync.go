@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Resync fills any gap left by a process restart or network drop: it looks
+// up the highest EID persisted per channel and, for each one, requests
+// IRCCloud's history for that channel starting right after it, replaying it
+// the same way an oob_include backlog fetch is processed. main.go calls it
+// once Connect succeeds and before the client enters its normal event loop,
+// so stored history is caught up before any live message arrives.
+//
+// Resync is a no-op when the client isn't backed by the sqlite storage.DB,
+// since GetLastEIDPerChannel has no meaning against the fs/memory drivers.
+func (c *IRCCloudClient) Resync(ctx context.Context) error {
+	sqliteDB, ok := c.sqliteDB()
+	if !ok {
+		return nil
+	}
+
+	lastEIDs, err := sqliteDB.GetLastEIDPerChannel()
+	if err != nil {
+		return fmt.Errorf("could not determine last seen eid per channel: %w", err)
+	}
+	if len(lastEIDs) == 0 {
+		c.logger.Infof("resync: no stored history, skipping")
+		return nil
+	}
+
+	var maxEID int64
+	for channel, eid := range lastEIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if eid > maxEID {
+			maxEID = eid
+		}
+
+		if err := c.resyncChannel(channel, eid); err != nil {
+			c.logger.Warnf("resync: channel history fetch failed channel=%s since_eid=%d error=%v", channel, eid, err)
+		}
+	}
+
+	c.updateLastSeenEID(maxEID)
+	return nil
+}
+
+// resyncChannel requests channel's history since sinceEID and replays it,
+// filling any gap left while the client was offline.
+func (c *IRCCloudClient) resyncChannel(channel string, sinceEID int64) error {
+	historyURL := fmt.Sprintf("/chat/backlog/?cname=%s&since_id=%d", url.QueryEscape(channel), sinceEID)
+
+	count, err := c.streamBacklogMessages(historyURL)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Infof("resync: replayed channel history channel=%s since_eid=%d count=%d", channel, sinceEID, count)
+	return nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/storage"
+)
+
+func TestResyncNoopsWithoutSqliteDB(t *testing.T) {
+	client := NewIRCCloudClient(nil)
+
+	if err := client.Resync(context.Background()); err != nil {
+		t.Fatalf("expected Resync to no-op without a sqlite-backed store, got error: %v", err)
+	}
+}
+
+func TestResyncNoopsWithNoStoredHistory(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "resync-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp database: %v", err)
+	}
+	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	db, err := storage.NewDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	client := NewIRCCloudClient(db)
+
+	if err := client.Resync(context.Background()); err != nil {
+		t.Fatalf("expected Resync to no-op with no stored history, got error: %v", err)
+	}
+}
+
+func TestResyncFetchesAndReplaysChannelHistorySinceLastEID(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "resync-*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp database: %v", err)
+	}
+	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	db, err := storage.NewDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	if err := db.InsertMessage(&storage.Message{
+		Channel:   "#test",
+		Timestamp: now,
+		Sender:    "user1",
+		Message:   "hello",
+		Date:      now.Format("2006-01-02"),
+		EID:       100,
+	}); err != nil {
+		t.Fatalf("failed to seed stored message: %v", err)
+	}
+
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]IRCMessage{
+			{Type: "buffer_msg", Chan: "#test", From: "user2", Msg: "catch up", Time: now.Unix(), EID: 200},
+		})
+	}))
+	defer server.Close()
+
+	client := NewIRCCloudClient(db)
+	client.apiHost = server.URL
+	client.authHeaderName = "Authorization"
+	client.authHeaderValue = "session test-session"
+	client.SetConnectionConfig(&config.ConnectionConfig{
+		ChannelIngestRateLimit: 100,
+		ChannelIngestBurst:     100,
+	})
+
+	if err := client.Resync(context.Background()); err != nil {
+		t.Fatalf("Resync returned error: %v", err)
+	}
+
+	if gotQuery.Get("cname") != "#test" {
+		t.Errorf("expected cname=#test, got %q", gotQuery.Get("cname"))
+	}
+	if gotQuery.Get("since_id") != "100" {
+		t.Errorf("expected since_id=100, got %q", gotQuery.Get("since_id"))
+	}
+
+	eid, err := db.GetLastEID("#test")
+	if err != nil {
+		t.Fatalf("GetLastEID returned error: %v", err)
+	}
+	if eid != 200 {
+		t.Errorf("expected replayed history to persist eid 200, got %d", eid)
+	}
+
+	if client.getLastSeenEID() != 200 {
+		t.Errorf("expected lastSeenEID to advance to 200, got %d", client.getLastSeenEID())
+	}
+}
@@ -0,0 +1,42 @@
+// Package auth provides pluggable ways for IRCCloudClient to obtain a
+// session: IRCCloud's own email/password + auth-formtoken flow, an OAuth2
+// grant against a configurable token endpoint, a pre-issued IRCCloud
+// session token, or a signed JWT — for deployments where storing raw
+// credentials is undesirable (a systemd credential store, a Kubernetes
+// secret containing only a short-lived token).
+package auth
+
+import "context"
+
+// Credentials is what a successful Authenticator run yields: enough for
+// IRCCloudClient to build its WebSocket URL and authenticate the dial and
+// any subsequent backlog request, without needing to know how they were
+// obtained.
+type Credentials struct {
+	// APIHost, WebSocketHost, and WebSocketPath mirror the fields
+	// IRCCloud's own login response returns, used to build the backlog and
+	// stream URLs.
+	APIHost       string
+	WebSocketHost string
+	WebSocketPath string
+
+	// HeaderName/HeaderValue are added to the WebSocket dial and backlog
+	// request to authenticate the session, e.g. "Cookie": "session=..."
+	// for form-token auth, or "Authorization": "Bearer ..." for OAuth2.
+	HeaderName  string
+	HeaderValue string
+}
+
+// Authenticator exchanges configured credentials for a session
+// IRCCloudClient can use to open its upstream WebSocket connection.
+// Implementations: FormTokenAuthenticator (IRCCloud's email/password +
+// auth-formtoken flow, the default), OAuth2Authenticator (a
+// client-credentials or password grant against a configurable token
+// endpoint), TokenAuthenticator (a pre-issued IRCCloud session token), and
+// JWTAuthenticator (a signed JWT verified locally and presented upstream as
+// a bearer token).
+type Authenticator interface {
+	// Authenticate returns fresh Credentials, reusing a cached session or
+	// token where the implementation supports it.
+	Authenticate(ctx context.Context) (*Credentials, error)
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"time"
+)
+
+const defaultUserAgent = "irccloud-watcher/0.1.0"
+
+// formTokenResponse is the response from the auth-formtoken endpoint.
+type formTokenResponse struct {
+	Success bool   `json:"success"`
+	Token   string `json:"token"`
+}
+
+// loginResponse is the response from the IRCCloud login endpoint.
+type loginResponse struct {
+	Success       bool   `json:"success"`
+	Message       string `json:"message"`
+	Session       string `json:"session"`
+	APIHost       string `json:"api_host"`
+	WebSocketHost string `json:"websocket_host"`
+	WebSocketPath string `json:"websocket_path"`
+}
+
+// FormTokenAuthenticator performs IRCCloud's native two-step login: fetch an
+// auth-formtoken, then POST it alongside email/password to get back a
+// session cookie. This is the default authenticator and what Connect's
+// email/password arguments already drive.
+type FormTokenAuthenticator struct {
+	Email    string
+	Password string
+
+	// HTTPClient is used for both requests if set; otherwise a client with
+	// a cookie jar and a 10s timeout is created per Authenticate call,
+	// matching the original behavior.
+	HTTPClient *http.Client
+}
+
+// Authenticate runs the auth-formtoken + login flow and returns the
+// resulting session as Credentials with a Cookie header.
+func (a *FormTokenAuthenticator) Authenticate(ctx context.Context) (*Credentials, error) {
+	client := a.HTTPClient
+	if client == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+		}
+		client = &http.Client{Timeout: 10 * time.Second, Jar: jar}
+	}
+
+	tokenURL := "https://www.irccloud.com/chat/auth-formtoken"
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("could not create token request: %w", err)
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Length", "0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not perform token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed with status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read token response body: %w", err)
+	}
+
+	var tokenResp formTokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("could not parse token response: %w", err)
+	}
+	if !tokenResp.Success {
+		return nil, fmt.Errorf("token request unsuccessful")
+	}
+
+	loginURL := "https://www.irccloud.com/chat/login"
+	data := url.Values{}
+	data.Set("email", a.Email)
+	data.Set("password", a.Password)
+	data.Set("token", tokenResp.Token)
+
+	req, err = http.NewRequestWithContext(ctx, "POST", loginURL, bytes.NewBufferString(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("could not create login request: %w", err)
+	}
+	req.Header.Set("X-Auth-Formtoken", tokenResp.Token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", defaultUserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err = client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not perform login request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("login failed with status: %s", resp.Status)
+	}
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read login response body: %w", err)
+	}
+
+	var login loginResponse
+	if err := json.Unmarshal(body, &login); err != nil {
+		return nil, fmt.Errorf("could not parse login response: %w", err)
+	}
+	if !login.Success {
+		if login.Message == "" {
+			login.Message = "authentication failed"
+		}
+		return nil, fmt.Errorf("login unsuccessful: %s", login.Message)
+	}
+
+	return &Credentials{
+		APIHost:       login.APIHost,
+		WebSocketHost: login.WebSocketHost,
+		WebSocketPath: login.WebSocketPath,
+		HeaderName:    "Cookie",
+		HeaderValue:   "session=" + login.Session,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig configures a JWTAuthenticator.
+type JWTConfig struct {
+	// Token is the signed JWT, expected to carry "email", "uid", and "exp"
+	// claims.
+	Token string
+
+	// Key verifies Token's signature. Only HMAC (HS256/384/512) signing
+	// methods are supported.
+	Key string
+
+	// APIHost/WebSocketHost/WebSocketPath are returned verbatim as part of
+	// the resulting Credentials, since a JWT carries no login response to
+	// source them from.
+	APIHost       string
+	WebSocketHost string
+	WebSocketPath string
+}
+
+// jwtClaims is the expected claim set of a JWTConfig.Token: the registered
+// claims (notably "exp", checked by jwt.ParseWithClaims) plus the "email"
+// and "uid" identifying the IRCCloud account the token was issued for.
+type jwtClaims struct {
+	Email string `json:"email"`
+	UID   int    `json:"uid"`
+	jwt.RegisteredClaims
+}
+
+// JWTAuthenticator authenticates with a pre-issued, locally-verified JWT
+// rather than performing IRCCloud's own login flow. The verified token
+// itself is presented upstream as a bearer credential, mirroring
+// OAuth2Authenticator's bearer-token Credentials. This suits deployments
+// where storing raw credentials is undesirable, e.g. a systemd credential
+// store or a Kubernetes secret containing only a short-lived token.
+type JWTAuthenticator struct {
+	config JWTConfig
+	claims *jwtClaims
+}
+
+// NewJWTAuthenticator creates a JWTAuthenticator from cfg, verifying
+// cfg.Token up front so a malformed or expired token is rejected at
+// startup rather than on the first connection attempt.
+func NewJWTAuthenticator(cfg JWTConfig) (*JWTAuthenticator, error) {
+	claims, err := verifyJWT(cfg.Token, cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &JWTAuthenticator{config: cfg, claims: claims}, nil
+}
+
+// Authenticate re-verifies the configured JWT (so an authenticator kept
+// alive past its token's expiry fails loudly instead of connecting with a
+// stale credential) and returns Credentials presenting it as a bearer
+// token.
+func (a *JWTAuthenticator) Authenticate(ctx context.Context) (*Credentials, error) {
+	claims, err := verifyJWT(a.config.Token, a.config.Key)
+	if err != nil {
+		return nil, err
+	}
+	a.claims = claims
+
+	return &Credentials{
+		APIHost:       a.config.APIHost,
+		WebSocketHost: a.config.WebSocketHost,
+		WebSocketPath: a.config.WebSocketPath,
+		HeaderName:    "Authorization",
+		HeaderValue:   "Bearer " + a.config.Token,
+	}, nil
+}
+
+// verifyJWT parses and validates tokenString against key, requiring an
+// HMAC signing method (HS256/384/512) so a token signed with "none" or an
+// asymmetric algorithm the caller didn't ask for can't slip through.
+func verifyJWT(tokenString, key string) (*jwtClaims, error) {
+	claims := &jwtClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(key), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not verify jwt: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signTestJWT(t *testing.T, key string, claims jwtClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(key))
+	if err != nil {
+		t.Fatalf("failed to sign test jwt: %v", err)
+	}
+	return signed
+}
+
+func TestJWTAuthenticatorReturnsBearerCredentials(t *testing.T) {
+	tokenString := signTestJWT(t, "secret", jwtClaims{
+		Email: "alice@example.com",
+		UID:   42,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+
+	a, err := NewJWTAuthenticator(JWTConfig{
+		Token:         tokenString,
+		Key:           "secret",
+		APIHost:       "https://api.example.com",
+		WebSocketHost: "ws.example.com",
+		WebSocketPath: "/stream",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator failed: %v", err)
+	}
+
+	creds, err := a.Authenticate(context.Background())
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if creds.HeaderName != "Authorization" || creds.HeaderValue != "Bearer "+tokenString {
+		t.Errorf("unexpected header %s=%s", creds.HeaderName, creds.HeaderValue)
+	}
+	if creds.APIHost != "https://api.example.com" || creds.WebSocketHost != "ws.example.com" || creds.WebSocketPath != "/stream" {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+}
+
+func TestNewJWTAuthenticatorRejectsExpiredToken(t *testing.T) {
+	tokenString := signTestJWT(t, "secret", jwtClaims{
+		Email: "alice@example.com",
+		UID:   42,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	})
+
+	if _, err := NewJWTAuthenticator(JWTConfig{Token: tokenString, Key: "secret"}); err == nil {
+		t.Fatal("expected an error for an expired token, got nil")
+	}
+}
+
+func TestNewJWTAuthenticatorRejectsWrongKey(t *testing.T) {
+	tokenString := signTestJWT(t, "secret", jwtClaims{
+		Email: "alice@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+
+	if _, err := NewJWTAuthenticator(JWTConfig{Token: tokenString, Key: "wrong-secret"}); err == nil {
+		t.Fatal("expected an error for a token signed with a different key, got nil")
+	}
+}
+
+func TestJWTAuthenticatorReauthenticateRejectsTokenThatExpiredSinceConstruction(t *testing.T) {
+	tokenString := signTestJWT(t, "secret", jwtClaims{
+		Email: "alice@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(2 * time.Second)),
+		},
+	})
+
+	a, err := NewJWTAuthenticator(JWTConfig{Token: tokenString, Key: "secret"})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator failed: %v", err)
+	}
+
+	time.Sleep(3 * time.Second)
+
+	if _, err := a.Authenticate(context.Background()); err == nil {
+		t.Fatal("expected Authenticate to reject a token that expired since construction")
+	}
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryLeeway is subtracted from a token's reported lifetime so a
+// near-expiry token isn't handed out only to be rejected by the server a
+// moment later.
+const tokenExpiryLeeway = 30 * time.Second
+
+// OAuth2Config configures an OAuth2Authenticator.
+type OAuth2Config struct {
+	// TokenURL is the OAuth2 token endpoint to request (and refresh)
+	// tokens from.
+	TokenURL string
+
+	// ClientID/ClientSecret authenticate the watcher itself to the token
+	// endpoint. Required for "client_credentials"; optional (but commonly
+	// set) for "password".
+	ClientID     string
+	ClientSecret string
+
+	// Username/Password are only used for the "password" grant type.
+	Username string
+	Password string
+
+	// GrantType selects the grant: "client_credentials" or "password".
+	// Defaults to "password" if empty.
+	GrantType string
+
+	// Scope, if set, is requested alongside the grant.
+	Scope string
+
+	// APIHost/WebSocketHost/WebSocketPath are returned verbatim as part of
+	// the resulting Credentials, since an OAuth2 gateway doesn't carry
+	// IRCCloud's own login response to source them from.
+	APIHost       string
+	WebSocketHost string
+	WebSocketPath string
+
+	// HTTPClient is used for token requests if set; otherwise a client
+	// with a 10s timeout is used.
+	HTTPClient *http.Client
+}
+
+// oauth2TokenResponse is the standard RFC 6749 token endpoint response.
+type oauth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// OAuth2Authenticator authenticates via an OAuth2 client-credentials or
+// password grant against a configurable token endpoint, for deployments
+// that front IRCCloud (or a compatible replay proxy) with an OAuth2
+// gateway rather than shipping raw passwords in config. The bearer token is
+// cached and reused across Authenticate calls until it's close to expiry,
+// at which point it's refreshed (via the refresh token, if the endpoint
+// issued one, otherwise by repeating the original grant).
+type OAuth2Authenticator struct {
+	config OAuth2Config
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+}
+
+// NewOAuth2Authenticator creates an OAuth2Authenticator from cfg.
+func NewOAuth2Authenticator(cfg OAuth2Config) *OAuth2Authenticator {
+	if cfg.GrantType == "" {
+		cfg.GrantType = "password"
+	}
+	return &OAuth2Authenticator{config: cfg}
+}
+
+// Authenticate returns Credentials carrying a bearer token, reusing the
+// cached token if it's not close to expiry and requesting a fresh one
+// (refreshing if possible) otherwise.
+func (a *OAuth2Authenticator) Authenticate(ctx context.Context) (*Credentials, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.accessToken == "" || time.Now().After(a.expiresAt) {
+		if err := a.fetchToken(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Credentials{
+		APIHost:       a.config.APIHost,
+		WebSocketHost: a.config.WebSocketHost,
+		WebSocketPath: a.config.WebSocketPath,
+		HeaderName:    "Authorization",
+		HeaderValue:   "Bearer " + a.accessToken,
+	}, nil
+}
+
+// fetchToken requests a new token, refreshing the cached one if a refresh
+// token is available, or performing the configured grant from scratch
+// otherwise. Caller must hold a.mu.
+func (a *OAuth2Authenticator) fetchToken(ctx context.Context) error {
+	data := url.Values{}
+	if a.refreshToken != "" {
+		data.Set("grant_type", "refresh_token")
+		data.Set("refresh_token", a.refreshToken)
+	} else {
+		switch a.config.GrantType {
+		case "client_credentials":
+			data.Set("grant_type", "client_credentials")
+		default:
+			data.Set("grant_type", "password")
+			data.Set("username", a.config.Username)
+			data.Set("password", a.config.Password)
+		}
+	}
+	if a.config.ClientID != "" {
+		data.Set("client_id", a.config.ClientID)
+	}
+	if a.config.ClientSecret != "" {
+		data.Set("client_secret", a.config.ClientSecret)
+	}
+	if a.config.Scope != "" {
+		data.Set("scope", a.config.Scope)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.config.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("could not create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	client := a.config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not perform token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read token response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// A refresh that the endpoint rejects (expired/revoked refresh
+		// token) falls back to the original grant on the next call.
+		a.refreshToken = ""
+		return fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp oauth2TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return fmt.Errorf("could not parse token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return fmt.Errorf("token response did not include an access_token")
+	}
+
+	a.accessToken = tokenResp.AccessToken
+	if tokenResp.RefreshToken != "" {
+		a.refreshToken = tokenResp.RefreshToken
+	}
+	if tokenResp.ExpiresIn > 0 {
+		a.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn)*time.Second - tokenExpiryLeeway)
+	} else {
+		// No expires_in means the endpoint isn't telling us, so re-request
+		// on every Authenticate call rather than caching indefinitely.
+		a.expiresAt = time.Now()
+	}
+
+	return nil
+}
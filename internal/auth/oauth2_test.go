@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOAuth2AuthenticatorPasswordGrantReturnsBearerCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if got := r.Form.Get("grant_type"); got != "password" {
+			t.Errorf("expected grant_type=password, got %s", got)
+		}
+		if got := r.Form.Get("username"); got != "alice" {
+			t.Errorf("expected username=alice, got %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"tok-1","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	a := NewOAuth2Authenticator(OAuth2Config{
+		TokenURL:      server.URL,
+		Username:      "alice",
+		Password:      "hunter2",
+		APIHost:       "https://api.example.com",
+		WebSocketHost: "ws.example.com",
+		WebSocketPath: "/stream",
+	})
+
+	creds, err := a.Authenticate(context.Background())
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if creds.HeaderName != "Authorization" || creds.HeaderValue != "Bearer tok-1" {
+		t.Errorf("unexpected header %s=%s", creds.HeaderName, creds.HeaderValue)
+	}
+	if creds.APIHost != "https://api.example.com" || creds.WebSocketHost != "ws.example.com" || creds.WebSocketPath != "/stream" {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+}
+
+func TestOAuth2AuthenticatorReusesCachedTokenUntilExpiry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"tok-1","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	a := NewOAuth2Authenticator(OAuth2Config{TokenURL: server.URL, GrantType: "client_credentials"})
+
+	for i := 0; i < 3; i++ {
+		if _, err := a.Authenticate(context.Background()); err != nil {
+			t.Fatalf("Authenticate failed: %v", err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected exactly 1 token request, got %d", requests)
+	}
+}
+
+func TestOAuth2AuthenticatorRefreshesWithRefreshToken(t *testing.T) {
+	var grantTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		grantTypes = append(grantTypes, r.Form.Get("grant_type"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"tok-1","refresh_token":"refresh-1","expires_in":0}`))
+	}))
+	defer server.Close()
+
+	a := NewOAuth2Authenticator(OAuth2Config{TokenURL: server.URL, GrantType: "client_credentials"})
+
+	if _, err := a.Authenticate(context.Background()); err != nil {
+		t.Fatalf("first Authenticate failed: %v", err)
+	}
+	if _, err := a.Authenticate(context.Background()); err != nil {
+		t.Fatalf("second Authenticate failed: %v", err)
+	}
+
+	if len(grantTypes) != 2 || grantTypes[0] != "client_credentials" || grantTypes[1] != "refresh_token" {
+		t.Errorf("expected [client_credentials refresh_token], got %v", grantTypes)
+	}
+}
+
+func TestOAuth2AuthenticatorPropagatesTokenEndpointError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	defer server.Close()
+
+	a := NewOAuth2Authenticator(OAuth2Config{TokenURL: server.URL, GrantType: "client_credentials"})
+
+	if _, err := a.Authenticate(context.Background()); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+// sanity check that the test server's ParseForm exercises URL-encoded body
+// the same way the authenticator actually sends it.
+func TestOAuth2TokenRequestIsFormEncoded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("expected form-encoded content type, got %s", ct)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"tok-1","expires_in":60}`))
+	}))
+	defer server.Close()
+
+	a := NewOAuth2Authenticator(OAuth2Config{TokenURL: server.URL, GrantType: "client_credentials"})
+	if _, err := a.Authenticate(context.Background()); err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// sessionCheckURL is a lightweight authenticated endpoint used to validate
+// a pre-issued session token before it's handed to IRCCloudClient, so a
+// stale or revoked token fails fast with a clear error instead of surfacing
+// as an opaque WebSocket handshake rejection later.
+const sessionCheckURL = "https://www.irccloud.com/chat/session"
+
+// TokenConfig configures a TokenAuthenticator.
+type TokenConfig struct {
+	// Session is a pre-issued IRCCloud session token (the same value the
+	// form-token login flow would otherwise have to obtain), presented
+	// upstream as the session cookie.
+	Session string
+
+	// APIHost/WebSocketHost/WebSocketPath are returned verbatim as part of
+	// the resulting Credentials, since there is no login response to
+	// source them from.
+	APIHost       string
+	WebSocketHost string
+	WebSocketPath string
+
+	// SessionCheckURL overrides sessionCheckURL, mainly for tests.
+	SessionCheckURL string
+
+	// HTTPClient is used to validate Session against SessionCheckURL if
+	// set; otherwise a client with a 10s timeout is used.
+	HTTPClient *http.Client
+}
+
+// TokenAuthenticator authenticates with a pre-issued IRCCloud session
+// token, skipping the auth-formtoken + login round-trip entirely. This
+// suits deployments where storing raw credentials is undesirable, e.g. a
+// systemd credential store or a Kubernetes secret containing only a
+// short-lived token.
+type TokenAuthenticator struct {
+	config TokenConfig
+}
+
+// NewTokenAuthenticator creates a TokenAuthenticator from cfg.
+func NewTokenAuthenticator(cfg TokenConfig) *TokenAuthenticator {
+	return &TokenAuthenticator{config: cfg}
+}
+
+// Authenticate validates the configured session token against IRCCloud and
+// returns Credentials carrying it as a Cookie header, unchanged for the
+// lifetime of the authenticator.
+func (a *TokenAuthenticator) Authenticate(ctx context.Context) (*Credentials, error) {
+	if err := a.validateSession(ctx); err != nil {
+		return nil, err
+	}
+
+	return &Credentials{
+		APIHost:       a.config.APIHost,
+		WebSocketHost: a.config.WebSocketHost,
+		WebSocketPath: a.config.WebSocketPath,
+		HeaderName:    "Cookie",
+		HeaderValue:   "session=" + a.config.Session,
+	}, nil
+}
+
+// validateSession confirms the token is still accepted by IRCCloud by
+// hitting sessionCheckURL with it, refusing to proceed on a 401 rather than
+// letting a revoked token fail later as an opaque WebSocket rejection.
+func (a *TokenAuthenticator) validateSession(ctx context.Context) error {
+	checkURL := a.config.SessionCheckURL
+	if checkURL == "" {
+		checkURL = sessionCheckURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", checkURL, nil)
+	if err != nil {
+		return fmt.Errorf("could not create session check request: %w", err)
+	}
+	req.Header.Set("Cookie", "session="+a.config.Session)
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	client := a.config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not perform session check request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("session token rejected by IRCCloud: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenAuthenticatorReturnsCookieCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Cookie"); got != "session=sess-123" {
+			t.Errorf("expected session cookie, got %s", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	a := NewTokenAuthenticator(TokenConfig{
+		Session:         "sess-123",
+		APIHost:         "https://api.example.com",
+		WebSocketHost:   "ws.example.com",
+		WebSocketPath:   "/stream",
+		HTTPClient:      server.Client(),
+		SessionCheckURL: server.URL,
+	})
+
+	creds, err := a.Authenticate(context.Background())
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if creds.HeaderName != "Cookie" || creds.HeaderValue != "session=sess-123" {
+		t.Errorf("unexpected header %s=%s", creds.HeaderName, creds.HeaderValue)
+	}
+	if creds.APIHost != "https://api.example.com" || creds.WebSocketHost != "ws.example.com" || creds.WebSocketPath != "/stream" {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+}
+
+func TestTokenAuthenticatorReturnsSameSessionAcrossCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	a := NewTokenAuthenticator(TokenConfig{Session: "sess-123", HTTPClient: server.Client(), SessionCheckURL: server.URL})
+
+	for i := 0; i < 3; i++ {
+		creds, err := a.Authenticate(context.Background())
+		if err != nil {
+			t.Fatalf("Authenticate failed: %v", err)
+		}
+		if creds.HeaderValue != "session=sess-123" {
+			t.Errorf("expected stable session credential, got %s", creds.HeaderValue)
+		}
+	}
+}
+
+func TestTokenAuthenticatorRefusesOn401(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	a := NewTokenAuthenticator(TokenConfig{Session: "revoked-session", HTTPClient: server.Client(), SessionCheckURL: server.URL})
+
+	if _, err := a.Authenticate(context.Background()); err == nil {
+		t.Fatal("expected an error for a 401 session check response, got nil")
+	}
+}
+
+func TestTokenAuthenticatorAllowsNon401ErrorStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	a := NewTokenAuthenticator(TokenConfig{Session: "sess-123", HTTPClient: server.Client(), SessionCheckURL: server.URL})
+
+	if _, err := a.Authenticate(context.Background()); err != nil {
+		t.Fatalf("expected a 500 session check response not to be treated as an invalid token, got: %v", err)
+	}
+}
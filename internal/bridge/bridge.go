@@ -0,0 +1,436 @@
+// Package bridge exposes a downstream IRC gateway, modeled loosely on
+// soju's ircConn abstraction: it listens on a TCP (or TLS) port and speaks
+// the IRC client protocol to local clients (HexChat, weechat, ...). This
+// turns irccloud-watcher into a bouncer, with IRCCloudClient acting as the
+// single upstream and every accepted connection becoming a downstream
+// Session. Upstream buffer_msg/join/part events are fanned out to every
+// authenticated session as PRIVMSG/NOTICE/JOIN/PART, and a downstream
+// PRIVMSG is relayed upstream via the Upstream interface.
+package bridge
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"irccloud-watcher/internal/config"
+)
+
+// ioTimeout bounds how long a downstream session may block on a single
+// read or write, mirroring soju's per-connection SetReadDeadline /
+// SetWriteDeadline so one stuck client can't wedge the dispatcher.
+const ioTimeout = 5 * time.Minute
+
+// serverName is used as the IRC server prefix in numeric replies.
+const serverName = "irccloud-watcher"
+
+// Upstream is the subset of IRCCloudClient the bridge needs: mapping a
+// downstream SASL PLAIN handshake onto the upstream IRCCloud login, and
+// relaying a downstream PRIVMSG upstream over the same WebSocket.
+type Upstream interface {
+	// Authenticate reports whether user/pass match the credentials used
+	// for the upstream IRCCloud connection.
+	Authenticate(user, pass string) bool
+	// Say sends message to target (a channel or nick) upstream via
+	// IRCCloud's "say" RPC method.
+	Say(target, message string) error
+}
+
+// Server listens for downstream IRC client connections and fans out
+// upstream traffic to every authenticated session.
+type Server struct {
+	cfg      config.BridgeConfig
+	upstream Upstream
+	listener net.Listener
+
+	mu       sync.RWMutex
+	sessions map[*session]bool
+}
+
+// NewServer creates a bridge Server that authenticates and relays messages
+// through upstream.
+func NewServer(cfg config.BridgeConfig, upstream Upstream) *Server {
+	return &Server{
+		cfg:      cfg,
+		upstream: upstream,
+		sessions: make(map[*session]bool),
+	}
+}
+
+// ListenAndServe opens the configured listener and accepts downstream
+// connections until the listener is closed, dispatching each one to its
+// own goroutine. It blocks and returns the reason the listener stopped.
+func (s *Server) ListenAndServe() error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	log.Printf("🌉 IRC bridge listening on %s", s.cfg.ListenAddr)
+
+	for {
+		conn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			return fmt.Errorf("bridge accept failed: %w", acceptErr)
+		}
+
+		sess := s.newSession(conn)
+		go sess.run()
+	}
+}
+
+// listen opens a plain or TLS listener depending on whether tls_cert/key
+// are configured.
+func (s *Server) listen() (net.Listener, error) {
+	if s.cfg.TLSCert == "" {
+		listener, err := net.Listen("tcp", s.cfg.ListenAddr)
+		if err != nil {
+			return nil, fmt.Errorf("could not listen on %s: %w", s.cfg.ListenAddr, err)
+		}
+		return listener, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.cfg.TLSCert, s.cfg.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not load bridge TLS certificate: %w", err)
+	}
+
+	listener, err := tls.Listen("tcp", s.cfg.ListenAddr, &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on %s: %w", s.cfg.ListenAddr, err)
+	}
+	return listener, nil
+}
+
+// Close stops accepting new downstream connections. Sessions already
+// running are left to notice the closed upstream on their next write.
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Close()
+}
+
+// addSession registers sess so it receives fanned-out broadcasts.
+func (s *Server) addSession(sess *session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sess] = true
+}
+
+// removeSession unregisters sess, e.g. once its connection closes.
+func (s *Server) removeSession(sess *session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sess)
+}
+
+// BroadcastMessage fans out an upstream PRIVMSG (or NOTICE) to every
+// registered, authenticated session.
+func (s *Server) BroadcastMessage(from, target, text string, notice bool) {
+	cmd := "PRIVMSG"
+	if notice {
+		cmd = "NOTICE"
+	}
+	s.broadcast(fmt.Sprintf(":%s %s %s :%s", from, cmd, target, text))
+}
+
+// BroadcastJoin fans out an upstream channel join.
+func (s *Server) BroadcastJoin(who, channel string) {
+	s.broadcast(fmt.Sprintf(":%s JOIN %s", who, channel))
+}
+
+// BroadcastPart fans out an upstream channel part.
+func (s *Server) BroadcastPart(who, channel, reason string) {
+	s.broadcast(fmt.Sprintf(":%s PART %s :%s", who, channel, reason))
+}
+
+// broadcast sends line to every registered, authenticated session.
+func (s *Server) broadcast(line string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for sess := range s.sessions {
+		if !sess.authed {
+			continue
+		}
+		sess.send(line)
+	}
+}
+
+// session is a single accepted downstream connection: one local IRC client
+// attached to the bouncer.
+type session struct {
+	server *Server
+	conn   net.Conn
+	reader *bufio.Reader
+	outbox chan string
+	// writeDone closes once writePump has drained outbox, so close() can
+	// wait for any queued frame (a rejection numeric, say) to actually
+	// reach the client before the connection is torn down.
+	writeDone chan struct{}
+
+	nick           string
+	user           string
+	pass           string
+	authed         bool
+	saslAuthed     bool
+	capNegotiating bool
+}
+
+// newSession wraps conn in a session registered with the server, but not
+// yet added to its broadcast list (that happens once authentication
+// completes).
+func (s *Server) newSession(conn net.Conn) *session {
+	return &session{
+		server:    s,
+		conn:      conn,
+		reader:    bufio.NewReader(conn),
+		outbox:    make(chan string, 64),
+		writeDone: make(chan struct{}),
+	}
+}
+
+// run drives a session's write pump and read loop until the connection
+// closes.
+func (sess *session) run() {
+	defer sess.close()
+
+	go sess.writePump()
+
+	for {
+		if err := sess.conn.SetReadDeadline(time.Now().Add(ioTimeout)); err != nil {
+			return
+		}
+
+		line, err := sess.reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			continue
+		}
+
+		if err := sess.handleLine(line); err != nil {
+			log.Printf("⚠️ bridge: %v", err)
+			return
+		}
+	}
+}
+
+// writePump drains outbox to the connection, applying a write deadline to
+// each frame so a slow downstream client can't block the dispatcher
+// indefinitely.
+func (sess *session) writePump() {
+	defer close(sess.writeDone)
+	for line := range sess.outbox {
+		if err := sess.conn.SetWriteDeadline(time.Now().Add(ioTimeout)); err != nil {
+			return
+		}
+		if _, err := sess.conn.Write([]byte(line + "\r\n")); err != nil {
+			return
+		}
+	}
+}
+
+// send queues line for delivery to this session without blocking the
+// caller; a session that can't keep up drops the frame rather than stall
+// the broadcast to every other session.
+func (sess *session) send(line string) {
+	select {
+	case sess.outbox <- line:
+	default:
+		log.Printf("⚠️ bridge: dropping frame for slow downstream %s", sess.nick)
+	}
+}
+
+// close tears the session down and unregisters it from the server, waiting
+// for writePump to flush any already-queued frame (e.g. a rejection
+// numeric) before the connection is closed out from under it.
+func (sess *session) close() {
+	sess.server.removeSession(sess)
+	close(sess.outbox)
+	<-sess.writeDone
+	sess.conn.Close()
+}
+
+// handleLine dispatches a single line of the IRC client protocol.
+func (sess *session) handleLine(line string) error {
+	command, params := splitCommand(line)
+
+	switch strings.ToUpper(command) {
+	case "CAP":
+		return sess.handleCAP(params)
+	case "AUTHENTICATE":
+		return sess.handleAuthenticate(params)
+	case "PASS":
+		// Accepted for clients that don't do SASL; the password is
+		// validated against Upstream.Authenticate once NICK/USER
+		// registration completes, in maybeRegister.
+		if len(params) > 0 {
+			sess.pass = params[0]
+		}
+		return nil
+	case "NICK":
+		if len(params) > 0 {
+			sess.nick = params[0]
+		}
+		return sess.maybeRegister()
+	case "USER":
+		if len(params) > 0 {
+			sess.user = params[0]
+		}
+		return sess.maybeRegister()
+	case "PING":
+		sess.send(fmt.Sprintf(":%s PONG %s :%s", serverName, serverName, strings.Join(params, " ")))
+		return nil
+	case "PRIVMSG":
+		return sess.handlePrivmsg(params)
+	case "QUIT":
+		return fmt.Errorf("downstream %s quit", sess.nick)
+	default:
+		// Unknown commands are ignored; this bridge only implements the
+		// subset local clients need to talk to a single-user bouncer.
+		return nil
+	}
+}
+
+// maybeRegister completes IRC registration once both NICK and USER have
+// been seen and the session has authenticated, either via a completed SASL
+// PLAIN exchange or a PASS that Upstream.Authenticate accepts. A session
+// that never authenticates is rejected rather than silently granted access.
+func (sess *session) maybeRegister() error {
+	if sess.authed || sess.nick == "" || sess.user == "" {
+		return nil
+	}
+
+	if !sess.server.cfg.Enabled {
+		return fmt.Errorf("bridge disabled")
+	}
+
+	if !sess.saslAuthed && !(sess.pass != "" && sess.server.upstream.Authenticate(sess.user, sess.pass)) {
+		sess.send(fmt.Sprintf(":%s 464 %s :Password incorrect", serverName, sess.nick))
+		return fmt.Errorf("session for %s never completed SASL or PASS authentication", sess.user)
+	}
+
+	sess.authed = true
+	sess.server.addSession(sess)
+
+	sess.send(fmt.Sprintf(":%s 001 %s :Welcome to irccloud-watcher, %s", serverName, sess.nick, sess.nick))
+	sess.send(fmt.Sprintf(":%s 376 %s :End of /MOTD command.", serverName, sess.nick))
+	return nil
+}
+
+// handleCAP answers capability negotiation with just sasl, the only
+// capability this bridge supports.
+func (sess *session) handleCAP(params []string) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	switch strings.ToUpper(params[0]) {
+	case "LS":
+		sess.capNegotiating = true
+		sess.send(fmt.Sprintf(":%s CAP * LS :sasl", serverName))
+	case "REQ":
+		sess.send(fmt.Sprintf(":%s CAP * ACK :%s", serverName, strings.Join(params[1:], " ")))
+	case "END":
+		sess.capNegotiating = false
+	}
+	return nil
+}
+
+// handleAuthenticate implements the SASL PLAIN exchange, mapping the
+// authcid/password a downstream client presents onto the credentials used
+// for the upstream IRCCloud login.
+func (sess *session) handleAuthenticate(params []string) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	if strings.EqualFold(params[0], "PLAIN") {
+		sess.send("AUTHENTICATE +")
+		return nil
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(params[0])
+	if err != nil {
+		sess.send(fmt.Sprintf(":%s 904 %s :SASL authentication failed", serverName, sess.nick))
+		return fmt.Errorf("malformed SASL PLAIN payload: %w", err)
+	}
+
+	// authzid \0 authcid \0 passwd
+	parts := strings.SplitN(string(payload), "\x00", 3)
+	if len(parts) != 3 {
+		sess.send(fmt.Sprintf(":%s 904 %s :SASL authentication failed", serverName, sess.nick))
+		return fmt.Errorf("malformed SASL PLAIN payload")
+	}
+
+	authcid, passwd := parts[1], parts[2]
+	if !sess.server.upstream.Authenticate(authcid, passwd) {
+		sess.send(fmt.Sprintf(":%s 904 %s :SASL authentication failed", serverName, sess.nick))
+		return fmt.Errorf("SASL authentication failed for %s", authcid)
+	}
+
+	sess.saslAuthed = true
+	sess.send(fmt.Sprintf(":%s 900 %s %s :You are now logged in as %s", serverName, sess.nick, sess.nick, authcid))
+	sess.send(fmt.Sprintf(":%s 903 %s :SASL authentication successful", serverName, sess.nick))
+	return nil
+}
+
+// handlePrivmsg relays a downstream PRIVMSG upstream via the IRCCloud "say"
+// method over the same WebSocket the upstream connection already holds.
+func (sess *session) handlePrivmsg(params []string) error {
+	if !sess.authed {
+		return fmt.Errorf("PRIVMSG from unauthenticated session %s", sess.nick)
+	}
+	if len(params) < 2 {
+		return nil
+	}
+
+	target, text := params[0], params[1]
+	if err := sess.server.upstream.Say(target, text); err != nil {
+		return fmt.Errorf("relaying PRIVMSG to %s upstream: %w", target, err)
+	}
+	return nil
+}
+
+// splitCommand splits a raw IRC line into its command and parameters,
+// honoring the "trailing" parameter convention where everything after a
+// leading ':' is a single final argument. A leading message prefix
+// (":nick!user@host COMMAND ...", sent by some clients even though it's
+// normally server-only) is stripped first.
+func splitCommand(line string) (command string, params []string) {
+	if strings.HasPrefix(line, ":") {
+		if idx := strings.Index(line, " "); idx != -1 {
+			line = line[idx+1:]
+		}
+	}
+
+	trailing := ""
+	if idx := strings.Index(line, " :"); idx != -1 {
+		trailing = line[idx+2:]
+		line = line[:idx]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	command = fields[0]
+	params = fields[1:]
+	if trailing != "" {
+		params = append(params, trailing)
+	}
+	return command, params
+}
@@ -0,0 +1,174 @@
+package bridge
+
+import (
+	"bufio"
+	"encoding/base64"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"irccloud-watcher/internal/config"
+)
+
+func TestSplitCommand(t *testing.T) {
+	cases := []struct {
+		line    string
+		command string
+		params  []string
+	}{
+		{"NICK alice", "NICK", []string{"alice"}},
+		{"PRIVMSG #test :hello there", "PRIVMSG", []string{"#test", "hello there"}},
+		{":alice!a@h PRIVMSG #test :hi", "PRIVMSG", []string{"#test", "hi"}},
+		{"CAP LS", "CAP", []string{"LS"}},
+	}
+
+	for _, tc := range cases {
+		command, params := splitCommand(tc.line)
+		if command != tc.command {
+			t.Errorf("splitCommand(%q) command = %q, want %q", tc.line, command, tc.command)
+		}
+		if len(params) != len(tc.params) {
+			t.Fatalf("splitCommand(%q) params = %v, want %v", tc.line, params, tc.params)
+		}
+		for i := range params {
+			if params[i] != tc.params[i] {
+				t.Errorf("splitCommand(%q) params[%d] = %q, want %q", tc.line, i, params[i], tc.params[i])
+			}
+		}
+	}
+}
+
+// fakeUpstream is a test double for Upstream that records relayed PRIVMSGs
+// and authenticates against a fixed user/pass pair.
+type fakeUpstream struct {
+	user, pass string
+	sent       []string
+}
+
+func (f *fakeUpstream) Authenticate(user, pass string) bool {
+	return user == f.user && pass == f.pass
+}
+
+func (f *fakeUpstream) Say(target, message string) error {
+	f.sent = append(f.sent, target+":"+message)
+	return nil
+}
+
+func TestSessionRegistrationAndPrivmsgRelay(t *testing.T) {
+	upstream := &fakeUpstream{user: "alice@example.com", pass: "hunter2"}
+	server := NewServer(config.BridgeConfig{Enabled: true}, upstream)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	sess := server.newSession(serverConn)
+	go sess.run()
+
+	writer := bufio.NewWriter(clientConn)
+	reader := bufio.NewReader(clientConn)
+
+	send := func(line string) {
+		if _, err := writer.WriteString(line + "\r\n"); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatalf("flush failed: %v", err)
+		}
+	}
+
+	readLine := func() string {
+		clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		return line
+	}
+
+	send("CAP LS")
+	readLine() // CAP * LS :sasl
+	send("CAP REQ :sasl")
+	readLine() // CAP * ACK :sasl
+	send("AUTHENTICATE PLAIN")
+	readLine() // AUTHENTICATE +
+	payload := base64.StdEncoding.EncodeToString([]byte("\x00alice@example.com\x00hunter2"))
+	send("AUTHENTICATE " + payload)
+	readLine() // 900 logged in as
+	readLine() // 903 SASL authentication successful
+	send("CAP END")
+
+	send("NICK alice")
+	send("USER alice 0 * :Alice")
+
+	welcome := readLine()
+	if !strings.Contains(welcome, "001") {
+		t.Fatalf("expected 001 welcome numeric, got %q", welcome)
+	}
+	readLine() // end-of-MOTD numeric
+
+	send("PRIVMSG #test :hello from downstream")
+	time.Sleep(50 * time.Millisecond)
+
+	if len(upstream.sent) != 1 || upstream.sent[0] != "#test:hello from downstream" {
+		t.Fatalf("expected PRIVMSG to be relayed upstream, got %v", upstream.sent)
+	}
+
+	server.BroadcastMessage("bob", "#test", "hi there", false)
+	broadcast := readLine()
+	if !strings.Contains(broadcast, "PRIVMSG #test :hi there") {
+		t.Fatalf("expected broadcast message to reach downstream, got %q", broadcast)
+	}
+}
+
+// TestSessionWithoutSASLOrPassIsRejected verifies the bypass this bridge
+// used to allow: a client that skips CAP/AUTHENTICATE and never sends a
+// correct PASS must not be registered or have its PRIVMSG relayed upstream.
+func TestSessionWithoutSASLOrPassIsRejected(t *testing.T) {
+	upstream := &fakeUpstream{user: "alice@example.com", pass: "hunter2"}
+	server := NewServer(config.BridgeConfig{Enabled: true}, upstream)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	sess := server.newSession(serverConn)
+	done := make(chan struct{})
+	go func() {
+		sess.run()
+		close(done)
+	}()
+
+	writer := bufio.NewWriter(clientConn)
+	reader := bufio.NewReader(clientConn)
+
+	send := func(line string) {
+		if _, err := writer.WriteString(line + "\r\n"); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatalf("flush failed: %v", err)
+		}
+	}
+
+	send("NICK alice")
+	send("USER alice 0 * :Alice")
+
+	clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !strings.Contains(line, "464") {
+		t.Fatalf("expected a 464 rejection numeric for an unauthenticated session, got %q", line)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the session to close after a failed registration attempt")
+	}
+
+	if len(upstream.sent) != 0 {
+		t.Fatalf("expected no PRIVMSG relayed upstream for an unauthenticated session, got %v", upstream.sent)
+	}
+}
@@ -0,0 +1,131 @@
+// Package commands runs the named, parameterized SELECT queries defined
+// under Config.Commands against the message store, turning accumulated IRC
+// history into a queryable reporting surface.
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/storage"
+)
+
+// sinceTokenPattern extracts the magnitude and unit from a "$since_Xh" or
+// "$since_Xd" arg token.
+var sinceTokenPattern = regexp.MustCompile(`^\$since_(\d+)([hd])$`)
+
+// Runner executes configured commands against a message store.
+type Runner struct {
+	db       *storage.DB
+	commands map[string]config.CommandConfig
+}
+
+// NewRunner creates a Runner for the given commands.
+func NewRunner(db *storage.DB, commands map[string]config.CommandConfig) *Runner {
+	return &Runner{db: db, commands: commands}
+}
+
+// Run resolves name's Args tokens against channel, executes the query, and
+// returns the result formatted per the command's Format (defaulting to
+// "table").
+func (r *Runner) Run(name, channel string) (string, error) {
+	cmd, ok := r.commands[name]
+	if !ok {
+		return "", fmt.Errorf("unknown command: %s", name)
+	}
+
+	args := make([]any, len(cmd.Args))
+	for i, token := range cmd.Args {
+		value, err := resolveArgToken(token, channel)
+		if err != nil {
+			return "", fmt.Errorf("command %s: %w", name, err)
+		}
+		args[i] = value
+	}
+
+	rows, err := r.db.Queryx(cmd.SQL, args...)
+	if err != nil {
+		return "", fmt.Errorf("command %s: query failed: %w", name, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("command %s: reading columns: %w", name, err)
+	}
+
+	var results []map[string]any
+	for rows.Next() {
+		row := make(map[string]any)
+		if err := rows.MapScan(row); err != nil {
+			return "", fmt.Errorf("command %s: scanning row: %w", name, err)
+		}
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("command %s: iterating rows: %w", name, err)
+	}
+
+	format := cmd.Format
+	if format == "" {
+		format = "table"
+	}
+
+	switch format {
+	case "table":
+		return formatTable(columns, results), nil
+	default:
+		return "", fmt.Errorf("command %s: unsupported format %q", name, format)
+	}
+}
+
+// resolveArgToken turns a CommandConfig.Args token into the value passed to
+// the matching "?" placeholder.
+func resolveArgToken(token, channel string) (any, error) {
+	if token == "$channel" {
+		return channel, nil
+	}
+
+	match := sinceTokenPattern.FindStringSubmatch(token)
+	if match == nil {
+		return nil, fmt.Errorf("unresolvable arg token %q", token)
+	}
+
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid arg token %q: %w", token, err)
+	}
+
+	var window time.Duration
+	switch match[2] {
+	case "h":
+		window = time.Duration(n) * time.Hour
+	case "d":
+		window = time.Duration(n) * 24 * time.Hour
+	}
+
+	return time.Now().Add(-window).Format("2006-01-02 15:04:05"), nil
+}
+
+// formatTable renders rows as a header line followed by one line per row,
+// with columns separated by single tabs (values are not padded to align).
+func formatTable(columns []string, rows []map[string]any) string {
+	var sb strings.Builder
+	sb.WriteString(strings.Join(columns, "\t"))
+	sb.WriteString("\n")
+
+	for _, row := range rows {
+		values := make([]string, len(columns))
+		for i, col := range columns {
+			values[i] = fmt.Sprintf("%v", row[col])
+		}
+		sb.WriteString(strings.Join(values, "\t"))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/storage"
+)
+
+func newTestDB(t *testing.T) *storage.DB {
+	t.Helper()
+	db, err := storage.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRunResolvesChannelAndSinceTokens(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Now()
+	if err := db.InsertMessage(&storage.Message{
+		Channel: "#devops", Timestamp: now, Sender: "alice",
+		Message: "deploying", Date: now.Format("2006-01-02"), EID: 1,
+	}); err != nil {
+		t.Fatalf("failed to insert message: %v", err)
+	}
+	if err := db.InsertMessage(&storage.Message{
+		Channel: "#offtopic", Timestamp: now, Sender: "bob",
+		Message: "lol", Date: now.Format("2006-01-02"), EID: 2,
+	}); err != nil {
+		t.Fatalf("failed to insert message: %v", err)
+	}
+
+	runner := NewRunner(db, map[string]config.CommandConfig{
+		"top_talkers": {
+			SQL:    "SELECT sender, COUNT(*) c FROM messages WHERE channel=? AND timestamp>? GROUP BY sender ORDER BY c DESC",
+			Args:   []string{"$channel", "$since_24h"},
+			Format: "table",
+		},
+	})
+
+	out, err := runner.Run("top_talkers", "#devops")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(out, "alice") || strings.Contains(out, "bob") {
+		t.Errorf("expected output to contain alice but not bob, got: %s", out)
+	}
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	runner := NewRunner(newTestDB(t), map[string]config.CommandConfig{})
+	if _, err := runner.Run("missing", "#devops"); err == nil {
+		t.Fatal("expected an error for an unknown command")
+	}
+}
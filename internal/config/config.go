@@ -1,24 +1,120 @@
 package config
 
 import (
+	"bytes"
+	_ "embed"
 	"fmt"
 	"os"
+	"reflect"
+	"regexp"
+	"strings"
 
+	"github.com/mitchellh/mapstructure"
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
 )
 
+// defaultConfigYAML is the embedded default config.yaml, bundled into the
+// binary so a fresh install has a working baseline without hunting for an
+// example file on GitHub. LoadConfig merges it underneath whatever path is
+// passed in, and the `--default-config` CLI flag prints it verbatim (there
+// is no subcommand form).
+//
+//go:embed default_config.yaml
+var defaultConfigYAML []byte
+
+// DefaultConfigYAML returns the embedded default configuration's raw YAML.
+func DefaultConfigYAML() []byte {
+	return defaultConfigYAML
+}
+
 // Config stores the application configuration.
 // It is loaded from a YAML file.
 type Config struct {
-	Email             string           `mapstructure:"email"`
-	Password          string           `mapstructure:"password"`
-	Channels          []string         `mapstructure:"channels"`
-	IgnoredChannels   []string         `mapstructure:"ignored_channels"`
-	DatabasePath      string           `mapstructure:"database_path"`
-	SummaryOutputPath string           `mapstructure:"summary_output_path"`
-	SummaryTime       string           `mapstructure:"summary_time"`
-	Connection        ConnectionConfig `mapstructure:"connection"`
-	LLM               LLMConfig        `mapstructure:"llm"`
+	Email             string          `mapstructure:"email"`
+	Password          string          `mapstructure:"password"`
+	Channels          []ChannelConfig `mapstructure:"channels"`
+	IgnoredChannels   []string        `mapstructure:"ignored_channels"`
+	DatabasePath      string          `mapstructure:"database_path"`
+	SummaryOutputPath string          `mapstructure:"summary_output_path"`
+	SummaryTime       string          `mapstructure:"summary_time"`
+	// SummaryFormat selects the summary.Formatter summaries are rendered
+	// with: "markdown" (default), "json", or "atom". Unrecognized or unset
+	// values fall back to markdown.
+	SummaryFormat string                   `mapstructure:"summary_format"`
+	Connection    ConnectionConfig         `mapstructure:"connection"`
+	LLM           LLMConfig                `mapstructure:"llm"`
+	Prompts       PromptsConfig            `mapstructure:"prompts"`
+	Plugins       PluginsConfig            `mapstructure:"plugins"`
+	Commands      map[string]CommandConfig `mapstructure:"commands"`
+	Bridge        BridgeConfig             `mapstructure:"bridge"`
+	Logging       LoggingConfig            `mapstructure:"logging"`
+	Auth          AuthConfig               `mapstructure:"auth"`
+	Enrichment    EnrichmentConfig         `mapstructure:"enrichment"`
+	Storage       StorageConfig            `mapstructure:"storage"`
+}
+
+// ChannelConfig is a monitored channel with optional per-channel overrides.
+// A bare channel name in the config's `channels` list (the pre-override
+// form) is promoted to a ChannelConfig{Name: name} by the
+// stringToChannelConfigHookFunc decode hook.
+type ChannelConfig struct {
+	Name              string `mapstructure:"name"`
+	SummaryTime       string `mapstructure:"summary_time"`
+	SummaryOutputPath string `mapstructure:"summary_output_path"`
+	// SummaryFormat overrides Config.SummaryFormat for this channel.
+	SummaryFormat string `mapstructure:"summary_format"`
+	// LLM names a provider profile from llm.providers to use for this
+	// channel's summaries instead of llm.default.
+	LLM          string               `mapstructure:"llm"`
+	Prompts      *ChannelPromptConfig `mapstructure:"prompts"`
+	IgnoredNicks []string             `mapstructure:"ignored_nicks"`
+}
+
+// ChannelNames returns the plain list of monitored channel names.
+func (c *Config) ChannelNames() []string {
+	names := make([]string, len(c.Channels))
+	for i, ch := range c.Channels {
+		names[i] = ch.Name
+	}
+	return names
+}
+
+// EffectiveChannelConfig returns name's ChannelConfig with every unset
+// override field filled in from the global defaults.
+func (c *Config) EffectiveChannelConfig(name string) ChannelConfig {
+	effective := ChannelConfig{
+		Name:              name,
+		SummaryTime:       c.SummaryTime,
+		SummaryOutputPath: c.SummaryOutputPath,
+		SummaryFormat:     c.SummaryFormat,
+		LLM:               c.LLM.Default,
+	}
+
+	for _, ch := range c.Channels {
+		if ch.Name != name {
+			continue
+		}
+		if ch.SummaryTime != "" {
+			effective.SummaryTime = ch.SummaryTime
+		}
+		if ch.SummaryOutputPath != "" {
+			effective.SummaryOutputPath = ch.SummaryOutputPath
+		}
+		if ch.SummaryFormat != "" {
+			effective.SummaryFormat = ch.SummaryFormat
+		}
+		if ch.LLM != "" {
+			effective.LLM = ch.LLM
+		}
+		if ch.Prompts != nil {
+			effective.Prompts = ch.Prompts
+		}
+		effective.IgnoredNicks = ch.IgnoredNicks
+		break
+	}
+
+	return effective
 }
 
 // ConnectionConfig stores WebSocket connection parameters.
@@ -30,9 +126,85 @@ type ConnectionConfig struct {
 	BackoffMultiplier float64 `mapstructure:"backoff_multiplier"`
 	ConnectionTimeout string  `mapstructure:"connection_timeout"`
 	PingInterval      string  `mapstructure:"ping_interval"`
+
+	// BackoffStrategy selects how calculateBackoffDelay spreads reconnect
+	// attempts out to avoid a thundering herd after an IRCCloud outage:
+	// "exponential" (no jitter, just initial*multiplier^retryCount capped
+	// at MaxRetryDelay), "exponential_jitter" (the same delay, randomized
+	// uniformly within JitterFactor of it; the default), or
+	// "decorrelated_jitter" (AWS-style: next = random between
+	// InitialRetryDelay and 3x the previous delay, capped at
+	// MaxRetryDelay).
+	BackoffStrategy string  `mapstructure:"backoff_strategy"`
+	JitterFactor    float64 `mapstructure:"jitter_factor"`
+
+	// WriteRateLimit/WriteBurst bound how fast IRCCloudClient may write to
+	// the upstream WebSocket (heartbeats, pings, and "say" calls), as a
+	// token-bucket: WriteRateLimit tokens refill per second, up to
+	// WriteBurst.
+	WriteRateLimit float64 `mapstructure:"write_rate_limit"`
+	WriteBurst     int     `mapstructure:"write_burst"`
+
+	// ReconnectRateLimit/ReconnectBurst bound how often connectWithRetry
+	// may attempt a new connection, as a token-bucket on top of the
+	// backoff delay: ReconnectRateLimit tokens refill per second, up to
+	// ReconnectBurst. This is a second line of defense alongside the
+	// backoff/circuit breaker against a flapping IRCCloud endpoint causing
+	// a hot reconnect loop.
+	ReconnectRateLimit float64 `mapstructure:"reconnect_rate_limit"`
+	ReconnectBurst     int     `mapstructure:"reconnect_burst"`
+
+	// ChannelIngestRateLimit/ChannelIngestBurst bound how many messages per
+	// second a single channel may insert into the message store, so one
+	// spammy channel can't stall SQLite writes for the rest.
+	ChannelIngestRateLimit float64 `mapstructure:"channel_ingest_rate_limit"`
+	ChannelIngestBurst     int     `mapstructure:"channel_ingest_burst"`
+
+	// CircuitBreakerThreshold/CircuitBreakerWindow bound how many reconnects
+	// may happen in a sliding window before the client gives up retrying at
+	// the normal backoff and opens the circuit instead, waiting
+	// CircuitBreakerCooldown before trying again. This catches the case
+	// where IRCCloud is having a bad time and every retry just fails
+	// immediately, which would otherwise burn through MaxRetryAttempts in
+	// seconds.
+	CircuitBreakerThreshold int    `mapstructure:"circuit_breaker_threshold"`
+	CircuitBreakerWindow    string `mapstructure:"circuit_breaker_window"`
+	CircuitBreakerCooldown  string `mapstructure:"circuit_breaker_cooldown"`
+
+	// MetricsAddr, if set, serves Prometheus metrics (connection state,
+	// message throughput, ...) at /metrics on this address. Left empty,
+	// no metrics server is started.
+	MetricsAddr string `mapstructure:"metrics_addr"`
+
+	// PersistEIDCache, if true, snapshots the EID deduplication cache to
+	// the database on shutdown and reloads it on startup, so a process
+	// restart doesn't re-store messages IRCCloud replays via an
+	// oob_include backlog fetch right after reconnect.
+	PersistEIDCache bool `mapstructure:"persist_eid_cache"`
+
+	// FormatRenderer selects how stored/broadcast messages render mIRC
+	// formatting codes: "ansi", "html", or "markdown" to preserve them, or
+	// "" (the default) to strip them entirely.
+	FormatRenderer string `mapstructure:"format_renderer"`
+
+	// PongTimeoutMultiplier bounds how many PingIntervals may elapse
+	// without a pong before runMessageLoop gives up on the connection and
+	// returns an error, forcing Run to reconnect. Defaults to 2.
+	PongTimeoutMultiplier int `mapstructure:"pong_timeout_multiplier"`
+
+	// BacklogWorkers sizes the worker pool processBacklog spreads decoded
+	// backlog messages across. Messages are sharded by channel so a
+	// channel's own history is always handled in EID order by the same
+	// worker, while unrelated channels replay concurrently. Defaults to 4
+	// when unset or non-positive.
+	BacklogWorkers int `mapstructure:"backlog_workers"`
 }
 
-// LLMConfig stores LLM provider settings for summary generation.
+// LLMConfig stores LLM provider settings for summary generation. A flat
+// config (provider/base_url/model/...) is supported for backward
+// compatibility and is migrated into a single NamedProviderConfig called
+// "default" by LoadConfig. Configs that need multiple providers with
+// fallback routing should use Providers/Default/Fallbacks instead.
 type LLMConfig struct {
 	Provider    string  `mapstructure:"provider"`
 	BaseURL     string  `mapstructure:"base_url"`
@@ -40,21 +212,294 @@ type LLMConfig struct {
 	Temperature float64 `mapstructure:"temperature"`
 	MaxTokens   int     `mapstructure:"max_tokens"`
 	APIKey      string  `mapstructure:"api_key"`
+
+	Providers []NamedProviderConfig `mapstructure:"providers"`
+	Default   string                `mapstructure:"default"`
+	Fallbacks []string              `mapstructure:"fallbacks"`
+
+	// SummaryConcurrency bounds how many MessageGroup "map" summaries the
+	// generator requests in parallel before reducing them into the daily
+	// digest. Defaults to 3.
+	SummaryConcurrency int `mapstructure:"summary_concurrency"`
+}
+
+// NamedProviderConfig is a single named LLM provider profile. Multiple
+// profiles let operators mix providers, e.g. a cheap local Ollama model as
+// the default with a cloud model as fallback.
+type NamedProviderConfig struct {
+	Name        string  `mapstructure:"name"`
+	Kind        string  `mapstructure:"kind"`
+	BaseURL     string  `mapstructure:"base_url"`
+	Model       string  `mapstructure:"model"`
+	APIKey      string  `mapstructure:"api_key"`
+	Temperature float64 `mapstructure:"temperature"`
+	MaxTokens   int     `mapstructure:"max_tokens"`
+	Timeout     string  `mapstructure:"timeout"`
+
+	// Organization is sent as the OpenAI-Organization header for "openai"
+	// and "openai-compatible" providers billing to a specific org.
+	Organization string `mapstructure:"organization"`
+}
+
+// ResolveProvider returns the named provider profile, if one exists.
+func (c *LLMConfig) ResolveProvider(name string) (*NamedProviderConfig, bool) {
+	for i := range c.Providers {
+		if c.Providers[i].Name == name {
+			return &c.Providers[i], true
+		}
+	}
+	return nil, false
+}
+
+// DefaultProvider returns the configured default provider profile.
+func (c *LLMConfig) DefaultProvider() (*NamedProviderConfig, bool) {
+	return c.ResolveProvider(c.Default)
 }
 
-// LoadConfig loads the configuration from the given path.
+// PromptsConfig stores the prompts used when generating summaries.
+type PromptsConfig struct {
+	SystemPrompt     string                         `mapstructure:"system_prompt"`
+	SystemPromptFile string                         `mapstructure:"system_prompt_file"`
+	SummaryTemplate  string                         `mapstructure:"summary_template"`
+	Channels         map[string]ChannelPromptConfig `mapstructure:"channels"`
+}
+
+// ChannelPromptConfig stores per-channel prompt overrides.
+type ChannelPromptConfig struct {
+	SystemPrompt     string `mapstructure:"system_prompt"`
+	SystemPromptFile string `mapstructure:"system_prompt_file"`
+	SummaryTemplate  string `mapstructure:"summary_template"`
+}
+
+// PluginsConfig stores the Lua plugin scripts to load and which hooks they
+// may be invoked on.
+type PluginsConfig struct {
+	Scripts []string `mapstructure:"scripts"`
+	Hooks   []string `mapstructure:"hooks"`
+}
+
+// CommandConfig is a named, parameterized SELECT query over the message
+// store, e.g. a "top_talkers" report. Args names the substitution tokens
+// (in order) that fill the query's "?" placeholders at run time, and
+// Schedule, if set, reuses the SummaryTime cron machinery to run the
+// command automatically.
+type CommandConfig struct {
+	SQL      string   `mapstructure:"sql"`
+	Args     []string `mapstructure:"args"`
+	Format   string   `mapstructure:"format"`
+	Schedule string   `mapstructure:"schedule"`
+}
+
+// EnrichmentConfig controls the LLM-backed message enrichment pipeline: for
+// buffer_msg events on an opted-in channel, produce a short summary,
+// detected language, sentiment, and extracted entities via the configured
+// llm.Provider, stored alongside the message by EID.
+type EnrichmentConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Channels []string `mapstructure:"channels"`
+
+	// Provider names a provider profile from llm.providers to use for
+	// enrichment, instead of llm.default.
+	Provider string `mapstructure:"provider"`
+
+	// WorkerCount/QueueSize bound the enrichment worker pool, the same
+	// pattern as the handler worker pool: a slow LLM call can't block the
+	// WebSocket read loop, and a full queue drops the oldest request
+	// rather than blocking ingestion.
+	WorkerCount int `mapstructure:"worker_count"`
+	QueueSize   int `mapstructure:"queue_size"`
+
+	// RateLimit/Burst bound how many enrichment requests per second may be
+	// sent to the LLM provider, as a token-bucket.
+	RateLimit float64 `mapstructure:"rate_limit"`
+	Burst     int     `mapstructure:"burst"`
+
+	// ReconcileInterval controls how often the background reconciler scans
+	// for messages that were stored without an enrichment (e.g. because
+	// the LLM provider was unavailable) and retries them.
+	ReconcileInterval string `mapstructure:"reconcile_interval"`
+}
+
+// StorageConfig selects the MessageStore backend storage.Open builds.
+// Driver is "sqlite" (the default, backward-compatible with the top-level
+// database_path), "fs" for a grep-able ZNC-style flat-log tree, "memory"
+// for an unpersisted in-process store, or "postgres"/"mysql" to use a
+// standalone RDBMS. Source is interpreted according to Driver: a SQLite
+// DSN/file path, a root log directory, ignored for "memory", or a
+// Postgres/MySQL DSN.
+//
+// Some features - IRCCloudClient's EID-cache persistence and enrichment
+// storage, and commands.Runner's named SQL queries - only work against the
+// concrete sqlite driver; see storage.MessageStore's doc comment.
+type StorageConfig struct {
+	Driver string `mapstructure:"driver"`
+	Source string `mapstructure:"source"`
+}
+
+// BridgeConfig stores the settings for the downstream IRC gateway, which
+// lets local clients (HexChat, weechat, ...) connect to irccloud-watcher as
+// if it were an IRC server, turning it into a bouncer for the upstream
+// IRCCloud connection.
+type BridgeConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	ListenAddr string `mapstructure:"listen_addr"`
+	TLSCert    string `mapstructure:"tls_cert"`
+	TLSKey     string `mapstructure:"tls_key"`
+}
+
+// LoggingConfig controls the structured logger built by the logging
+// package. Level is one of "debug", "info", "warn", "error" (default
+// "info"). Encoding selects the zap encoder: "console" for human-readable
+// output during local development (default), or "json" for shipping logs to
+// Loki/ELK. OutputPath, if set, writes logs to that file instead of stderr,
+// rotating it via lumberjack according to MaxSizeMB/MaxBackups/MaxAgeDays/
+// Compress.
+type LoggingConfig struct {
+	Level      string `mapstructure:"level"`
+	Encoding   string `mapstructure:"encoding"`
+	OutputPath string `mapstructure:"output_path"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`
+	MaxBackups int    `mapstructure:"max_backups"`
+	MaxAgeDays int    `mapstructure:"max_age_days"`
+	Compress   bool   `mapstructure:"compress"`
+}
+
+// AuthConfig selects how IRCCloudClient authenticates upstream. Mode
+// "formtoken" (the default) is IRCCloud's own email/password login and
+// needs no further configuration here. Mode "oauth2" instead performs an
+// OAuth2 grant against OAuth2.TokenURL and presents the resulting bearer
+// token, for deployments that front IRCCloud (or a compatible replay
+// proxy) with an OAuth2 gateway instead of shipping raw passwords. Mode
+// "token" presents a pre-issued IRCCloud session token, and mode "jwt"
+// verifies a signed JWT locally and presents it as a bearer token — both
+// skip the auth-formtoken + login round-trip, for environments where
+// storing raw credentials is undesirable (a systemd credential store, a
+// Kubernetes secret containing only a short-lived token).
+type AuthConfig struct {
+	Mode   string       `mapstructure:"mode"`
+	OAuth2 OAuth2Config `mapstructure:"oauth2"`
+	Token  TokenConfig  `mapstructure:"token"`
+	JWT    JWTConfig    `mapstructure:"jwt"`
+
+	// RetryMaxElapsedTime/RetryInitialInterval/RetryMaxInterval bound the
+	// jittered exponential backoff retrying the login request (5xx and
+	// network errors; Retry-After is honored on 429/503). Retrying stops
+	// immediately on ErrInvalidCredentials regardless of these knobs. A
+	// generous RetryMaxElapsedTime lets a long-running watcher ride out an
+	// IRCCloud maintenance window without a manual restart.
+	RetryMaxElapsedTime  string `mapstructure:"retry_max_elapsed_time"`
+	RetryInitialInterval string `mapstructure:"retry_initial_interval"`
+	RetryMaxInterval     string `mapstructure:"retry_max_interval"`
+
+	// DebugDumpPath, if set, appends a redacted request/response
+	// transcript of every failed form-token, login, and upstream WebSocket
+	// handshake exchange to this file, so a user can attach it to a bug
+	// report. OAuth2 token requests are not dumped. Sensitive headers and the
+	// password/session/access_token/email body fields are masked before
+	// writing.
+	DebugDumpPath string `mapstructure:"debug_dump_path"`
+}
+
+// OAuth2Config configures the OAuth2 authenticator used when Auth.Mode is
+// "oauth2".
+type OAuth2Config struct {
+	TokenURL     string `mapstructure:"token_url"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	Username     string `mapstructure:"username"`
+	Password     string `mapstructure:"password"`
+	// GrantType is "password" (default) or "client_credentials".
+	GrantType     string `mapstructure:"grant_type"`
+	Scope         string `mapstructure:"scope"`
+	APIHost       string `mapstructure:"api_host"`
+	WebSocketHost string `mapstructure:"websocket_host"`
+	WebSocketPath string `mapstructure:"websocket_path"`
+}
+
+// TokenConfig configures the token authenticator used when Auth.Mode is
+// "token".
+type TokenConfig struct {
+	Session       string `mapstructure:"session"`
+	APIHost       string `mapstructure:"api_host"`
+	WebSocketHost string `mapstructure:"websocket_host"`
+	WebSocketPath string `mapstructure:"websocket_path"`
+}
+
+// JWTConfig configures the JWT authenticator used when Auth.Mode is "jwt".
+type JWTConfig struct {
+	Token         string `mapstructure:"token"`
+	Key           string `mapstructure:"key"`
+	APIHost       string `mapstructure:"api_host"`
+	WebSocketHost string `mapstructure:"websocket_host"`
+	WebSocketPath string `mapstructure:"websocket_path"`
+}
+
+// stringToChannelConfigHookFunc promotes a bare channel name string to a
+// ChannelConfig{Name: name}, so the legacy `channels: ["#foo", "#bar"]` form
+// keeps working unchanged alongside the per-channel override form.
+func stringToChannelConfigHookFunc() mapstructure.DecodeHookFunc {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(ChannelConfig{}) {
+			return data, nil
+		}
+		return ChannelConfig{Name: data.(string)}, nil
+	}
+}
+
+// LoadConfig loads the configuration from the given path, merged on top of
+// the embedded default config so any field the file at path doesn't set -
+// including the whole file, if path doesn't exist - falls back to that
+// default rather than its Go zero value.
 func LoadConfig(path string) (*Config, error) {
+	return loadConfig(path, true)
+}
+
+// Defaults returns a Config built entirely from the embedded default
+// config.yaml, the same way LoadConfig would build one for a path that
+// doesn't exist, except it skips LoadConfig's required-field checks
+// (email, password, database_path, ...). It's for callers that supply
+// those fields from somewhere other than a config.yaml - env vars in a
+// test, CLI flags - and just want the rest of the defaults (Connection's
+// timeouts and rate limits, in particular) without hand-duplicating them.
+func Defaults() *Config {
+	c, err := loadConfig("", false)
+	if err != nil {
+		// defaultConfigYAML is embedded at build time and covered by this
+		// package's own tests, so a parse failure here means the binary
+		// itself was built wrong, not a runtime condition callers can
+		// recover from.
+		panic(fmt.Sprintf("config: embedded default config.yaml is invalid: %v", err))
+	}
+	return c
+}
+
+// loadConfig is LoadConfig's implementation, parameterized on whether to
+// enforce ValidateRequired so Defaults can reuse it without a config.yaml.
+func loadConfig(path string, requireFields bool) (*Config, error) {
 	v := viper.New()
-	v.SetConfigFile(path)
 	v.SetConfigType("yaml")
 	v.AutomaticEnv() // Enable environment variable substitution
 
-	if err := v.ReadInConfig(); err != nil {
-		return nil, err
+	if err := v.ReadConfig(bytes.NewReader(defaultConfigYAML)); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded default config: %w", err)
+	}
+
+	if _, statErr := os.Stat(path); statErr == nil {
+		v.SetConfigFile(path)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(statErr) {
+		return nil, statErr
 	}
 
 	var c Config
-	if err := v.Unmarshal(&c); err != nil {
+	decodeHook := mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		stringToChannelConfigHookFunc(),
+	)
+	if err := v.Unmarshal(&c, viper.DecodeHook(decodeHook)); err != nil {
 		return nil, err
 	}
 
@@ -65,6 +510,9 @@ func LoadConfig(path string) (*Config, error) {
 	if password := os.Getenv("IRCCLOUD_PASSWORD"); password != "" {
 		c.Password = password
 	}
+	if sessionToken := os.Getenv("IRCCLOUD_SESSION_TOKEN"); sessionToken != "" {
+		c.Auth.Token.Session = sessionToken
+	}
 
 	// Override LLM API key with environment variable if set
 	if apiKey := os.Getenv("LLM_API_KEY"); apiKey != "" {
@@ -83,9 +531,23 @@ func LoadConfig(path string) (*Config, error) {
 	// Set default connection values if not specified
 	setConnectionDefaults(&c.Connection)
 
+	// Set default logging values if not specified
+	setLoggingDefaults(&c.Logging)
+
+	// Set default auth values if not specified
+	setAuthDefaults(&c.Auth)
+
+	// Set default enrichment values if not specified
+	setEnrichmentDefaults(&c.Enrichment)
+
+	// Set default storage values if not specified
+	setStorageDefaults(&c)
+
 	// Validate required fields first (before setting defaults)
-	if err := c.ValidateRequired(); err != nil {
-		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	if requireFields {
+		if err := c.ValidateRequired(); err != nil {
+			return nil, fmt.Errorf("configuration validation failed: %w", err)
+		}
 	}
 
 	// Set default LLM values if not specified
@@ -98,9 +560,185 @@ func LoadConfig(path string) (*Config, error) {
 		}
 	}
 
+	migrateLegacyLLMConfig(&c.LLM)
+	applyProviderEnvOverrides(&c.LLM)
+
+	if err := c.validateLLMProviders(); err != nil {
+		return nil, fmt.Errorf("LLM provider configuration validation failed: %w", err)
+	}
+
+	if err := c.validatePromptsConfig(); err != nil {
+		return nil, fmt.Errorf("prompts configuration validation failed: %w", err)
+	}
+
+	if err := c.validatePluginsConfig(); err != nil {
+		return nil, fmt.Errorf("plugins configuration validation failed: %w", err)
+	}
+
+	if err := c.validateCommandsConfig(); err != nil {
+		return nil, fmt.Errorf("commands configuration validation failed: %w", err)
+	}
+
+	if err := c.validateChannelsConfig(); err != nil {
+		return nil, fmt.Errorf("channels configuration validation failed: %w", err)
+	}
+
+	if err := c.validateBridgeConfig(); err != nil {
+		return nil, fmt.Errorf("bridge configuration validation failed: %w", err)
+	}
+
+	if err := c.validateStorageConfig(); err != nil {
+		return nil, fmt.Errorf("storage configuration validation failed: %w", err)
+	}
+
 	return &c, nil
 }
 
+// cronParser validates cron expressions the same way robfig/cron parses
+// them at schedule time, so a typo in a per-channel override fails fast at
+// startup instead of when the scheduler tries to use it.
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// validateChannelsConfig requires a name on every channel entry and checks
+// that any per-channel summary_time override is a valid cron expression.
+func (c *Config) validateChannelsConfig() error {
+	for _, ch := range c.Channels {
+		if ch.Name == "" {
+			return fmt.Errorf("channel entries require a name")
+		}
+		if ch.SummaryTime != "" {
+			if _, err := cronParser.Parse(ch.SummaryTime); err != nil {
+				return fmt.Errorf("channel %q: invalid summary_time cron expression: %w", ch.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// commandArgTokenPattern matches the substitution tokens a CommandConfig's
+// Args may use: "$channel" or a relative time window like "$since_24h" /
+// "$since_7d".
+var commandArgTokenPattern = regexp.MustCompile(`^\$channel$|^\$since_\d+[hd]$`)
+
+// validateCommandsConfig rejects anything but a SELECT statement (DDL/DML
+// would let a command mutate the message store), requires that every "?"
+// placeholder has a matching Args entry, and that every Args entry is a
+// token this subsystem knows how to resolve at run time.
+func (c *Config) validateCommandsConfig() error {
+	for name, cmd := range c.Commands {
+		tokens := strings.Fields(cmd.SQL)
+		if len(tokens) == 0 || strings.ToUpper(tokens[0]) != "SELECT" {
+			return fmt.Errorf("command %q: only SELECT statements are allowed", name)
+		}
+
+		placeholders := strings.Count(cmd.SQL, "?")
+		if placeholders != len(cmd.Args) {
+			return fmt.Errorf("command %q: sql has %d placeholder(s) but args has %d", name, placeholders, len(cmd.Args))
+		}
+
+		for _, arg := range cmd.Args {
+			if !commandArgTokenPattern.MatchString(arg) {
+				return fmt.Errorf("command %q: unresolvable arg token %q (expected $channel or $since_<N>h / $since_<N>d)", name, arg)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBridgeConfig requires a listen_addr when the bridge is enabled,
+// and that tls_cert/tls_key are either both set or both absent.
+func (c *Config) validateBridgeConfig() error {
+	if !c.Bridge.Enabled {
+		return nil
+	}
+
+	if c.Bridge.ListenAddr == "" {
+		return fmt.Errorf("listen_addr is required when bridge is enabled")
+	}
+
+	if (c.Bridge.TLSCert == "") != (c.Bridge.TLSKey == "") {
+		return fmt.Errorf("tls_cert and tls_key must both be set to enable TLS")
+	}
+
+	return nil
+}
+
+// validStorageDrivers are the storage.driver values storage.Open accepts.
+var validStorageDrivers = map[string]bool{
+	"sqlite":   true,
+	"fs":       true,
+	"memory":   true,
+	"postgres": true,
+	"mysql":    true,
+}
+
+// validateStorageConfig rejects an unknown driver and requires a source
+// for drivers that need one.
+func (c *Config) validateStorageConfig() error {
+	if !validStorageDrivers[c.Storage.Driver] {
+		return fmt.Errorf("unsupported storage driver: %s (supported: sqlite, fs, memory, postgres, mysql)", c.Storage.Driver)
+	}
+	if c.Storage.Driver != "memory" && c.Storage.Source == "" {
+		return fmt.Errorf("storage.source is required for driver %s", c.Storage.Driver)
+	}
+	return nil
+}
+
+// validPluginHooks are the hook names plugin scripts may register for.
+var validPluginHooks = map[string]bool{
+	"on_message":   true,
+	"pre_summary":  true,
+	"post_summary": true,
+	"on_command":   true,
+}
+
+// validatePluginsConfig stats every configured script so a typo or missing
+// file fails fast at startup instead of when the hook first fires, and
+// rejects unknown hook names.
+func (c *Config) validatePluginsConfig() error {
+	for _, script := range c.Plugins.Scripts {
+		if _, err := os.Stat(script); err != nil {
+			return fmt.Errorf("plugin script %q is not accessible: %w", script, err)
+		}
+	}
+
+	for _, hook := range c.Plugins.Hooks {
+		if !validPluginHooks[hook] {
+			return fmt.Errorf("unknown plugin hook: %s (supported: on_message, pre_summary, post_summary, on_command)", hook)
+		}
+	}
+
+	return nil
+}
+
+// validatePromptsConfig validates that inline and file-based prompt overrides
+// are mutually exclusive, both at the top level and per channel.
+func (c *Config) validatePromptsConfig() error {
+	if c.Prompts.SystemPrompt != "" && c.Prompts.SystemPromptFile != "" {
+		return fmt.Errorf("system_prompt and system_prompt_file are mutually exclusive")
+	}
+	if c.Prompts.SystemPromptFile != "" {
+		if _, err := os.Stat(c.Prompts.SystemPromptFile); err != nil {
+			return fmt.Errorf("system_prompt_file %q is not accessible: %w", c.Prompts.SystemPromptFile, err)
+		}
+	}
+
+	for channel, override := range c.Prompts.Channels {
+		if override.SystemPrompt != "" && override.SystemPromptFile != "" {
+			return fmt.Errorf("channel %q: system_prompt and system_prompt_file are mutually exclusive", channel)
+		}
+		if override.SystemPromptFile != "" {
+			if _, err := os.Stat(override.SystemPromptFile); err != nil {
+				return fmt.Errorf("channel %q: system_prompt_file %q is not accessible: %w", channel, override.SystemPromptFile, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // ValidateRequired checks that all required configuration fields are present
 func (c *Config) ValidateRequired() error {
 	if c.Email == "" {
@@ -141,20 +779,31 @@ func (c *Config) Validate() error {
 // validateLLMConfig validates LLM-specific configuration
 func (c *Config) validateLLMConfig() error {
 	validProviders := map[string]bool{
-		"ollama":    true,
-		"openai":    true,
-		"anthropic": true,
-		"gemini":    true,
+		"ollama":            true,
+		"openai":            true,
+		"openai-compatible": true,
+		"anthropic":         true,
+		"gemini":            true,
 	}
 
 	if !validProviders[c.LLM.Provider] {
-		return fmt.Errorf("unsupported LLM provider: %s (supported: ollama, openai, anthropic, gemini)", c.LLM.Provider)
+		return fmt.Errorf("unsupported LLM provider: %s (supported: ollama, openai, openai-compatible, anthropic, gemini)", c.LLM.Provider)
 	}
 
-	if c.LLM.Provider != "ollama" && c.LLM.APIKey == "" {
+	// Self-hosted endpoints (Ollama, or an openai-compatible server such as
+	// LocalAI/vLLM/LM Studio) typically don't require an API key.
+	if c.LLM.Provider != "ollama" && c.LLM.Provider != "openai-compatible" && c.LLM.APIKey == "" {
 		return fmt.Errorf("api_key is required for provider %s (set via environment variable or config file)", c.LLM.Provider)
 	}
 
+	// Unlike "openai", which defaults to api.openai.com, "openai-compatible"
+	// exists specifically to point at a self-hosted runtime (llama.cpp,
+	// vLLM, LM Studio, Ollama's own /v1 endpoint, ...). Leaving base_url
+	// unset would silently fall back to OpenAI's API instead, so require it.
+	if c.LLM.Provider == "openai-compatible" && c.LLM.BaseURL == "" {
+		return fmt.Errorf("base_url is required for provider openai-compatible")
+	}
+
 	if c.LLM.Model == "" {
 		return fmt.Errorf("model is required for LLM provider %s", c.LLM.Provider)
 	}
@@ -193,6 +842,114 @@ func setConnectionDefaults(c *ConnectionConfig) {
 	if c.PingInterval == "" {
 		c.PingInterval = "60s"
 	}
+	if c.PongTimeoutMultiplier == 0 {
+		c.PongTimeoutMultiplier = 2
+	}
+	if c.BackoffStrategy == "" {
+		c.BackoffStrategy = "exponential_jitter"
+	}
+	if c.JitterFactor == 0 {
+		c.JitterFactor = 0.5
+	}
+	if c.WriteRateLimit == 0 {
+		c.WriteRateLimit = 5
+	}
+	if c.WriteBurst == 0 {
+		c.WriteBurst = 10
+	}
+	if c.ReconnectRateLimit == 0 {
+		c.ReconnectRateLimit = 0.2
+	}
+	if c.ReconnectBurst == 0 {
+		c.ReconnectBurst = 1
+	}
+	if c.ChannelIngestRateLimit == 0 {
+		c.ChannelIngestRateLimit = 10
+	}
+	if c.ChannelIngestBurst == 0 {
+		c.ChannelIngestBurst = 20
+	}
+	if c.CircuitBreakerThreshold == 0 {
+		c.CircuitBreakerThreshold = 5
+	}
+	if c.CircuitBreakerWindow == "" {
+		c.CircuitBreakerWindow = "2m"
+	}
+	if c.CircuitBreakerCooldown == "" {
+		c.CircuitBreakerCooldown = "10m"
+	}
+}
+
+// setEnrichmentDefaults sets default values for the enrichment pipeline.
+func setEnrichmentDefaults(c *EnrichmentConfig) {
+	if c.WorkerCount == 0 {
+		c.WorkerCount = 2
+	}
+	if c.QueueSize == 0 {
+		c.QueueSize = 64
+	}
+	if c.RateLimit == 0 {
+		c.RateLimit = 1
+	}
+	if c.Burst == 0 {
+		c.Burst = 2
+	}
+	if c.ReconcileInterval == "" {
+		c.ReconcileInterval = "5m"
+	}
+}
+
+// setStorageDefaults defaults Driver to "sqlite" and, for the sqlite
+// driver only, defaults Source to the top-level database_path so existing
+// configs that never set storage: keep working unchanged.
+func setStorageDefaults(c *Config) {
+	if c.Storage.Driver == "" {
+		c.Storage.Driver = "sqlite"
+	}
+	if c.Storage.Source == "" && c.Storage.Driver == "sqlite" {
+		c.Storage.Source = c.DatabasePath
+	}
+}
+
+// setLoggingDefaults sets default values for logging configuration. The
+// rotation settings only take effect when OutputPath is set, but are
+// defaulted here regardless, the same as EnrichmentConfig's defaults are
+// set whether or not enrichment is enabled.
+func setLoggingDefaults(c *LoggingConfig) {
+	if c.Level == "" {
+		c.Level = "info"
+	}
+	if c.Encoding == "" {
+		c.Encoding = "console"
+	}
+	if c.MaxSizeMB == 0 {
+		c.MaxSizeMB = 100
+	}
+	if c.MaxBackups == 0 {
+		c.MaxBackups = 3
+	}
+	if c.MaxAgeDays == 0 {
+		c.MaxAgeDays = 28
+	}
+}
+
+// setAuthDefaults sets default values for auth configuration
+func setAuthDefaults(c *AuthConfig) {
+	if c.Mode == "" {
+		c.Mode = "formtoken"
+	}
+	if c.OAuth2.GrantType == "" {
+		c.OAuth2.GrantType = "password"
+	}
+	if c.RetryMaxElapsedTime == "" {
+		c.RetryMaxElapsedTime = "5m"
+	}
+	if c.RetryInitialInterval == "" {
+		c.RetryInitialInterval = "1s"
+	}
+	if c.RetryMaxInterval == "" {
+		c.RetryMaxInterval = "30s"
+	}
 }
 
 // setLLMDefaults sets default values for LLM configuration
@@ -221,4 +978,108 @@ func setLLMDefaults(c *LLMConfig) {
 	if c.MaxTokens == 0 {
 		c.MaxTokens = 1000
 	}
+	if c.SummaryConcurrency == 0 {
+		c.SummaryConcurrency = 3
+	}
+}
+
+// migrateLegacyLLMConfig synthesizes a "default" provider profile from the
+// flat llm: block when no named providers are configured, so older configs
+// keep working unchanged.
+func migrateLegacyLLMConfig(c *LLMConfig) {
+	if len(c.Providers) > 0 {
+		return
+	}
+	if c.Provider == "" {
+		return
+	}
+
+	c.Providers = []NamedProviderConfig{
+		{
+			Name:        "default",
+			Kind:        c.Provider,
+			BaseURL:     c.BaseURL,
+			Model:       c.Model,
+			APIKey:      c.APIKey,
+			Temperature: c.Temperature,
+			MaxTokens:   c.MaxTokens,
+		},
+	}
+	c.Default = "default"
+}
+
+// applyProviderEnvOverrides lets operators override a named provider's API
+// key via LLM_API_KEY_<NAME> (name upper-cased, non-alphanumerics replaced
+// with underscores) without editing the config file.
+func applyProviderEnvOverrides(c *LLMConfig) {
+	for i := range c.Providers {
+		envName := "LLM_API_KEY_" + sanitizeEnvName(c.Providers[i].Name)
+		if apiKey := os.Getenv(envName); apiKey != "" {
+			c.Providers[i].APIKey = apiKey
+		}
+	}
+}
+
+// sanitizeEnvName upper-cases a provider name and replaces anything that
+// isn't a letter, digit, or underscore so it forms a valid env var suffix.
+func sanitizeEnvName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// validateLLMProviders validates the named provider profiles: names must be
+// unique, Default must point at a configured provider, and every entry in
+// Fallbacks must resolve to a configured provider too.
+func (c *Config) validateLLMProviders() error {
+	if len(c.LLM.Providers) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(c.LLM.Providers))
+	for _, p := range c.LLM.Providers {
+		if p.Name == "" {
+			return fmt.Errorf("llm provider entries require a name")
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate llm provider name: %s", p.Name)
+		}
+		seen[p.Name] = true
+
+		// Self-hosted endpoints (Ollama, or an openai-compatible server)
+		// typically don't require an API key; every other kind does, and
+		// checking it here means a missing credential fails config loading
+		// instead of the first summary/enrichment run.
+		if p.Kind != "ollama" && p.Kind != "openai-compatible" && p.APIKey == "" {
+			return fmt.Errorf("llm provider %q: api_key is required for kind %s", p.Name, p.Kind)
+		}
+
+		// See the matching check in validateLLMConfig: base_url is the
+		// whole point of "openai-compatible", and an empty value would
+		// silently target OpenAI's own API instead of the intended runtime.
+		if p.Kind == "openai-compatible" && p.BaseURL == "" {
+			return fmt.Errorf("llm provider %q: base_url is required for kind openai-compatible", p.Name)
+		}
+	}
+
+	if c.LLM.Default == "" {
+		return fmt.Errorf("llm.default must name one of the configured providers")
+	}
+	if _, ok := c.LLM.ResolveProvider(c.LLM.Default); !ok {
+		return fmt.Errorf("llm.default %q does not match any configured provider", c.LLM.Default)
+	}
+
+	for _, name := range c.LLM.Fallbacks {
+		if _, ok := c.LLM.ResolveProvider(name); !ok {
+			return fmt.Errorf("llm.fallbacks entry %q does not match any configured provider", name)
+		}
+	}
+
+	return nil
 }
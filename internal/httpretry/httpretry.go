@@ -0,0 +1,126 @@
+// Package httpretry wraps http.Client.Do with jittered exponential backoff
+// for the handful of call sites across the watcher (IRCCloud auth, Ollama)
+// that talk to a single upstream and want to ride out a transient network
+// blip or a rate limit instead of failing the whole operation.
+package httpretry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Config bounds the retry loop Do applies around an HTTP call.
+type Config struct {
+	// MaxElapsedTime is the total time Do will keep retrying before giving
+	// up, regardless of ctx's own deadline.
+	MaxElapsedTime time.Duration
+
+	// InitialInterval/MaxInterval bound the jittered exponential backoff
+	// between attempts (InitialInterval doubling, capped at MaxInterval,
+	// each with up to 50% random jitter applied).
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+// DefaultConfig is a reasonable default for a short-lived upstream call:
+// a handful of quick retries rather than minutes of patience.
+func DefaultConfig() Config {
+	return Config{
+		MaxElapsedTime:  30 * time.Second,
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     5 * time.Second,
+	}
+}
+
+// Do performs an HTTP request built fresh by newRequest on every attempt
+// (a request's body can only be read once, so a single *http.Request can't
+// be replayed), retrying with jittered exponential backoff on network
+// errors and 5xx/429 responses, waiting out a numeric Retry-After header on
+// those responses before the computed delay, and honoring ctx.Done()
+// between attempts. Any other response status is returned immediately
+// without retrying, since retrying won't fix a 4xx the server isn't asking
+// us to slow down for.
+func Do(ctx context.Context, client *http.Client, cfg Config, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	if cfg.MaxElapsedTime <= 0 {
+		// The zero Config means "don't retry" rather than the backoff
+		// library's own "0 = unlimited" reading of MaxElapsedTime, so a
+		// caller that forgets to set retry knobs gets a single attempt
+		// instead of a silent infinite retry loop.
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		return client.Do(req)
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = cfg.InitialInterval
+	b.Multiplier = 2
+	b.MaxInterval = cfg.MaxInterval
+	b.MaxElapsedTime = cfg.MaxElapsedTime
+
+	var result *http.Response
+	attempt := 0
+	operation := func() error {
+		attempt++
+
+		req, err := newRequest()
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+
+		if !isRetryableStatus(resp.StatusCode) {
+			result = resp
+			return nil
+		}
+
+		if wait, ok := retryAfterDuration(resp.Header.Get("Retry-After")); ok {
+			resp.Body.Close()
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return backoff.Permanent(fmt.Errorf("retry cancelled while honoring Retry-After: %w", ctx.Err()))
+			}
+			return fmt.Errorf("attempt %d: retryable status %s, honored Retry-After", attempt, resp.Status)
+		}
+
+		resp.Body.Close()
+		return fmt.Errorf("attempt %d: retryable status %s", attempt, resp.Status)
+	}
+
+	if err := backoff.Retry(operation, backoff.WithContext(b, ctx)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// isRetryableStatus reports whether status is worth retrying: a rate limit
+// or a server-side failure, as opposed to a client error the retry can't
+// fix.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryAfterDuration parses a Retry-After header's delay-seconds form (the
+// HTTP-date form isn't worth the extra parsing for these short-lived
+// internal calls) and reports whether it was present and valid.
+func retryAfterDuration(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(header)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
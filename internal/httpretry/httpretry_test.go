@@ -0,0 +1,138 @@
+package httpretry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func fastConfig() Config {
+	return Config{
+		MaxElapsedTime:  2 * time.Second,
+		InitialInterval: 5 * time.Millisecond,
+		MaxInterval:     20 * time.Millisecond,
+	}
+}
+
+func TestDoReturnsFirstSuccessfulResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := Do(context.Background(), server.Client(), fastConfig(), func() (*http.Request, error) {
+		return http.NewRequest("GET", server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestDoRetriesOn503AndEventuallySucceeds(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := Do(context.Background(), server.Client(), fastConfig(), func() (*http.Request, error) {
+		return http.NewRequest("GET", server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	resp.Body.Close()
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestDoDoesNotRetryClientErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	resp, err := Do(context.Background(), server.Client(), fastConfig(), func() (*http.Request, error) {
+		return http.NewRequest("GET", server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	resp.Body.Close()
+	if attempts != 1 {
+		t.Errorf("expected no retries for a 400, got %d attempts", attempts)
+	}
+}
+
+func TestDoHonorsRetryAfterHeader(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := Do(context.Background(), server.Client(), fastConfig(), func() (*http.Request, error) {
+		return http.NewRequest("GET", server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	resp.Body.Close()
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestDoWithZeroConfigMakesASingleAttempt(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := Do(context.Background(), server.Client(), Config{}, func() (*http.Request, error) {
+		return http.NewRequest("GET", server.URL, nil)
+	}); err != nil {
+		t.Fatalf("expected the zero Config to surface the response rather than an error, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected the zero Config to make exactly one attempt, got %d", attempts)
+	}
+}
+
+func TestDoGivesUpWhenContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := Do(ctx, server.Client(), fastConfig(), func() (*http.Request, error) {
+		return http.NewRequest("GET", server.URL, nil)
+	}); err == nil {
+		t.Fatal("expected an error once the context is cancelled, got nil")
+	}
+}
@@ -0,0 +1,320 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultAnthropicBaseURL is used when no BaseURL override is configured.
+const defaultAnthropicBaseURL = "https://api.anthropic.com/v1"
+
+// anthropicAPIVersion is the Messages API version this client speaks.
+const anthropicAPIVersion = "2023-06-01"
+
+// AnthropicClient implements the Provider interface for Anthropic's Messages
+// API.
+type AnthropicClient struct {
+	config     *ProviderConfig
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+}
+
+// anthropicMessagesRequest represents a request to the /messages endpoint.
+type anthropicMessagesRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature,omitempty"`
+}
+
+// anthropicMessage represents a single message in a Messages API request.
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicMessagesResponse represents a response from the /messages endpoint.
+type anthropicMessagesResponse struct {
+	Model      string `json:"model"`
+	StopReason string `json:"stop_reason"`
+	Content    []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// anthropicErrorResponse represents an error response from the Anthropic API.
+type anthropicErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// NewAnthropicClient creates a new AnthropicClient.
+func NewAnthropicClient(config *ProviderConfig, apiKey string) *AnthropicClient {
+	if config == nil {
+		config = DefaultProviderConfig()
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+
+	return &AnthropicClient{
+		config:  config,
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// Name returns the provider name.
+func (c *AnthropicClient) Name() string {
+	return "anthropic"
+}
+
+// Generate generates text using the Messages API.
+func (c *AnthropicClient) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = c.config.DefaultMaxTokens
+	}
+
+	temperature := req.Temperature
+	if temperature <= 0 {
+		temperature = c.config.DefaultTemperature
+	}
+
+	msgReq := &anthropicMessagesRequest{
+		Model: model,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: req.Prompt},
+		},
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	}
+
+	startTime := time.Now()
+
+	reqBody, err := json.Marshal(msgReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/messages", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+	if c.apiKey != "" {
+		httpReq.Header.Set("x-api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseTime := time.Since(startTime)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp anthropicErrorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+			return nil, fmt.Errorf("anthropic API error (status %d): %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("anthropic API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var msgResp anthropicMessagesResponse
+	if err := json.Unmarshal(body, &msgResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(msgResp.Content) == 0 {
+		return nil, fmt.Errorf("anthropic API returned no content")
+	}
+
+	return &GenerateResponse{
+		Text:         msgResp.Content[0].Text,
+		TokensUsed:   msgResp.Usage.InputTokens + msgResp.Usage.OutputTokens,
+		Model:        msgResp.Model,
+		FinishReason: msgResp.StopReason,
+		ResponseTime: responseTime,
+	}, nil
+}
+
+// GenerateStream satisfies the Provider interface without true incremental
+// output: the Messages API call above runs to completion and the whole
+// response is delivered as a single done chunk.
+func (c *AnthropicClient) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	resp, err := c.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return onChunk(StreamChunk{Text: resp.Text, Done: true, TokensUsed: resp.TokensUsed})
+}
+
+// Chat generates a reply to a multi-turn conversation using the Messages
+// API's native messages array. The Messages API takes a standing instruction
+// as a top-level "system" string rather than a message in the array, so any
+// system-role messages are pulled out and concatenated into that field.
+func (c *AnthropicClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = c.config.DefaultMaxTokens
+	}
+
+	temperature := req.Temperature
+	if temperature <= 0 {
+		temperature = c.config.DefaultTemperature
+	}
+
+	var system strings.Builder
+	messages := make([]anthropicMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			if system.Len() > 0 {
+				system.WriteString("\n")
+			}
+			system.WriteString(m.Content)
+			continue
+		}
+		messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+
+	msgReq := &anthropicMessagesRequest{
+		Model:       model,
+		System:      system.String(),
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	}
+
+	startTime := time.Now()
+
+	reqBody, err := json.Marshal(msgReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/messages", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+	if c.apiKey != "" {
+		httpReq.Header.Set("x-api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseTime := time.Since(startTime)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp anthropicErrorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+			return nil, fmt.Errorf("anthropic API error (status %d): %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("anthropic API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var msgResp anthropicMessagesResponse
+	if err := json.Unmarshal(body, &msgResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(msgResp.Content) == 0 {
+		return nil, fmt.Errorf("anthropic API returned no content")
+	}
+
+	return &ChatResponse{
+		Message:      ChatMessage{Role: "assistant", Content: msgResp.Content[0].Text},
+		TokensUsed:   msgResp.Usage.InputTokens + msgResp.Usage.OutputTokens,
+		Model:        msgResp.Model,
+		FinishReason: msgResp.StopReason,
+		ResponseTime: responseTime,
+	}, nil
+}
+
+// ListModels returns a static list of known Claude models. Anthropic's API
+// has no public models endpoint, unlike OpenAI/Ollama.
+func (c *AnthropicClient) ListModels(ctx context.Context) ([]string, error) {
+	return []string{
+		"claude-3-5-sonnet-20241022",
+		"claude-3-5-haiku-20241022",
+		"claude-3-opus-20240229",
+		"claude-3-haiku-20240307",
+	}, nil
+}
+
+// Health checks if the API is reachable and the API key is valid by sending
+// a minimal Messages request.
+func (c *AnthropicClient) Health(ctx context.Context) error {
+	_, err := c.Generate(ctx, &GenerateRequest{
+		Model:     c.config.DefaultModel,
+		Prompt:    "ping",
+		MaxTokens: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("anthropic endpoint not reachable: %w", err)
+	}
+	return nil
+}
+
+// Close cleans up resources (no-op for HTTP client).
+func (c *AnthropicClient) Close() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewAnthropicClientDefaultBaseURL(t *testing.T) {
+	client := NewAnthropicClient(nil, "test-key")
+
+	if client.Name() != "anthropic" {
+		t.Errorf("expected name to be 'anthropic', got %s", client.Name())
+	}
+
+	if client.baseURL != defaultAnthropicBaseURL {
+		t.Errorf("expected base URL to be %s, got %s", defaultAnthropicBaseURL, client.baseURL)
+	}
+}
+
+func TestAnthropicClient_Generate_Success(t *testing.T) {
+	var gotAPIKey, gotVersion string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages" {
+			t.Errorf("expected path /messages, got %s", r.URL.Path)
+		}
+		gotAPIKey = r.Header.Get("x-api-key")
+		gotVersion = r.Header.Get("anthropic-version")
+
+		var req anthropicMessagesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		resp := anthropicMessagesResponse{Model: req.Model, StopReason: "end_turn"}
+		resp.Content = []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}{{Type: "text", Text: "Hello!"}}
+		resp.Usage.InputTokens = 3
+		resp.Usage.OutputTokens = 2
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewAnthropicClient(&ProviderConfig{BaseURL: server.URL}, "secret-key")
+
+	resp, err := client.Generate(context.Background(), &GenerateRequest{
+		Model:  "claude-3-5-haiku-20241022",
+		Prompt: "Hi",
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if resp.Text != "Hello!" {
+		t.Errorf("expected text 'Hello!', got %s", resp.Text)
+	}
+	if resp.TokensUsed != 5 {
+		t.Errorf("expected 5 tokens used, got %d", resp.TokensUsed)
+	}
+	if gotAPIKey != "secret-key" {
+		t.Errorf("expected x-api-key 'secret-key', got %q", gotAPIKey)
+	}
+	if gotVersion != anthropicAPIVersion {
+		t.Errorf("expected anthropic-version %q, got %q", anthropicAPIVersion, gotVersion)
+	}
+}
+
+func TestAnthropicClient_Generate_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(anthropicErrorResponse{
+			Error: struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+			}{Message: "invalid x-api-key", Type: "authentication_error"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewAnthropicClient(&ProviderConfig{BaseURL: server.URL}, "bad-key")
+	_, err := client.Generate(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err == nil {
+		t.Fatal("expected error for unauthorized response")
+	}
+}
@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitState is a circuit breaker's current disposition toward letting
+// calls through.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreakerConfig tunes how many failures within how long a window
+// trip a breaker, and how long it stays open before allowing a half-open
+// probe through.
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	Window           time.Duration
+	CooldownPeriod   time.Duration
+}
+
+// DefaultCircuitBreakerConfig is the tuning the summary generator gives the
+// breaker of every provider it puts in a FallbackProvider chain: three failures within a minute trips the breaker, and
+// it stays open for 30 seconds before the next call is let through as a
+// half-open probe.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: 3,
+		Window:           1 * time.Minute,
+		CooldownPeriod:   30 * time.Second,
+	}
+}
+
+// CircuitBreaker tracks one provider's recent failures and decides whether
+// a call to it should be attempted, short-circuited, or sent through as a
+// half-open probe after its cooldown has elapsed.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    circuitState
+	failures []time.Time
+	openedAt time.Time
+}
+
+// NewCircuitBreaker creates a CircuitBreaker tuned by config.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{config: config}
+}
+
+// Allow reports whether a call should be attempted right now. A closed
+// breaker always allows calls. An open breaker allows exactly one call
+// through as a half-open probe once its cooldown has elapsed, and turns
+// away every other caller until that probe's outcome is recorded via
+// RecordSuccess or RecordFailure.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitClosed:
+		return true
+	case circuitHalfOpen:
+		return false
+	default: // circuitOpen
+		if time.Since(cb.openedAt) < cb.config.CooldownPeriod {
+			return false
+		}
+		cb.state = circuitHalfOpen
+		return true
+	}
+}
+
+// RecordSuccess closes the breaker and clears its failure history.
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = circuitClosed
+	cb.failures = nil
+}
+
+// RecordFailure records a failure. A failed half-open probe reopens the
+// breaker immediately with a fresh cooldown; otherwise the breaker opens
+// once FailureThreshold failures have landed within Window.
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == circuitHalfOpen {
+		cb.open()
+		return
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-cb.config.Window)
+	kept := cb.failures[:0]
+	for _, ts := range cb.failures {
+		if ts.After(cutoff) {
+			kept = append(kept, ts)
+		}
+	}
+	cb.failures = append(kept, now)
+
+	if len(cb.failures) >= cb.config.FailureThreshold {
+		cb.open()
+	}
+}
+
+// open transitions the breaker to open and starts its cooldown. Callers
+// must hold cb.mu.
+func (cb *CircuitBreaker) open() {
+	cb.state = circuitOpen
+	cb.openedAt = time.Now()
+	cb.failures = nil
+}
+
+// State reports the breaker's current state as "closed", "open", or
+// "half-open", for logging and metrics. It does not trigger the
+// open-to-half-open cooldown transition that Allow performs.
+func (cb *CircuitBreaker) State() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_StartsClosed(t *testing.T) {
+	cb := NewCircuitBreaker(DefaultCircuitBreakerConfig())
+
+	if !cb.Allow() {
+		t.Error("expected a fresh breaker to allow calls")
+	}
+	if cb.State() != "closed" {
+		t.Errorf("expected state 'closed', got %q", cb.State())
+	}
+}
+
+func TestCircuitBreaker_OpensAfterThresholdFailuresWithinWindow(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 3,
+		Window:           time.Minute,
+		CooldownPeriod:   time.Minute,
+	})
+
+	cb.RecordFailure()
+	cb.RecordFailure()
+	if cb.State() != "closed" {
+		t.Fatalf("expected breaker to stay closed below threshold, got %q", cb.State())
+	}
+
+	cb.RecordFailure()
+	if cb.State() != "open" {
+		t.Fatalf("expected breaker to open at threshold, got %q", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("expected an open breaker within its cooldown to deny calls")
+	}
+}
+
+func TestCircuitBreaker_FailuresOutsideWindowDontAccumulate(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		Window:           10 * time.Millisecond,
+		CooldownPeriod:   time.Minute,
+	})
+
+	cb.RecordFailure()
+	time.Sleep(20 * time.Millisecond)
+	cb.RecordFailure()
+
+	if cb.State() != "closed" {
+		t.Errorf("expected old failures to have aged out of the window, got %q", cb.State())
+	}
+}
+
+func TestCircuitBreaker_AllowsHalfOpenProbeAfterCooldown(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		Window:           time.Minute,
+		CooldownPeriod:   5 * time.Millisecond,
+	})
+
+	cb.RecordFailure()
+	if cb.State() != "open" {
+		t.Fatalf("expected breaker to open, got %q", cb.State())
+	}
+
+	if cb.Allow() {
+		t.Fatal("expected the breaker to deny calls before its cooldown elapses")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if !cb.Allow() {
+		t.Fatal("expected a half-open probe to be allowed once the cooldown elapses")
+	}
+	if cb.State() != "half-open" {
+		t.Errorf("expected state 'half-open' after the probe is let through, got %q", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("expected a second concurrent caller to be denied while a probe is in flight")
+	}
+}
+
+func TestCircuitBreaker_SuccessfulProbeCloses(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		Window:           time.Minute,
+		CooldownPeriod:   5 * time.Millisecond,
+	})
+
+	cb.RecordFailure()
+	time.Sleep(10 * time.Millisecond)
+	cb.Allow() // consume the half-open probe
+
+	cb.RecordSuccess()
+	if cb.State() != "closed" {
+		t.Errorf("expected a successful probe to close the breaker, got %q", cb.State())
+	}
+	if !cb.Allow() {
+		t.Error("expected the closed breaker to allow further calls")
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopensImmediately(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		Window:           time.Minute,
+		CooldownPeriod:   5 * time.Millisecond,
+	})
+
+	cb.RecordFailure()
+	time.Sleep(10 * time.Millisecond)
+	cb.Allow() // consume the half-open probe
+
+	cb.RecordFailure()
+	if cb.State() != "open" {
+		t.Errorf("expected a failed probe to reopen the breaker, got %q", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("expected the freshly reopened breaker to deny calls during its new cooldown")
+	}
+}
@@ -0,0 +1,58 @@
+package llm
+
+// Conversation is an in-memory, multi-turn chat history for a single
+// consumer (e.g. one IRC channel), used to build ChatRequests for Chat
+// instead of one-shot prompts. It retains at most MemoryLimit non-system
+// messages, dropping the oldest once exceeded, and always carries
+// SystemPrompt as the conversation's standing instruction regardless of how
+// much history has rolled off.
+type Conversation struct {
+	SystemPrompt string
+	MemoryLimit  int
+
+	messages []ChatMessage
+}
+
+// NewConversation returns a Conversation carrying systemPrompt as its
+// standing instruction. memoryLimit caps how many non-system messages are
+// retained; <= 0 means unlimited.
+func NewConversation(systemPrompt string, memoryLimit int) *Conversation {
+	return &Conversation{SystemPrompt: systemPrompt, MemoryLimit: memoryLimit}
+}
+
+// Append adds a turn to the conversation, trimming the oldest messages once
+// MemoryLimit is exceeded.
+func (c *Conversation) Append(role, content string) {
+	c.messages = append(c.messages, ChatMessage{Role: role, Content: content})
+	if c.MemoryLimit > 0 && len(c.messages) > c.MemoryLimit {
+		c.messages = c.messages[len(c.messages)-c.MemoryLimit:]
+	}
+}
+
+// Messages returns the message list a ChatRequest should carry: SystemPrompt
+// (if set) followed by the retained history, oldest first.
+func (c *Conversation) Messages() []ChatMessage {
+	if c.SystemPrompt == "" {
+		out := make([]ChatMessage, len(c.messages))
+		copy(out, c.messages)
+		return out
+	}
+
+	out := make([]ChatMessage, 0, len(c.messages)+1)
+	out = append(out, ChatMessage{Role: "system", Content: c.SystemPrompt})
+	out = append(out, c.messages...)
+	return out
+}
+
+// Request builds a ChatRequest from the conversation's current history for
+// model, leaving MaxTokens/Temperature at zero so the provider falls back
+// to its own configured defaults.
+func (c *Conversation) Request(model string) *ChatRequest {
+	return &ChatRequest{Model: model, Messages: c.Messages()}
+}
+
+// Reset clears the conversation's history, keeping SystemPrompt and
+// MemoryLimit.
+func (c *Conversation) Reset() {
+	c.messages = nil
+}
@@ -0,0 +1,79 @@
+package llm
+
+import "testing"
+
+func TestConversationMessagesIncludesSystemPrompt(t *testing.T) {
+	conv := NewConversation("you are a helpful IRC bot", 10)
+	conv.Append("user", "summarize today")
+	conv.Append("assistant", "sure, here's the gist")
+
+	messages := conv.Messages()
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages (system + 2 turns), got %d: %+v", len(messages), messages)
+	}
+	if messages[0].Role != "system" || messages[0].Content != "you are a helpful IRC bot" {
+		t.Errorf("expected first message to be the system prompt, got %+v", messages[0])
+	}
+	if messages[1].Role != "user" || messages[2].Role != "assistant" {
+		t.Errorf("expected user then assistant turns, got %+v", messages[1:])
+	}
+}
+
+func TestConversationWithoutSystemPromptOmitsIt(t *testing.T) {
+	conv := NewConversation("", 10)
+	conv.Append("user", "hi")
+
+	messages := conv.Messages()
+	if len(messages) != 1 || messages[0].Role != "user" {
+		t.Fatalf("expected just the user turn, got %+v", messages)
+	}
+}
+
+func TestConversationTrimsToMemoryLimit(t *testing.T) {
+	conv := NewConversation("", 2)
+	conv.Append("user", "one")
+	conv.Append("assistant", "two")
+	conv.Append("user", "three")
+
+	messages := conv.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("expected memory limit to cap history at 2, got %d: %+v", len(messages), messages)
+	}
+	if messages[0].Content != "two" || messages[1].Content != "three" {
+		t.Errorf("expected the oldest message to be dropped, got %+v", messages)
+	}
+}
+
+func TestConversationUnlimitedMemoryKeepsEverything(t *testing.T) {
+	conv := NewConversation("", 0)
+	for i := 0; i < 5; i++ {
+		conv.Append("user", "msg")
+	}
+	if len(conv.Messages()) != 5 {
+		t.Errorf("expected unlimited memory to keep all 5 messages, got %d", len(conv.Messages()))
+	}
+}
+
+func TestConversationRequestUsesCurrentHistory(t *testing.T) {
+	conv := NewConversation("system", 10)
+	conv.Append("user", "hi")
+
+	req := conv.Request("llama3.2")
+	if req.Model != "llama3.2" {
+		t.Errorf("expected model to be llama3.2, got %q", req.Model)
+	}
+	if len(req.Messages) != 2 {
+		t.Fatalf("expected system + user messages, got %+v", req.Messages)
+	}
+}
+
+func TestConversationResetClearsHistory(t *testing.T) {
+	conv := NewConversation("system", 10)
+	conv.Append("user", "hi")
+	conv.Reset()
+
+	messages := conv.Messages()
+	if len(messages) != 1 || messages[0].Role != "system" {
+		t.Errorf("expected only the system prompt to remain after Reset, got %+v", messages)
+	}
+}
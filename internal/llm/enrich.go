@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"irccloud-watcher/internal/storage"
+)
+
+// Enricher produces LLM-derived metadata for a stored message: a short
+// summary, detected language, sentiment, and extracted entities/URLs.
+type Enricher interface {
+	Enrich(ctx context.Context, msg *storage.Message) (*storage.Enrichment, error)
+}
+
+// enrichmentResult is the JSON shape the enrichment prompt asks the model
+// to respond with.
+type enrichmentResult struct {
+	Summary   string   `json:"summary"`
+	Language  string   `json:"language"`
+	Sentiment string   `json:"sentiment"`
+	Entities  []string `json:"entities"`
+}
+
+// LLMEnricher implements Enricher on top of a Provider, asking it to return
+// a small JSON object describing the message.
+type LLMEnricher struct {
+	provider Provider
+	model    string
+}
+
+// NewLLMEnricher builds an Enricher backed by provider, using model for
+// every request.
+func NewLLMEnricher(provider Provider, model string) *LLMEnricher {
+	return &LLMEnricher{provider: provider, model: model}
+}
+
+// Enrich asks the provider to summarize, classify, and extract entities
+// from msg.Message, parsing its response as JSON into a storage.Enrichment.
+func (e *LLMEnricher) Enrich(ctx context.Context, msg *storage.Message) (*storage.Enrichment, error) {
+	resp, err := e.provider.Generate(ctx, &GenerateRequest{
+		Model:       e.model,
+		Prompt:      enrichmentPrompt(msg.Message),
+		MaxTokens:   256,
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("enrichment generation failed: %w", err)
+	}
+
+	var result enrichmentResult
+	if err := json.Unmarshal([]byte(extractJSON(resp.Text)), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse enrichment response: %w", err)
+	}
+
+	return &storage.Enrichment{
+		EID:       msg.EID,
+		Channel:   msg.Channel,
+		Summary:   result.Summary,
+		Language:  result.Language,
+		Sentiment: result.Sentiment,
+		Entities:  strings.Join(result.Entities, ","),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// enrichmentPrompt builds the prompt asking the model for a strict JSON
+// object, so Enrich can parse it without a more elaborate structured-output
+// integration per provider.
+func enrichmentPrompt(message string) string {
+	return fmt.Sprintf(`Analyze the following chat message and respond with ONLY a JSON object
+with the keys "summary" (one short sentence), "language" (ISO 639-1 code),
+"sentiment" ("positive", "neutral", or "negative"), and "entities" (an array
+of named entities or URLs mentioned, possibly empty). Do not include any
+other text.
+
+Message: %s`, message)
+}
+
+// extractJSON trims any leading/trailing prose a model adds around the JSON
+// object it was asked for, returning the substring from the first '{' to
+// the last '}'.
+func extractJSON(text string) string {
+	start := strings.IndexByte(text, '{')
+	end := strings.LastIndexByte(text, '}')
+	if start == -1 || end == -1 || end < start {
+		return text
+	}
+	return text[start : end+1]
+}
@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"irccloud-watcher/internal/storage"
+)
+
+// mockProvider for testing LLMEnricher.
+type mockProvider struct {
+	response   string
+	shouldFail bool
+}
+
+func (m *mockProvider) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if m.shouldFail {
+		return nil, errors.New("mock provider failure")
+	}
+	return &GenerateResponse{Text: m.response, Model: req.Model}, nil
+}
+
+func (m *mockProvider) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	resp, err := m.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return onChunk(StreamChunk{Text: resp.Text, Done: true, TokensUsed: resp.TokensUsed})
+}
+
+func (m *mockProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if m.shouldFail {
+		return nil, errors.New("mock provider failure")
+	}
+	return &ChatResponse{Message: ChatMessage{Role: "assistant", Content: m.response}, Model: req.Model}, nil
+}
+
+func (m *mockProvider) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+func (m *mockProvider) Health(ctx context.Context) error                 { return nil }
+func (m *mockProvider) Name() string                                     { return "mock" }
+func (m *mockProvider) Close() error                                     { return nil }
+
+func TestLLMEnricherParsesWellFormedJSONResponse(t *testing.T) {
+	provider := &mockProvider{response: `{"summary":"asked about lunch","language":"en","sentiment":"neutral","entities":["lunch"]}`}
+	enricher := NewLLMEnricher(provider, "test-model")
+
+	msg := &storage.Message{EID: 42, Channel: "#test", Message: "what's for lunch?"}
+	enrichment, err := enricher.Enrich(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if enrichment.EID != 42 || enrichment.Channel != "#test" {
+		t.Errorf("expected enrichment keyed to the source message, got %+v", enrichment)
+	}
+	if enrichment.Summary != "asked about lunch" {
+		t.Errorf("expected summary to match, got %q", enrichment.Summary)
+	}
+	if enrichment.Language != "en" {
+		t.Errorf("expected language 'en', got %q", enrichment.Language)
+	}
+	if enrichment.Sentiment != "neutral" {
+		t.Errorf("expected sentiment 'neutral', got %q", enrichment.Sentiment)
+	}
+	if enrichment.Entities != "lunch" {
+		t.Errorf("expected entities 'lunch', got %q", enrichment.Entities)
+	}
+}
+
+func TestLLMEnricherTrimsSurroundingProseAroundJSON(t *testing.T) {
+	provider := &mockProvider{response: "Sure, here you go:\n{\"summary\":\"hi\",\"language\":\"en\",\"sentiment\":\"positive\",\"entities\":[]}\nHope that helps!"}
+	enricher := NewLLMEnricher(provider, "test-model")
+
+	enrichment, err := enricher.Enrich(context.Background(), &storage.Message{EID: 1, Message: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if enrichment.Summary != "hi" {
+		t.Errorf("expected summary 'hi', got %q", enrichment.Summary)
+	}
+}
+
+func TestLLMEnricherPropagatesProviderError(t *testing.T) {
+	enricher := NewLLMEnricher(&mockProvider{shouldFail: true}, "test-model")
+
+	if _, err := enricher.Enrich(context.Background(), &storage.Message{EID: 1, Message: "hi"}); err == nil {
+		t.Fatal("expected an error when the provider fails")
+	}
+}
+
+func TestLLMEnricherReturnsErrorOnMalformedResponse(t *testing.T) {
+	enricher := NewLLMEnricher(&mockProvider{response: "not json at all"}, "test-model")
+
+	if _, err := enricher.Enrich(context.Background(), &storage.Message{EID: 1, Message: "hi"}); err == nil {
+		t.Fatal("expected an error when the response isn't parseable JSON")
+	}
+}
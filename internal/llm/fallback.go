@@ -0,0 +1,210 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"irccloud-watcher/internal/metrics"
+)
+
+// FallbackEntry pairs a named Provider with the CircuitBreaker tracking its
+// health. Callers building multiple chains from the same set of providers
+// (e.g. a per-channel override reordering a subset of a larger pool) should
+// share one CircuitBreaker per provider name across every FallbackProvider
+// they build, so a failure recorded via one chain is visible to every other
+// chain that includes the same provider.
+type FallbackEntry struct {
+	Name     string
+	Provider Provider
+	Breaker  *CircuitBreaker
+}
+
+// FallbackProvider wraps an ordered list of providers and tries them in
+// turn on every call, skipping any whose circuit breaker is currently open
+// and returning the first success. It satisfies Provider itself, so it can
+// be used anywhere a single Provider is expected.
+type FallbackProvider struct {
+	entries []FallbackEntry
+}
+
+// NewFallbackProvider builds a FallbackProvider that tries entries in
+// order.
+func NewFallbackProvider(entries []FallbackEntry) *FallbackProvider {
+	return &FallbackProvider{entries: entries}
+}
+
+// GenerateNamed tries each provider in order, skipping ones whose breaker
+// is open, and returns the first successful generation along with the name
+// of the provider that produced it.
+func (f *FallbackProvider) GenerateNamed(ctx context.Context, req *GenerateRequest) (*GenerateResponse, string, error) {
+	var lastErr error
+	attempted := false
+
+	for _, e := range f.entries {
+		if !e.Breaker.Allow() {
+			continue
+		}
+		attempted = true
+
+		start := time.Now()
+		resp, err := e.Provider.Generate(ctx, req)
+		if err != nil {
+			e.Breaker.RecordFailure()
+			metrics.LLMErrorsTotal.WithLabelValues(e.Name).Inc()
+			metrics.LLMGenerateDuration.WithLabelValues(e.Name, requestModel(req.Model)).Observe(time.Since(start).Seconds())
+			lastErr = fmt.Errorf("provider %q: %w", e.Name, err)
+			continue
+		}
+
+		e.Breaker.RecordSuccess()
+		metrics.LLMTokensUsedTotal.WithLabelValues(e.Name, resp.Model).Add(float64(resp.TokensUsed))
+		metrics.LLMGenerateDuration.WithLabelValues(e.Name, resp.Model).Observe(time.Since(start).Seconds())
+		return resp, e.Name, nil
+	}
+
+	return nil, "", f.exhaustedErr(attempted, lastErr)
+}
+
+// requestModel reports the model a failed call was attempted against, for
+// metrics labeling: a provider that never got as far as picking its own
+// default model still needs a non-empty label value.
+func requestModel(model string) string {
+	if model == "" {
+		return "default"
+	}
+	return model
+}
+
+// ChatNamed is GenerateNamed's counterpart for multi-turn Chat calls.
+func (f *FallbackProvider) ChatNamed(ctx context.Context, req *ChatRequest) (*ChatResponse, string, error) {
+	var lastErr error
+	attempted := false
+
+	for _, e := range f.entries {
+		if !e.Breaker.Allow() {
+			continue
+		}
+		attempted = true
+
+		start := time.Now()
+		resp, err := e.Provider.Chat(ctx, req)
+		if err != nil {
+			e.Breaker.RecordFailure()
+			metrics.LLMErrorsTotal.WithLabelValues(e.Name).Inc()
+			metrics.LLMGenerateDuration.WithLabelValues(e.Name, requestModel(req.Model)).Observe(time.Since(start).Seconds())
+			lastErr = fmt.Errorf("provider %q: %w", e.Name, err)
+			continue
+		}
+
+		e.Breaker.RecordSuccess()
+		metrics.LLMTokensUsedTotal.WithLabelValues(e.Name, resp.Model).Add(float64(resp.TokensUsed))
+		metrics.LLMGenerateDuration.WithLabelValues(e.Name, resp.Model).Observe(time.Since(start).Seconds())
+		return resp, e.Name, nil
+	}
+
+	return nil, "", f.exhaustedErr(attempted, lastErr)
+}
+
+// Generate satisfies Provider by discarding GenerateNamed's provider-name
+// return value.
+func (f *FallbackProvider) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	resp, _, err := f.GenerateNamed(ctx, req)
+	return resp, err
+}
+
+// Chat satisfies Provider by discarding ChatNamed's provider-name return
+// value.
+func (f *FallbackProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	resp, _, err := f.ChatNamed(ctx, req)
+	return resp, err
+}
+
+// GenerateStream satisfies Provider without mid-stream fallover: it streams
+// from the first provider whose breaker allows a call, moving on to the next
+// provider only if that one fails before delivering any chunk, since
+// switching providers partway through a streamed response would mean
+// splicing two unrelated generations together.
+func (f *FallbackProvider) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	var lastErr error
+	attempted := false
+
+	for _, e := range f.entries {
+		if !e.Breaker.Allow() {
+			continue
+		}
+		attempted = true
+
+		delivered := false
+		err := e.Provider.GenerateStream(ctx, req, func(sc StreamChunk) error {
+			delivered = true
+			return onChunk(sc)
+		})
+		if err != nil {
+			e.Breaker.RecordFailure()
+			metrics.LLMErrorsTotal.WithLabelValues(e.Name).Inc()
+			lastErr = fmt.Errorf("provider %q: %w", e.Name, err)
+			if delivered {
+				return lastErr
+			}
+			continue
+		}
+
+		e.Breaker.RecordSuccess()
+		return nil
+	}
+
+	return f.exhaustedErr(attempted, lastErr)
+}
+
+// ListModels delegates to the chain's first provider.
+func (f *FallbackProvider) ListModels(ctx context.Context) ([]string, error) {
+	if len(f.entries) == 0 {
+		return nil, fmt.Errorf("no providers configured")
+	}
+	return f.entries[0].Provider.ListModels(ctx)
+}
+
+// Health delegates to the chain's first provider.
+func (f *FallbackProvider) Health(ctx context.Context) error {
+	if len(f.entries) == 0 {
+		return fmt.Errorf("no providers configured")
+	}
+	return f.entries[0].Provider.Health(ctx)
+}
+
+// Name returns a display name listing every wrapped provider in order.
+func (f *FallbackProvider) Name() string {
+	return fmt.Sprintf("fallback(%s)", f.names())
+}
+
+// Close closes every wrapped provider, returning the first error if any
+// fail to close cleanly.
+func (f *FallbackProvider) Close() error {
+	var firstErr error
+	for _, e := range f.entries {
+		if err := e.Provider.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f *FallbackProvider) names() string {
+	names := make([]string, len(f.entries))
+	for i, e := range f.entries {
+		names[i] = e.Name
+	}
+	return strings.Join(names, ",")
+}
+
+// exhaustedErr builds the error returned once every entry has been tried
+// or skipped: it distinguishes "nothing was even attempted" (every breaker
+// open) from "every attempt failed" (lastErr set).
+func (f *FallbackProvider) exhaustedErr(attempted bool, lastErr error) error {
+	if !attempted {
+		return fmt.Errorf("all providers are unavailable (circuit breakers open): %s", f.names())
+	}
+	return fmt.Errorf("all providers failed: %w", lastErr)
+}
@@ -0,0 +1,176 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fallbackTestProvider is a minimal Provider stub whose Generate/Chat calls
+// always succeed or always fail, so tests can assemble chains of providers
+// with a known outcome each.
+type fallbackTestProvider struct {
+	name      string
+	shouldErr bool
+	calls     int
+}
+
+func (p *fallbackTestProvider) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	p.calls++
+	if p.shouldErr {
+		return nil, errors.New("boom")
+	}
+	return &GenerateResponse{Text: "ok from " + p.name, Model: "model-" + p.name}, nil
+}
+
+func (p *fallbackTestProvider) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	resp, err := p.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return onChunk(StreamChunk{Text: resp.Text, Done: true})
+}
+
+func (p *fallbackTestProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	p.calls++
+	if p.shouldErr {
+		return nil, errors.New("boom")
+	}
+	return &ChatResponse{Message: ChatMessage{Role: "assistant", Content: "ok from " + p.name}, Model: "model-" + p.name}, nil
+}
+
+func (p *fallbackTestProvider) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+func (p *fallbackTestProvider) Health(ctx context.Context) error                 { return nil }
+func (p *fallbackTestProvider) Name() string                                     { return p.name }
+func (p *fallbackTestProvider) Close() error                                     { return nil }
+
+func fallbackEntries(providers ...*fallbackTestProvider) []FallbackEntry {
+	entries := make([]FallbackEntry, len(providers))
+	for i, p := range providers {
+		entries[i] = FallbackEntry{
+			Name:     p.name,
+			Provider: p,
+			Breaker:  NewCircuitBreaker(DefaultCircuitBreakerConfig()),
+		}
+	}
+	return entries
+}
+
+func TestFallbackProvider_GenerateUsesFirstHealthyProvider(t *testing.T) {
+	primary := &fallbackTestProvider{name: "primary"}
+	secondary := &fallbackTestProvider{name: "secondary"}
+	fb := NewFallbackProvider(fallbackEntries(primary, secondary))
+
+	resp, name, err := fb.GenerateNamed(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "primary" {
+		t.Errorf("expected the primary provider to be used, got %q", name)
+	}
+	if resp.Text != "ok from primary" {
+		t.Errorf("unexpected response text: %q", resp.Text)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("expected secondary to be untouched, got %d calls", secondary.calls)
+	}
+}
+
+func TestFallbackProvider_FallsOverToNextProviderOnFailure(t *testing.T) {
+	primary := &fallbackTestProvider{name: "primary", shouldErr: true}
+	secondary := &fallbackTestProvider{name: "secondary"}
+	fb := NewFallbackProvider(fallbackEntries(primary, secondary))
+
+	resp, name, err := fb.GenerateNamed(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "secondary" {
+		t.Errorf("expected fallover to secondary, got %q", name)
+	}
+	if resp.Text != "ok from secondary" {
+		t.Errorf("unexpected response text: %q", resp.Text)
+	}
+}
+
+func TestFallbackProvider_AllProvidersFailingReturnsError(t *testing.T) {
+	primary := &fallbackTestProvider{name: "primary", shouldErr: true}
+	secondary := &fallbackTestProvider{name: "secondary", shouldErr: true}
+	fb := NewFallbackProvider(fallbackEntries(primary, secondary))
+
+	_, _, err := fb.GenerateNamed(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err == nil {
+		t.Fatal("expected an error when every provider fails")
+	}
+}
+
+func TestFallbackProvider_SkipsProviderWithOpenBreaker(t *testing.T) {
+	primary := &fallbackTestProvider{name: "primary", shouldErr: true}
+	secondary := &fallbackTestProvider{name: "secondary"}
+
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Window: time.Minute, CooldownPeriod: time.Minute})
+	entries := []FallbackEntry{
+		{Name: "primary", Provider: primary, Breaker: breaker},
+		{Name: "secondary", Provider: secondary, Breaker: NewCircuitBreaker(DefaultCircuitBreakerConfig())},
+	}
+	fb := NewFallbackProvider(entries)
+
+	// First call trips primary's breaker and falls over to secondary.
+	if _, name, err := fb.GenerateNamed(context.Background(), &GenerateRequest{Prompt: "hi"}); err != nil || name != "secondary" {
+		t.Fatalf("expected first call to fall over to secondary, got name=%q err=%v", name, err)
+	}
+	if breaker.State() != "open" {
+		t.Fatalf("expected primary's breaker to have opened, got %q", breaker.State())
+	}
+
+	// A fresh call should skip primary (breaker open, cooldown not elapsed)
+	// without even attempting it.
+	callsBefore := primary.calls
+	if _, name, err := fb.GenerateNamed(context.Background(), &GenerateRequest{Prompt: "hi"}); err != nil || name != "secondary" {
+		t.Fatalf("expected second call to skip primary and use secondary, got name=%q err=%v", name, err)
+	}
+	if primary.calls != callsBefore {
+		t.Errorf("expected primary to not be called while its breaker is open, got %d calls", primary.calls)
+	}
+}
+
+func TestFallbackProvider_ChatFallsOverLikeGenerate(t *testing.T) {
+	primary := &fallbackTestProvider{name: "primary", shouldErr: true}
+	secondary := &fallbackTestProvider{name: "secondary"}
+	fb := NewFallbackProvider(fallbackEntries(primary, secondary))
+
+	resp, name, err := fb.ChatNamed(context.Background(), &ChatRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "secondary" {
+		t.Errorf("expected fallover to secondary, got %q", name)
+	}
+	if resp.Message.Content != "ok from secondary" {
+		t.Errorf("unexpected chat response: %q", resp.Message.Content)
+	}
+}
+
+func TestFallbackProvider_NameListsEveryWrappedProvider(t *testing.T) {
+	primary := &fallbackTestProvider{name: "primary"}
+	secondary := &fallbackTestProvider{name: "secondary"}
+	fb := NewFallbackProvider(fallbackEntries(primary, secondary))
+
+	if got, want := fb.Name(), "fallback(primary,secondary)"; got != want {
+		t.Errorf("expected Name() %q, got %q", want, got)
+	}
+}
+
+func TestFallbackProvider_GenerateInterfaceMethodDropsProviderName(t *testing.T) {
+	primary := &fallbackTestProvider{name: "primary"}
+	fb := NewFallbackProvider(fallbackEntries(primary))
+
+	resp, err := fb.Generate(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "ok from primary" {
+		t.Errorf("unexpected response text: %q", resp.Text)
+	}
+}
@@ -0,0 +1,352 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultGeminiBaseURL is used when no BaseURL override is configured.
+const defaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// GeminiClient implements the Provider interface for Google's Gemini
+// generateContent API.
+type GeminiClient struct {
+	config     *ProviderConfig
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+}
+
+// geminiGenerateRequest represents a request to the generateContent endpoint.
+type geminiGenerateRequest struct {
+	Contents          []geminiContent        `json:"contents"`
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+// geminiContent represents a single turn of content in a Gemini request. Role
+// is omitted for Generate's single-turn requests and for SystemInstruction,
+// where Gemini doesn't expect one; Chat sets it to "user" or "model" for
+// each turn in the conversation.
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+// geminiPart holds one piece of a geminiContent's text.
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+// geminiGenerationConfig controls sampling for a generateContent request.
+type geminiGenerationConfig struct {
+	MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
+	Temperature     float64 `json:"temperature,omitempty"`
+}
+
+// geminiGenerateResponse represents a response from the generateContent
+// endpoint.
+type geminiGenerateResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		TotalTokenCount int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// geminiErrorResponse represents an error response from the Gemini API.
+type geminiErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// geminiModelsResponse represents the response from the /models endpoint.
+type geminiModelsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+// NewGeminiClient creates a new GeminiClient.
+func NewGeminiClient(config *ProviderConfig, apiKey string) *GeminiClient {
+	if config == nil {
+		config = DefaultProviderConfig()
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultGeminiBaseURL
+	}
+
+	return &GeminiClient{
+		config:  config,
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// Name returns the provider name.
+func (c *GeminiClient) Name() string {
+	return "gemini"
+}
+
+// Generate generates text using the generateContent endpoint.
+func (c *GeminiClient) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = c.config.DefaultMaxTokens
+	}
+
+	temperature := req.Temperature
+	if temperature <= 0 {
+		temperature = c.config.DefaultTemperature
+	}
+
+	genReq := &geminiGenerateRequest{
+		Contents: []geminiContent{
+			{Parts: []geminiPart{{Text: req.Prompt}}},
+		},
+		GenerationConfig: geminiGenerationConfig{
+			MaxOutputTokens: maxTokens,
+			Temperature:     temperature,
+		},
+	}
+
+	startTime := time.Now()
+
+	reqBody, err := json.Marshal(genReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.baseURL, model, url.QueryEscape(c.apiKey))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseTime := time.Since(startTime)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp geminiErrorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+			return nil, fmt.Errorf("gemini API error (status %d): %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("gemini API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var genResp geminiGenerateResponse
+	if err := json.Unmarshal(body, &genResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(genResp.Candidates) == 0 || len(genResp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("gemini API returned no candidates")
+	}
+
+	return &GenerateResponse{
+		Text:         genResp.Candidates[0].Content.Parts[0].Text,
+		TokensUsed:   genResp.UsageMetadata.TotalTokenCount,
+		Model:        model,
+		FinishReason: genResp.Candidates[0].FinishReason,
+		ResponseTime: responseTime,
+	}, nil
+}
+
+// GenerateStream satisfies the Provider interface without true incremental
+// output: the generateContent call above runs to completion and the whole
+// response is delivered as a single done chunk.
+func (c *GeminiClient) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	resp, err := c.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return onChunk(StreamChunk{Text: resp.Text, Done: true, TokensUsed: resp.TokensUsed})
+}
+
+// Chat generates a reply to a multi-turn conversation using the
+// generateContent endpoint's native contents array. Gemini takes a standing
+// instruction as a separate systemInstruction field rather than a turn in
+// the array, so any system-role messages go there instead; remaining turns
+// are mapped to Gemini's "user"/"model" roles ("assistant" in is mapped to
+// "model" out).
+func (c *GeminiClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = c.config.DefaultMaxTokens
+	}
+
+	temperature := req.Temperature
+	if temperature <= 0 {
+		temperature = c.config.DefaultTemperature
+	}
+
+	var systemInstruction *geminiContent
+	contents := make([]geminiContent, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			if systemInstruction == nil {
+				systemInstruction = &geminiContent{}
+			}
+			systemInstruction.Parts = append(systemInstruction.Parts, geminiPart{Text: m.Content})
+			continue
+		}
+		role := m.Role
+		if role == "assistant" {
+			role = "model"
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: []geminiPart{{Text: m.Content}}})
+	}
+
+	genReq := &geminiGenerateRequest{
+		Contents:          contents,
+		SystemInstruction: systemInstruction,
+		GenerationConfig: geminiGenerationConfig{
+			MaxOutputTokens: maxTokens,
+			Temperature:     temperature,
+		},
+	}
+
+	startTime := time.Now()
+
+	reqBody, err := json.Marshal(genReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.baseURL, model, url.QueryEscape(c.apiKey))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseTime := time.Since(startTime)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp geminiErrorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+			return nil, fmt.Errorf("gemini API error (status %d): %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("gemini API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var genResp geminiGenerateResponse
+	if err := json.Unmarshal(body, &genResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(genResp.Candidates) == 0 || len(genResp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("gemini API returned no candidates")
+	}
+
+	return &ChatResponse{
+		Message:      ChatMessage{Role: "assistant", Content: genResp.Candidates[0].Content.Parts[0].Text},
+		TokensUsed:   genResp.UsageMetadata.TotalTokenCount,
+		Model:        model,
+		FinishReason: genResp.Candidates[0].FinishReason,
+		ResponseTime: responseTime,
+	}, nil
+}
+
+// ListModels returns available models from the /models endpoint.
+func (c *GeminiClient) ListModels(ctx context.Context) ([]string, error) {
+	endpoint := fmt.Sprintf("%s/models?key=%s", c.baseURL, url.QueryEscape(c.apiKey))
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", endpoint, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("gemini API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var modelsResp geminiModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	models := make([]string, len(modelsResp.Models))
+	for i, m := range modelsResp.Models {
+		models[i] = strings.TrimPrefix(m.Name, "models/")
+	}
+
+	return models, nil
+}
+
+// Health checks if the endpoint is available by listing models.
+func (c *GeminiClient) Health(ctx context.Context) error {
+	if _, err := c.ListModels(ctx); err != nil {
+		return fmt.Errorf("gemini endpoint not reachable: %w", err)
+	}
+	return nil
+}
+
+// Close cleans up resources (no-op for HTTP client).
+func (c *GeminiClient) Close() error {
+	return nil
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewGeminiClientDefaultBaseURL(t *testing.T) {
+	client := NewGeminiClient(nil, "test-key")
+
+	if client.Name() != "gemini" {
+		t.Errorf("expected name to be 'gemini', got %s", client.Name())
+	}
+
+	if client.baseURL != defaultGeminiBaseURL {
+		t.Errorf("expected base URL to be %s, got %s", defaultGeminiBaseURL, client.baseURL)
+	}
+}
+
+func TestGeminiClient_Generate_Success(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if !strings.Contains(r.URL.RawQuery, "key=test-key") {
+			t.Errorf("expected key query param, got %s", r.URL.RawQuery)
+		}
+
+		resp := geminiGenerateResponse{}
+		resp.Candidates = []struct {
+			Content      geminiContent `json:"content"`
+			FinishReason string        `json:"finishReason"`
+		}{
+			{
+				Content:      geminiContent{Parts: []geminiPart{{Text: "Hello!"}}},
+				FinishReason: "STOP",
+			},
+		}
+		resp.UsageMetadata.TotalTokenCount = 7
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewGeminiClient(&ProviderConfig{BaseURL: server.URL}, "test-key")
+
+	resp, err := client.Generate(context.Background(), &GenerateRequest{
+		Model:  "gemini-1.5-flash",
+		Prompt: "Hi",
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if resp.Text != "Hello!" {
+		t.Errorf("expected text 'Hello!', got %s", resp.Text)
+	}
+	if resp.TokensUsed != 7 {
+		t.Errorf("expected 7 tokens used, got %d", resp.TokensUsed)
+	}
+	if gotPath != "/models/gemini-1.5-flash:generateContent" {
+		t.Errorf("expected path '/models/gemini-1.5-flash:generateContent', got %s", gotPath)
+	}
+}
+
+func TestGeminiClient_Generate_NoCandidates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(geminiGenerateResponse{})
+	}))
+	defer server.Close()
+
+	client := NewGeminiClient(&ProviderConfig{BaseURL: server.URL}, "test-key")
+	_, err := client.Generate(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err == nil {
+		t.Fatal("expected error for empty candidates")
+	}
+}
@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -9,6 +10,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"irccloud-watcher/internal/httpretry"
 )
 
 // OllamaClient implements the Provider interface for Ollama.
@@ -20,11 +23,27 @@ type OllamaClient struct {
 
 // OllamaRequest represents a request to the Ollama API.
 type OllamaRequest struct {
-	Model   string         `json:"model"`
-	Prompt  string         `json:"prompt"`
-	Stream  bool           `json:"stream"`
-	Options *OllamaOptions `json:"options,omitempty"`
-	Context []int          `json:"context,omitempty"`
+	Model   string          `json:"model"`
+	Prompt  string          `json:"prompt"`
+	Stream  bool            `json:"stream"`
+	Format  json.RawMessage `json:"format,omitempty"`
+	Options *OllamaOptions  `json:"options,omitempty"`
+	Context []int           `json:"context,omitempty"`
+}
+
+// ollamaResponseFormat translates GenerateRequest.ResponseSchema into the
+// raw JSON Ollama's "format" field expects: the bare string "json" for
+// generic JSON mode, or the schema text itself (already valid JSON) for a
+// constrained schema. An empty schema leaves Format unset, so Ollama
+// generates free text as before.
+func ollamaResponseFormat(schema string) json.RawMessage {
+	if schema == "" {
+		return nil
+	}
+	if schema == "json" {
+		return json.RawMessage(`"json"`)
+	}
+	return json.RawMessage(schema)
 }
 
 // OllamaOptions represents optional parameters for Ollama requests.
@@ -74,6 +93,29 @@ type OllamaError struct {
 	Error string `json:"error"`
 }
 
+// OllamaChatMessage is a single message in an /api/chat request or response.
+type OllamaChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// OllamaChatRequest represents a request to the /api/chat endpoint.
+type OllamaChatRequest struct {
+	Model     string              `json:"model"`
+	Messages  []OllamaChatMessage `json:"messages"`
+	Stream    bool                `json:"stream"`
+	KeepAlive string              `json:"keep_alive,omitempty"`
+	Options   *OllamaOptions      `json:"options,omitempty"`
+}
+
+// OllamaChatResponse represents a non-streaming response from /api/chat.
+type OllamaChatResponse struct {
+	Model     string            `json:"model"`
+	Message   OllamaChatMessage `json:"message"`
+	Done      bool              `json:"done"`
+	EvalCount int               `json:"eval_count,omitempty"`
+}
+
 // NewOllamaClient creates a new OllamaClient.
 func NewOllamaClient(config *ProviderConfig) *OllamaClient {
 	if config == nil {
@@ -97,22 +139,76 @@ func NewOllamaClient(config *ProviderConfig) *OllamaClient {
 	}
 }
 
+// retryConfig translates config's RetryDelay/MaxRetryDelay/RetryAttempts
+// into an httpretry.Config, budgeting enough elapsed time for
+// RetryAttempts retries even at the worst-case per-attempt delay.
+func (c *OllamaClient) retryConfig() httpretry.Config {
+	return httpretry.Config{
+		InitialInterval: c.config.RetryDelay,
+		MaxInterval:     c.config.MaxRetryDelay,
+		MaxElapsedTime:  time.Duration(c.config.RetryAttempts+1) * c.config.MaxRetryDelay,
+	}
+}
+
 // Name returns the provider name.
 func (c *OllamaClient) Name() string {
 	return "ollama"
 }
 
-// Generate generates text using the Ollama API.
+// DefaultModel returns the model EnsureModel/Generate/Chat fall back to when
+// a caller's request leaves Model unset.
+func (c *OllamaClient) DefaultModel() string {
+	return c.config.DefaultModel
+}
+
+// Generate generates text using the Ollama API, waiting for the full
+// response. It's GenerateStream underneath, accumulating every chunk into a
+// single GenerateResponse for callers that don't care about incremental
+// output.
 func (c *OllamaClient) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
 	if req == nil {
 		return nil, fmt.Errorf("request cannot be nil")
 	}
 
+	startTime := time.Now()
+	var text strings.Builder
+	tokensUsed := 0
+
+	err := c.GenerateStream(ctx, req, func(chunk StreamChunk) error {
+		text.WriteString(chunk.Text)
+		if chunk.TokensUsed > 0 {
+			tokensUsed = chunk.TokensUsed
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	model := req.Model
 	if model == "" {
 		model = c.config.DefaultModel
 	}
 
+	return &GenerateResponse{
+		Text:         text.String(),
+		TokensUsed:   tokensUsed,
+		Model:        model,
+		FinishReason: "stop", // Ollama doesn't provide this explicitly
+		ResponseTime: time.Since(startTime),
+	}, nil
+}
+
+// GenerateStream generates text using the Ollama API, invoking onChunk for
+// each line of the application/x-ndjson response as it arrives rather than
+// blocking for the full response. ctx is checked between chunks, so a
+// canceled context abandons the generation mid-stream instead of waiting
+// for Ollama to finish.
+func (c *OllamaClient) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	if req == nil {
+		return fmt.Errorf("request cannot be nil")
+	}
+
 	maxTokens := req.MaxTokens
 	if maxTokens <= 0 {
 		maxTokens = c.config.DefaultMaxTokens
@@ -123,21 +219,77 @@ func (c *OllamaClient) Generate(ctx context.Context, req *GenerateRequest) (*Gen
 		temperature = c.config.DefaultTemperature
 	}
 
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+	}
+
 	ollamaReq := &OllamaRequest{
 		Model:  model,
 		Prompt: req.Prompt,
-		Stream: false,
+		Stream: true,
+		Format: ollamaResponseFormat(req.ResponseSchema),
 		Options: &OllamaOptions{
 			NumPredict:  maxTokens,
 			Temperature: temperature,
 		},
 	}
 
-	return c.generateWithRetry(ctx, ollamaReq)
+	return c.streamWithRetry(ctx, ollamaReq, onChunk)
+}
+
+// GenerateChunk is one piece of a Stream generation, sent on the channel
+// Stream returns. Done marks the final chunk; Err, if non-nil, means the
+// stream ended early and is always delivered alongside Done: true.
+type GenerateChunk struct {
+	Text string
+	Done bool
+	Err  error
+}
+
+// Stream is GenerateStream reshaped as a channel instead of a callback, for
+// callers that want to range over tokens (e.g. rendering them incrementally)
+// rather than supplying a closure. It's Ollama-specific, like EnsureModel
+// and DefaultModel, rather than a Provider method, since every other
+// provider already streams via GenerateStream and gains nothing from a
+// second, channel-shaped API. The returned channel is closed after the
+// final chunk; canceling ctx stops the underlying HTTP request (closing its
+// body) and ends the stream. The final chunk carrying the error is sent only
+// while the receiver is still reading, so it can be dropped once ctx is done.
+func (c *OllamaClient) Stream(ctx context.Context, req *GenerateRequest) (<-chan GenerateChunk, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	chunks := make(chan GenerateChunk)
+	go func() {
+		defer close(chunks)
+
+		err := c.GenerateStream(ctx, req, func(sc StreamChunk) error {
+			select {
+			case chunks <- GenerateChunk{Text: sc.Text, Done: sc.Done}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			select {
+			case chunks <- GenerateChunk{Done: true, Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return chunks, nil
 }
 
-// generateWithRetry performs the generation with retry logic.
-func (c *OllamaClient) generateWithRetry(ctx context.Context, req *OllamaRequest) (*GenerateResponse, error) {
+// streamWithRetry performs the streaming generation with retry logic. A
+// retried attempt starts the stream over from scratch and delivers its
+// chunks to onChunk again, so a caller whose onChunk accumulates text (like
+// Generate) ends up with an earlier partial attempt's output followed by the
+// retried attempt's output.
+func (c *OllamaClient) streamWithRetry(ctx context.Context, req *OllamaRequest, onChunk func(StreamChunk) error) error {
 	var lastErr error
 	retryDelay := c.config.RetryDelay
 
@@ -146,7 +298,7 @@ func (c *OllamaClient) generateWithRetry(ctx context.Context, req *OllamaRequest
 			// Wait before retrying
 			select {
 			case <-ctx.Done():
-				return nil, ctx.Err()
+				return ctx.Err()
 			case <-time.After(retryDelay):
 				// Exponential backoff
 				retryDelay *= 2
@@ -156,32 +308,144 @@ func (c *OllamaClient) generateWithRetry(ctx context.Context, req *OllamaRequest
 			}
 		}
 
-		resp, err := c.generate(ctx, req)
+		err := c.stream(ctx, req, onChunk)
 		if err == nil {
-			return resp, nil
+			return nil
 		}
 
 		lastErr = err
 
 		// Don't retry on context cancellation or certain errors
 		if ctx.Err() != nil {
-			return nil, ctx.Err()
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("failed after %d attempts: %w", c.config.RetryAttempts+1, lastErr)
+}
+
+// stream performs a single streaming generation attempt, scanning Ollama's
+// ndjson response line by line and invoking onChunk for each decoded line
+// until ctx is canceled, a line fails to decode, or Ollama reports
+// done:true.
+func (c *OllamaClient) stream(ctx context.Context, req *OllamaRequest, onChunk func(StreamChunk) error) error {
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/generate", bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		var ollamaErr OllamaError
+		if json.Unmarshal(body, &ollamaErr) == nil && ollamaErr.Error != "" {
+			return fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, ollamaErr.Error)
 		}
+		return fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	return nil, fmt.Errorf("failed after %d attempts: %w", c.config.RetryAttempts+1, lastErr)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var ollamaResp OllamaResponse
+		if err := json.Unmarshal(line, &ollamaResp); err != nil {
+			return fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+
+		tokenCount := ollamaResp.EvalCount
+		if tokenCount == 0 && ollamaResp.Response != "" {
+			// Rough estimate if not provided
+			tokenCount = len(strings.Fields(ollamaResp.Response))
+		}
+
+		if chunkErr := onChunk(StreamChunk{
+			Text:       ollamaResp.Response,
+			Done:       ollamaResp.Done,
+			TokensUsed: tokenCount,
+		}); chunkErr != nil {
+			return chunkErr
+		}
+
+		if ollamaResp.Done {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return nil
 }
 
-// generate performs a single generation request.
-func (c *OllamaClient) generate(ctx context.Context, req *OllamaRequest) (*GenerateResponse, error) {
+// Chat generates a reply to a multi-turn conversation using the /api/chat
+// endpoint, which takes a messages array instead of Generate's flat prompt
+// so a stable system instruction and prior turns can be sent alongside the
+// latest user message. Unlike Generate/GenerateStream, it asks Ollama for a
+// single non-streamed response, since callers driving a conversation loop
+// want the whole reply before deciding on the next turn.
+func (c *OllamaClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = c.config.DefaultMaxTokens
+	}
+
+	temperature := req.Temperature
+	if temperature <= 0 {
+		temperature = c.config.DefaultTemperature
+	}
+
+	messages := make([]OllamaChatMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = OllamaChatMessage{Role: m.Role, Content: m.Content}
+	}
+
+	chatReq := &OllamaChatRequest{
+		Model:     model,
+		Messages:  messages,
+		KeepAlive: req.KeepAlive,
+		Options: &OllamaOptions{
+			NumPredict:  maxTokens,
+			Temperature: temperature,
+		},
+	}
+
 	startTime := time.Now()
 
-	reqBody, err := json.Marshal(req)
+	reqBody, err := json.Marshal(chatReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/generate", bytes.NewReader(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/chat", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -196,8 +460,12 @@ func (c *OllamaClient) generate(ctx context.Context, req *OllamaRequest) (*Gener
 
 	responseTime := time.Since(startTime)
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
 		var ollamaErr OllamaError
 		if json.Unmarshal(body, &ollamaErr) == nil && ollamaErr.Error != "" {
 			return nil, fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, ollamaErr.Error)
@@ -205,40 +473,144 @@ func (c *OllamaClient) generate(ctx context.Context, req *OllamaRequest) (*Gener
 		return nil, fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	var ollamaResp OllamaResponse
-	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+	var chatResp OllamaChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	totalDuration := time.Duration(0)
-	if ollamaResp.TotalDuration > 0 {
-		totalDuration = time.Duration(ollamaResp.TotalDuration) * time.Nanosecond
+	return &ChatResponse{
+		Message:      ChatMessage{Role: chatResp.Message.Role, Content: chatResp.Message.Content},
+		TokensUsed:   chatResp.EvalCount,
+		Model:        model,
+		FinishReason: "stop", // Ollama doesn't provide this explicitly
+		ResponseTime: responseTime,
+	}, nil
+}
+
+// PullProgress reports one incremental status update from a model pull
+// triggered by EnsureModel, mirroring a single streamed line of Ollama's
+// /api/pull response.
+type PullProgress struct {
+	Status    string
+	Completed int64
+	Total     int64
+}
+
+// ollamaPullRequest represents a request to the /api/pull endpoint.
+type ollamaPullRequest struct {
+	Name   string `json:"name"`
+	Stream bool   `json:"stream"`
+}
+
+// ollamaPullStatus represents one streamed line of /api/pull's ndjson
+// progress output.
+type ollamaPullStatus struct {
+	Status    string `json:"status"`
+	Completed int64  `json:"completed,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// EnsureModel makes sure name is present and loaded into memory before a
+// caller depends on it: it checks /api/tags first, pulls name via
+// /api/pull if it's missing (streaming progress to onProgress, which may be
+// nil), then issues a tiny warmup generation to force it into memory. This
+// is meant to run once at startup or on a deploy, not before every
+// generation - callers that skip it trade a predictable startup delay for
+// an unpredictable cold-load the first time the model is actually needed.
+func (c *OllamaClient) EnsureModel(ctx context.Context, name string, onProgress func(PullProgress)) error {
+	present, err := c.hasModel(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to check installed models: %w", err)
 	}
 
-	tokenCount := ollamaResp.EvalCount
-	if tokenCount == 0 && ollamaResp.Response != "" {
-		// Rough estimate if not provided
-		tokenCount = len(strings.Fields(ollamaResp.Response))
+	if !present {
+		if err := c.pullModel(ctx, name, onProgress); err != nil {
+			return fmt.Errorf("failed to pull model %q: %w", name, err)
+		}
 	}
 
-	return &GenerateResponse{
-		Text:          ollamaResp.Response,
-		TokensUsed:    tokenCount,
-		Model:         ollamaResp.Model,
-		FinishReason:  "stop", // Ollama doesn't provide this explicitly
-		ResponseTime:  responseTime,
-		TotalDuration: totalDuration,
-	}, nil
+	if _, err := c.Generate(ctx, &GenerateRequest{Model: name, Prompt: "Hi", MaxTokens: 1}); err != nil {
+		return fmt.Errorf("failed to warm up model %q: %w", name, err)
+	}
+
+	return nil
 }
 
-// ListModels returns available models from the Ollama instance.
-func (c *OllamaClient) ListModels(ctx context.Context) ([]string, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/tags", http.NoBody)
+// hasModel reports whether name is already pulled, per /api/tags.
+func (c *OllamaClient) hasModel(ctx context.Context, name string) (bool, error) {
+	models, err := c.ListModels(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return false, err
+	}
+	for _, m := range models {
+		if m == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// pullModel streams a model pull from /api/pull, invoking onProgress for
+// each status line Ollama reports until the pull completes or fails.
+func (c *OllamaClient) pullModel(ctx context.Context, name string, onProgress func(PullProgress)) error {
+	reqBody, err := json.Marshal(ollamaPullRequest{Name: name, Stream: true})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/pull", bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
 	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var status ollamaPullStatus
+		if err := json.Unmarshal(line, &status); err != nil {
+			return fmt.Errorf("failed to decode pull progress: %w", err)
+		}
+		if status.Error != "" {
+			return fmt.Errorf("ollama pull error: %s", status.Error)
+		}
+
+		if onProgress != nil {
+			onProgress(PullProgress{Status: status.Status, Completed: status.Completed, Total: status.Total})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read pull progress: %w", err)
+	}
+	return nil
+}
+
+// ListModels returns available models from the Ollama instance.
+func (c *OllamaClient) ListModels(ctx context.Context) ([]string, error) {
+	resp, err := httpretry.Do(ctx, c.httpClient, c.retryConfig(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/tags", http.NoBody)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
@@ -264,12 +636,9 @@ func (c *OllamaClient) ListModels(ctx context.Context) ([]string, error) {
 
 // Health checks if the Ollama instance is available.
 func (c *OllamaClient) Health(ctx context.Context) error {
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/tags", http.NoBody)
-	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := httpretry.Do(ctx, c.httpClient, c.retryConfig(), func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/tags", http.NoBody)
+	})
 	if err != nil {
 		return fmt.Errorf("ollama instance not reachable: %w", err)
 	}
@@ -0,0 +1,132 @@
+//go:build integration
+
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// integrationTestModel is small enough to pull and generate from quickly in
+// CI; swap for another tiny instruct model if this one stops being mirrored.
+const integrationTestModel = "qwen2:0.5b"
+
+// startOllamaContainer brings up a real Ollama instance and returns a client
+// pointed at it, tearing the container down when the test completes.
+func startOllamaContainer(t *testing.T) *OllamaClient {
+	t.Helper()
+
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "ollama/ollama:latest",
+			ExposedPorts: []string{"11434/tcp"},
+			WaitingFor:   wait.ForListeningPort("11434/tcp").WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start ollama container: %v", err)
+	}
+	t.Cleanup(func() {
+		if termErr := container.Terminate(context.Background()); termErr != nil {
+			t.Logf("failed to terminate ollama container: %v", termErr)
+		}
+	})
+
+	endpoint, err := container.Endpoint(ctx, "http")
+	if err != nil {
+		t.Fatalf("failed to get ollama container endpoint: %v", err)
+	}
+
+	return NewOllamaClient(&ProviderConfig{
+		BaseURL: endpoint,
+		Timeout: 2 * time.Minute,
+	})
+}
+
+// TestOllamaClient_Integration exercises OllamaClient against a real Ollama
+// instance: health, EnsureModel's pull-and-warmup, ListModels, Generate, and
+// GenerateStream. It pulls a real model over the network, so it's gated
+// behind the integration build tag and run via `make test-integration`
+// rather than the default test suite.
+func TestOllamaClient_Integration(t *testing.T) {
+	client := startOllamaContainer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	t.Run("Health", func(t *testing.T) {
+		if err := client.Health(ctx); err != nil {
+			t.Fatalf("expected a freshly started Ollama instance to be healthy: %v", err)
+		}
+	})
+
+	t.Run("EnsureModel", func(t *testing.T) {
+		err := client.EnsureModel(ctx, integrationTestModel, func(p PullProgress) {
+			t.Logf("pull progress: %s (%d/%d)", p.Status, p.Completed, p.Total)
+		})
+		if err != nil {
+			t.Fatalf("EnsureModel failed: %v", err)
+		}
+	})
+
+	t.Run("ListModels", func(t *testing.T) {
+		models, err := client.ListModels(ctx)
+		if err != nil {
+			t.Fatalf("ListModels failed: %v", err)
+		}
+		found := false
+		for _, m := range models {
+			if m == integrationTestModel {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("expected %q to be listed after EnsureModel, got %v", integrationTestModel, models)
+		}
+	})
+
+	t.Run("Generate", func(t *testing.T) {
+		resp, err := client.Generate(ctx, &GenerateRequest{
+			Model:     integrationTestModel,
+			Prompt:    "Say hello in one word.",
+			MaxTokens: 16,
+		})
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if resp.Text == "" {
+			t.Error("expected a non-empty generation")
+		}
+	})
+
+	t.Run("GenerateStream", func(t *testing.T) {
+		var chunks int
+		var text strings.Builder
+
+		err := client.GenerateStream(ctx, &GenerateRequest{
+			Model:     integrationTestModel,
+			Prompt:    "Count from one to three.",
+			MaxTokens: 32,
+		}, func(chunk StreamChunk) error {
+			chunks++
+			text.WriteString(chunk.Text)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("GenerateStream failed: %v", err)
+		}
+		if chunks == 0 {
+			t.Error("expected at least one streamed chunk")
+		}
+		if text.Len() == 0 {
+			t.Error("expected non-empty streamed text")
+		}
+	})
+}
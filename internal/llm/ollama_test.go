@@ -3,6 +3,7 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -112,8 +113,10 @@ func TestOllamaClient_Generate_Success(t *testing.T) {
 		t.Errorf("expected 5 tokens used, got %d", resp.TokensUsed)
 	}
 
-	if resp.TotalDuration != 100*time.Millisecond {
-		t.Errorf("expected total duration 100ms, got %v", resp.TotalDuration)
+	// Generate now accumulates StreamChunks, which don't carry Ollama's
+	// total_duration stat, so TotalDuration is no longer populated.
+	if resp.TotalDuration != 0 {
+		t.Errorf("expected total duration to be unset, got %v", resp.TotalDuration)
 	}
 }
 
@@ -220,6 +223,348 @@ func TestOllamaClient_Generate_NilRequest(t *testing.T) {
 	}
 }
 
+func TestOllamaClient_GenerateStream_DeliversEachChunk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OllamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if !req.Stream {
+			t.Error("expected stream to be true")
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		_ = enc.Encode(OllamaResponse{Model: "test-model", Response: "Hello", Done: false})
+		_ = enc.Encode(OllamaResponse{Model: "test-model", Response: " there!", Done: true, EvalCount: 5})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	req := &GenerateRequest{Model: "test-model", Prompt: "Hello, world!"}
+
+	var chunks []StreamChunk
+	err := client.GenerateStream(context.Background(), req, func(chunk StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Text != "Hello" || chunks[0].Done {
+		t.Errorf("expected first chunk {Hello, done=false}, got %+v", chunks[0])
+	}
+	if chunks[1].Text != " there!" || !chunks[1].Done || chunks[1].TokensUsed != 5 {
+		t.Errorf("expected final chunk { there!, done=true, tokens=5}, got %+v", chunks[1])
+	}
+}
+
+func TestOllamaClient_GenerateStream_StopsOnCallbackError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		_ = enc.Encode(OllamaResponse{Model: "test-model", Response: "Hello", Done: false})
+		_ = enc.Encode(OllamaResponse{Model: "test-model", Response: " there!", Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second, RetryAttempts: 0})
+
+	callbackErr := errors.New("caller gave up")
+	calls := 0
+	err := client.GenerateStream(context.Background(), &GenerateRequest{Model: "test-model", Prompt: "hi"}, func(chunk StreamChunk) error {
+		calls++
+		return callbackErr
+	})
+
+	if !errors.Is(err, callbackErr) {
+		t.Errorf("expected callback error to propagate, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected onChunk to stop after the first chunk, got %d calls", calls)
+	}
+}
+
+func TestOllamaClient_Stream_DeliversEachChunk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		_ = enc.Encode(OllamaResponse{Model: "test-model", Response: "Hello", Done: false})
+		_ = enc.Encode(OllamaResponse{Model: "test-model", Response: " there!", Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	chunks, err := client.Stream(context.Background(), &GenerateRequest{Model: "test-model", Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []GenerateChunk
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %+v", len(got), got)
+	}
+	if got[0].Text != "Hello" || got[0].Done || got[0].Err != nil {
+		t.Errorf("expected first chunk {Hello, done=false}, got %+v", got[0])
+	}
+	if got[1].Text != " there!" || !got[1].Done || got[1].Err != nil {
+		t.Errorf("expected final chunk { there!, done=true}, got %+v", got[1])
+	}
+}
+
+func TestOllamaClient_Stream_DeliversErrorAsFinalChunk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte("not valid json\n"))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second, RetryAttempts: 0})
+
+	chunks, err := client.Stream(context.Background(), &GenerateRequest{Model: "test-model", Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []GenerateChunk
+	for chunk := range chunks {
+		got = append(got, chunk)
+	}
+
+	if len(got) != 1 || got[0].Err == nil || !got[0].Done {
+		t.Fatalf("expected a single error chunk with done=true, got %+v", got)
+	}
+}
+
+func TestOllamaClient_Generate_AccumulatesStreamChunks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		_ = enc.Encode(OllamaResponse{Model: "test-model", Response: "Hello", Done: false})
+		_ = enc.Encode(OllamaResponse{Model: "test-model", Response: " there!", Done: true, EvalCount: 5})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	resp, err := client.Generate(context.Background(), &GenerateRequest{Model: "test-model", Prompt: "Hello, world!"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Text != "Hello there!" {
+		t.Errorf("expected accumulated text 'Hello there!', got %q", resp.Text)
+	}
+	if resp.TokensUsed != 5 {
+		t.Errorf("expected 5 tokens used, got %d", resp.TokensUsed)
+	}
+}
+
+func TestOllamaClient_Generate_JSONModeSetsFormat(t *testing.T) {
+	var gotReq OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_ = json.NewEncoder(w).Encode(OllamaResponse{Model: "test-model", Response: `{"ok":true}`, Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	req := &GenerateRequest{Model: "test-model", Prompt: "give me json", ResponseSchema: "json"}
+	if _, err := client.Generate(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(gotReq.Format) != `"json"` {
+		t.Errorf("expected format 'json', got %s", string(gotReq.Format))
+	}
+}
+
+func TestOllamaClient_Generate_SchemaPassesThroughAsFormat(t *testing.T) {
+	const schema = `{"type":"object","properties":{"ok":{"type":"boolean"}}}`
+
+	var gotReq OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_ = json.NewEncoder(w).Encode(OllamaResponse{Model: "test-model", Response: `{"ok":true}`, Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	req := &GenerateRequest{Model: "test-model", Prompt: "give me json", ResponseSchema: schema}
+	if _, err := client.Generate(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(gotReq.Format) != schema {
+		t.Errorf("expected format to be the schema verbatim, got %s", string(gotReq.Format))
+	}
+}
+
+func TestOllamaClient_Chat_Success(t *testing.T) {
+	var gotReq OllamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("expected request to /api/chat, got %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if gotReq.Stream {
+			t.Error("expected stream to be false")
+		}
+
+		_ = json.NewEncoder(w).Encode(OllamaChatResponse{
+			Model:     "test-model",
+			Message:   OllamaChatMessage{Role: "assistant", Content: "hi there"},
+			Done:      true,
+			EvalCount: 7,
+		})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	req := &ChatRequest{
+		Model: "test-model",
+		Messages: []ChatMessage{
+			{Role: "system", Content: "You are a helpful assistant."},
+			{Role: "user", Content: "hello"},
+		},
+		KeepAlive: "5m",
+	}
+
+	resp, err := client.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(gotReq.Messages) != 2 || gotReq.Messages[0].Role != "system" || gotReq.Messages[1].Role != "user" {
+		t.Errorf("expected both messages forwarded in order, got %+v", gotReq.Messages)
+	}
+	if gotReq.KeepAlive != "5m" {
+		t.Errorf("expected keep_alive '5m', got %q", gotReq.KeepAlive)
+	}
+	if resp.Message.Role != "assistant" || resp.Message.Content != "hi there" {
+		t.Errorf("expected assistant reply 'hi there', got %+v", resp.Message)
+	}
+	if resp.TokensUsed != 7 {
+		t.Errorf("expected 7 tokens used, got %d", resp.TokensUsed)
+	}
+}
+
+func TestOllamaClient_Chat_NilRequest(t *testing.T) {
+	client := NewOllamaClient(&ProviderConfig{BaseURL: "http://localhost:11434"})
+
+	if _, err := client.Chat(context.Background(), nil); err == nil {
+		t.Fatal("expected an error for a nil request")
+	}
+}
+
+func TestOllamaClient_EnsureModel_SkipsPullWhenAlreadyPresent(t *testing.T) {
+	pulled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			_ = json.NewEncoder(w).Encode(OllamaModelsResponse{Models: []OllamaModel{{Name: "test-model"}}})
+		case "/api/pull":
+			pulled = true
+			w.WriteHeader(http.StatusOK)
+		case "/api/generate":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			_ = json.NewEncoder(w).Encode(OllamaResponse{Model: "test-model", Response: "Hi", Done: true})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	if err := client.EnsureModel(context.Background(), "test-model", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pulled {
+		t.Error("expected /api/pull not to be called for an already-present model")
+	}
+}
+
+func TestOllamaClient_EnsureModel_PullsMissingModelAndReportsProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			_ = json.NewEncoder(w).Encode(OllamaModelsResponse{})
+		case "/api/pull":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			enc := json.NewEncoder(w)
+			_ = enc.Encode(ollamaPullStatus{Status: "pulling manifest"})
+			_ = enc.Encode(ollamaPullStatus{Status: "downloading", Completed: 50, Total: 100})
+			_ = enc.Encode(ollamaPullStatus{Status: "success"})
+		case "/api/generate":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			_ = json.NewEncoder(w).Encode(OllamaResponse{Model: "test-model", Response: "Hi", Done: true})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	var progress []PullProgress
+	err := client.EnsureModel(context.Background(), "test-model", func(p PullProgress) {
+		progress = append(progress, p)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("expected 3 progress updates, got %d", len(progress))
+	}
+	if progress[1].Completed != 50 || progress[1].Total != 100 {
+		t.Errorf("expected download progress 50/100, got %+v", progress[1])
+	}
+}
+
+func TestOllamaClient_EnsureModel_PropagatesPullError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			_ = json.NewEncoder(w).Encode(OllamaModelsResponse{})
+		case "/api/pull":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			_ = json.NewEncoder(w).Encode(ollamaPullStatus{Error: "model not found"})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(&ProviderConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	if err := client.EnsureModel(context.Background(), "missing-model", nil); err == nil {
+		t.Fatal("expected an error when the pull reports a failure")
+	}
+}
+
 func TestOllamaClient_ListModels_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/tags" {
@@ -0,0 +1,332 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOpenAIBaseURL is used when no BaseURL override is configured.
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIClient implements the Provider interface for OpenAI's chat-completions
+// wire format. It also works against any OpenAI-compatible endpoint (LocalAI,
+// vLLM, LM Studio, llama.cpp's server, OpenRouter, Groq, ...) by pointing
+// BaseURL at the alternate host.
+type OpenAIClient struct {
+	config     *ProviderConfig
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+}
+
+// openAIChatRequest represents a request to the /chat/completions endpoint.
+type openAIChatRequest struct {
+	Model       string              `json:"model"`
+	Messages    []openAIChatMessage `json:"messages"`
+	MaxTokens   int                 `json:"max_tokens,omitempty"`
+	Temperature float64             `json:"temperature,omitempty"`
+}
+
+// openAIChatMessage represents a single message in a chat-completions request.
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIChatResponse represents a response from the /chat/completions endpoint.
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      openAIChatMessage `json:"message"`
+		FinishReason string            `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// openAIErrorResponse represents an error response from an OpenAI-compatible API.
+type openAIErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// NewOpenAIClient creates a new OpenAIClient. apiKey may be empty for
+// self-hosted endpoints that don't require authentication.
+func NewOpenAIClient(config *ProviderConfig, apiKey string) *OpenAIClient {
+	if config == nil {
+		config = DefaultProviderConfig()
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+
+	return &OpenAIClient{
+		config:  config,
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// Name returns the provider name.
+func (c *OpenAIClient) Name() string {
+	return "openai"
+}
+
+// Generate generates text using the chat-completions endpoint.
+func (c *OpenAIClient) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = c.config.DefaultMaxTokens
+	}
+
+	temperature := req.Temperature
+	if temperature <= 0 {
+		temperature = c.config.DefaultTemperature
+	}
+
+	chatReq := &openAIChatRequest{
+		Model: model,
+		Messages: []openAIChatMessage{
+			{Role: "user", Content: req.Prompt},
+		},
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	}
+
+	startTime := time.Now()
+
+	reqBody, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if c.config.Organization != "" {
+		httpReq.Header.Set("OpenAI-Organization", c.config.Organization)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseTime := time.Since(startTime)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp openAIErrorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+			return nil, fmt.Errorf("openai API error (status %d): %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("openai API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("openai API returned no choices")
+	}
+
+	return &GenerateResponse{
+		Text:         chatResp.Choices[0].Message.Content,
+		TokensUsed:   chatResp.Usage.TotalTokens,
+		Model:        chatResp.Model,
+		FinishReason: chatResp.Choices[0].FinishReason,
+		ResponseTime: responseTime,
+	}, nil
+}
+
+// GenerateStream satisfies the Provider interface without true incremental
+// output: the chat-completions call above runs to completion and the whole
+// response is delivered as a single done chunk.
+func (c *OpenAIClient) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	resp, err := c.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return onChunk(StreamChunk{Text: resp.Text, Done: true, TokensUsed: resp.TokensUsed})
+}
+
+// Chat generates a reply to a multi-turn conversation using the
+// chat-completions endpoint's native messages array, unlike Generate which
+// always sends a single user message.
+func (c *OpenAIClient) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	model := req.Model
+	if model == "" {
+		model = c.config.DefaultModel
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = c.config.DefaultMaxTokens
+	}
+
+	temperature := req.Temperature
+	if temperature <= 0 {
+		temperature = c.config.DefaultTemperature
+	}
+
+	messages := make([]openAIChatMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = openAIChatMessage{Role: m.Role, Content: m.Content}
+	}
+
+	chatReq := &openAIChatRequest{
+		Model:       model,
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	}
+
+	startTime := time.Now()
+
+	reqBody, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if c.config.Organization != "" {
+		httpReq.Header.Set("OpenAI-Organization", c.config.Organization)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseTime := time.Since(startTime)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp openAIErrorResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+			return nil, fmt.Errorf("openai API error (status %d): %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return nil, fmt.Errorf("openai API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("openai API returned no choices")
+	}
+
+	return &ChatResponse{
+		Message:      ChatMessage{Role: "assistant", Content: chatResp.Choices[0].Message.Content},
+		TokensUsed:   chatResp.Usage.TotalTokens,
+		Model:        chatResp.Model,
+		FinishReason: chatResp.Choices[0].FinishReason,
+		ResponseTime: responseTime,
+	}, nil
+}
+
+// ListModels returns available models from the /models endpoint.
+func (c *OpenAIClient) ListModels(ctx context.Context) ([]string, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models", http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("openai API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var modelsResp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	models := make([]string, len(modelsResp.Data))
+	for i, m := range modelsResp.Data {
+		models[i] = m.ID
+	}
+
+	return models, nil
+}
+
+// Health checks if the endpoint is available by listing models.
+func (c *OpenAIClient) Health(ctx context.Context) error {
+	if _, err := c.ListModels(ctx); err != nil {
+		return fmt.Errorf("openai endpoint not reachable: %w", err)
+	}
+	return nil
+}
+
+// Close cleans up resources (no-op for HTTP client).
+func (c *OpenAIClient) Close() error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewOpenAIClientDefaultBaseURL(t *testing.T) {
+	client := NewOpenAIClient(nil, "test-key")
+
+	if client.Name() != "openai" {
+		t.Errorf("expected name to be 'openai', got %s", client.Name())
+	}
+
+	if client.baseURL != defaultOpenAIBaseURL {
+		t.Errorf("expected base URL to be %s, got %s", defaultOpenAIBaseURL, client.baseURL)
+	}
+}
+
+func TestNewOpenAIClientCustomBaseURL(t *testing.T) {
+	config := &ProviderConfig{
+		BaseURL:      "http://localhost:8080/v1",
+		DefaultModel: "local-model",
+	}
+
+	client := NewOpenAIClient(config, "")
+
+	if client.baseURL != "http://localhost:8080/v1" {
+		t.Errorf("expected base URL to be 'http://localhost:8080/v1', got %s", client.baseURL)
+	}
+}
+
+func TestOpenAIClient_Generate_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("expected path /chat/completions, got %s", r.URL.Path)
+		}
+
+		var req openAIChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		resp := openAIChatResponse{Model: req.Model}
+		resp.Choices = []struct {
+			Message      openAIChatMessage `json:"message"`
+			FinishReason string            `json:"finish_reason"`
+		}{
+			{Message: openAIChatMessage{Role: "assistant", Content: "Hello!"}, FinishReason: "stop"},
+		}
+		resp.Usage.TotalTokens = 5
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(&ProviderConfig{BaseURL: server.URL}, "")
+
+	resp, err := client.Generate(context.Background(), &GenerateRequest{
+		Model:  "gpt-4o-mini",
+		Prompt: "Hi",
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if resp.Text != "Hello!" {
+		t.Errorf("expected text 'Hello!', got %s", resp.Text)
+	}
+	if resp.TokensUsed != 5 {
+		t.Errorf("expected 5 tokens used, got %d", resp.TokensUsed)
+	}
+}
+
+func TestOpenAIClient_Generate_NoAPIKeyForSelfHosted(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(openAIChatResponse{})
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(&ProviderConfig{BaseURL: server.URL}, "")
+	_, err := client.Generate(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err == nil {
+		t.Fatalf("expected error due to empty choices")
+	}
+
+	if gotAuth != "" {
+		t.Errorf("expected no Authorization header to be sent, got %q", gotAuth)
+	}
+}
@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -12,6 +13,14 @@ type GenerateRequest struct {
 	MaxTokens   int
 	Temperature float64
 	Context     []string
+
+	// ResponseSchema, when set, asks the provider for structured JSON
+	// output instead of free text. The literal value "json" requests
+	// generic JSON mode; any other value is treated as a JSON Schema
+	// document (raw JSON text) constraining the response shape. Providers
+	// without a native format parameter ignore it - GenerateStructured's
+	// parse-and-repair loop works regardless.
+	ResponseSchema string
 }
 
 // GenerateResponse represents a response from an LLM generation request.
@@ -24,11 +33,67 @@ type GenerateResponse struct {
 	TotalDuration time.Duration
 }
 
+// StreamChunk is one piece of a streamed generation, delivered to
+// GenerateStream's callback as the provider produces it. Done marks the
+// final chunk, at which point TokensUsed (if the provider reports it)
+// reflects the whole generation rather than just this chunk.
+type StreamChunk struct {
+	Text       string
+	Done       bool
+	TokensUsed int
+}
+
+// ChatMessage is a single turn in a multi-turn Chat conversation, with role
+// "system", "user", or "assistant".
+type ChatMessage struct {
+	Role    string
+	Content string
+}
+
+// ChatRequest represents a multi-turn chat generation request, as opposed to
+// GenerateRequest's single flat prompt. Messages typically open with one
+// system message carrying the standing instructions, followed by alternating
+// user/assistant turns.
+type ChatRequest struct {
+	Model       string
+	Messages    []ChatMessage
+	MaxTokens   int
+	Temperature float64
+
+	// KeepAlive controls how long a provider keeps the model resident after
+	// this request (e.g. Ollama's "5m" / "0" keep_alive option), so a
+	// scheduled summarizer can pin the model in VRAM between runs instead of
+	// paying a cold-load penalty each time. Providers without an equivalent
+	// concept ignore it.
+	KeepAlive string
+}
+
+// ChatResponse represents the result of a Chat call.
+type ChatResponse struct {
+	Message      ChatMessage
+	TokensUsed   int
+	Model        string
+	FinishReason string
+	ResponseTime time.Duration
+}
+
 // Provider defines the interface for different LLM services.
 type Provider interface {
 	// Generate generates text using the LLM.
 	Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error)
 
+	// GenerateStream generates text using the LLM, invoking onChunk as each
+	// piece of the response arrives instead of blocking until it's all in.
+	// Returning an error from onChunk aborts the generation. Providers
+	// without real incremental output deliver the whole response as a
+	// single done chunk.
+	GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error
+
+	// Chat generates a reply to a multi-turn conversation carrying a stable
+	// system instruction plus alternating user/assistant history, as opposed
+	// to Generate's single flat prompt.
+	Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error)
+
 	// ListModels returns available models for this provider.
 	ListModels(ctx context.Context) ([]string, error)
 
@@ -52,6 +117,36 @@ type ProviderConfig struct {
 	DefaultModel       string
 	DefaultMaxTokens   int
 	DefaultTemperature float64
+
+	// Organization, if set, is sent as the OpenAI-Organization header by
+	// OpenAIClient for accounts billing to a specific organization.
+	Organization string
+
+	// APIKey authenticates against a hosted provider (OpenAI, Anthropic,
+	// Gemini). Ollama ignores it - a local server has nothing to
+	// authenticate against.
+	APIKey string
+}
+
+// NewProvider builds the Provider for name using cfg, so callers needn't
+// hardcode a specific client type (generator.go's newProviderFromConfig, in
+// particular). name is a provider kind - "ollama", "openai" (or
+// "openai-compatible", for any OpenAI-shaped chat completions endpoint),
+// "anthropic", or "gemini" - matching the values accepted by config's
+// llm.providers[].kind.
+func NewProvider(name string, cfg *ProviderConfig) (Provider, error) {
+	switch name {
+	case "ollama":
+		return NewOllamaClient(cfg), nil
+	case "openai", "openai-compatible":
+		return NewOpenAIClient(cfg, cfg.APIKey), nil
+	case "anthropic":
+		return NewAnthropicClient(cfg, cfg.APIKey), nil
+	case "gemini":
+		return NewGeminiClient(cfg, cfg.APIKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider kind: %s", name)
+	}
 }
 
 // DefaultProviderConfig returns default configuration values.
@@ -0,0 +1,143 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetryConfig tunes a RetryingProvider's exponential backoff between
+// attempts, mirroring the InitialRetryDelay/MaxRetryDelay/BackoffMultiplier
+// fields config.ConnectionConfig uses for websocket reconnects.
+type RetryConfig struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+}
+
+// DefaultRetryConfig is the tuning newProviderFromConfig wraps every
+// non-Ollama LLM provider with (OllamaClient retries internally): three
+// attempts, starting at 500ms and doubling up to a
+// 10s cap.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 500 * time.Millisecond,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2.0,
+	}
+}
+
+// RetryingProvider wraps a Provider with RetryConfig's exponential backoff,
+// making up to MaxAttempts attempts in all at a failed
+// Generate/Chat/GenerateStream call before giving up. It composes with the per-provider CircuitBreaker
+// FallbackProvider already tracks rather than duplicating it: a transient
+// error is absorbed here without ever reaching the breaker, while a
+// provider that's genuinely down still exhausts its retries on every call
+// and trips the breaker at the normal rate.
+type RetryingProvider struct {
+	provider Provider
+	config   RetryConfig
+}
+
+// NewRetryingProvider wraps provider with config's retry policy.
+func NewRetryingProvider(provider Provider, config RetryConfig) *RetryingProvider {
+	return &RetryingProvider{provider: provider, config: config}
+}
+
+// Generate retries Provider.Generate per RetryConfig.
+func (r *RetryingProvider) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	var resp *GenerateResponse
+	err := r.retry(ctx, func() error {
+		var genErr error
+		resp, genErr = r.provider.Generate(ctx, req)
+		return genErr
+	})
+	return resp, err
+}
+
+// Chat retries Provider.Chat per RetryConfig.
+func (r *RetryingProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	var resp *ChatResponse
+	err := r.retry(ctx, func() error {
+		var chatErr error
+		resp, chatErr = r.provider.Chat(ctx, req)
+		return chatErr
+	})
+	return resp, err
+}
+
+// GenerateStream retries Provider.GenerateStream per RetryConfig. A
+// retried attempt restarts the stream from scratch - same as Ollama's own
+// internal streamWithRetry - so onChunk should expect to see an earlier
+// partial attempt's chunks again rather than one unbroken stream.
+func (r *RetryingProvider) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	return r.retry(ctx, func() error {
+		return r.provider.GenerateStream(ctx, req, onChunk)
+	})
+}
+
+// ListModels delegates to the wrapped provider without retrying.
+func (r *RetryingProvider) ListModels(ctx context.Context) ([]string, error) {
+	return r.provider.ListModels(ctx)
+}
+
+// Health delegates to the wrapped provider without retrying.
+func (r *RetryingProvider) Health(ctx context.Context) error {
+	return r.provider.Health(ctx)
+}
+
+// Name delegates to the wrapped provider.
+func (r *RetryingProvider) Name() string {
+	return r.provider.Name()
+}
+
+// Close delegates to the wrapped provider.
+func (r *RetryingProvider) Close() error {
+	return r.provider.Close()
+}
+
+// retry runs fn, retrying up to config.MaxAttempts-1 additional times with
+// exponential backoff between attempts. ctx.Canceled and
+// ctx.DeadlineExceeded are terminal: they're returned immediately rather
+// than retried, since waiting for the caller's own deadline/cancellation
+// can never help.
+func (r *RetryingProvider) retry(ctx context.Context, fn func() error) error {
+	maxAttempts := r.config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	delay := r.config.InitialDelay
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay = time.Duration(float64(delay) * r.config.Multiplier)
+			if r.config.MaxDelay > 0 && delay > r.config.MaxDelay {
+				delay = r.config.MaxDelay
+			}
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+
+		lastErr = err
+	}
+
+	return fmt.Errorf("failed after %d attempts: %w", maxAttempts, lastErr)
+}
+
+var _ Provider = (*RetryingProvider)(nil)
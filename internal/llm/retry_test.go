@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyProvider fails its first failuresBeforeSuccess calls, then
+// succeeds, so RetryingProvider's retry loop can be exercised against a
+// provider that recovers partway through.
+type flakyProvider struct {
+	failuresBeforeSuccess int
+	calls                 int
+	err                   error
+}
+
+func (p *flakyProvider) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	p.calls++
+	if p.calls <= p.failuresBeforeSuccess {
+		if p.err != nil {
+			return nil, p.err
+		}
+		return nil, errors.New("transient failure")
+	}
+	return &GenerateResponse{Text: "ok"}, nil
+}
+
+func (p *flakyProvider) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	resp, err := p.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return onChunk(StreamChunk{Text: resp.Text, Done: true})
+}
+
+func (p *flakyProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	p.calls++
+	if p.calls <= p.failuresBeforeSuccess {
+		if p.err != nil {
+			return nil, p.err
+		}
+		return nil, errors.New("transient failure")
+	}
+	return &ChatResponse{Message: ChatMessage{Role: "assistant", Content: "ok"}}, nil
+}
+
+func (p *flakyProvider) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+func (p *flakyProvider) Health(ctx context.Context) error                 { return nil }
+func (p *flakyProvider) Name() string                                     { return "flaky" }
+func (p *flakyProvider) Close() error                                     { return nil }
+
+func testRetryConfig() RetryConfig {
+	return RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Multiplier: 2}
+}
+
+func TestRetryingProvider_SucceedsAfterTransientFailures(t *testing.T) {
+	p := &flakyProvider{failuresBeforeSuccess: 2}
+	rp := NewRetryingProvider(p, testRetryConfig())
+
+	resp, err := rp.Generate(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("expected retry to eventually succeed, got error: %v", err)
+	}
+	if resp.Text != "ok" {
+		t.Errorf("unexpected response text: %q", resp.Text)
+	}
+	if p.calls != 3 {
+		t.Errorf("expected 3 calls (2 failures + 1 success), got %d", p.calls)
+	}
+}
+
+func TestRetryingProvider_GivesUpAfterMaxAttempts(t *testing.T) {
+	p := &flakyProvider{failuresBeforeSuccess: 10}
+	rp := NewRetryingProvider(p, testRetryConfig())
+
+	_, err := rp.Generate(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if err == nil {
+		t.Fatal("expected an error once every attempt fails")
+	}
+	if p.calls != 3 {
+		t.Errorf("expected exactly MaxAttempts=3 calls, got %d", p.calls)
+	}
+}
+
+func TestRetryingProvider_StopsImmediatelyOnContextCancellation(t *testing.T) {
+	p := &flakyProvider{failuresBeforeSuccess: 10, err: context.Canceled}
+	rp := NewRetryingProvider(p, testRetryConfig())
+
+	_, err := rp.Generate(context.Background(), &GenerateRequest{Prompt: "hi"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled to surface unchanged, got %v", err)
+	}
+	if p.calls != 1 {
+		t.Errorf("expected context.Canceled to stop retries after the first attempt, got %d calls", p.calls)
+	}
+}
+
+func TestRetryingProvider_ChatRetriesLikeGenerate(t *testing.T) {
+	p := &flakyProvider{failuresBeforeSuccess: 1}
+	rp := NewRetryingProvider(p, testRetryConfig())
+
+	resp, err := rp.Chat(context.Background(), &ChatRequest{Messages: []ChatMessage{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Message.Content != "ok" {
+		t.Errorf("unexpected chat response: %q", resp.Message.Content)
+	}
+}
@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GenerateStructured calls provider.Generate asking for JSON output and
+// unmarshals the result into out. req.ResponseSchema defaults to "json" if
+// left unset. If the first response doesn't parse, it retries once with a
+// repair prompt that includes the parse error and the malformed output,
+// asking the model to emit valid JSON for the same request; a second parse
+// failure is returned as an error rather than retried again indefinitely.
+func GenerateStructured(ctx context.Context, provider Provider, req *GenerateRequest, out any) error {
+	if req.ResponseSchema == "" {
+		req.ResponseSchema = "json"
+	}
+
+	resp, err := provider.Generate(ctx, req)
+	if err != nil {
+		return fmt.Errorf("generation failed: %w", err)
+	}
+
+	parseErr := json.Unmarshal([]byte(resp.Text), out)
+	if parseErr == nil {
+		return nil
+	}
+
+	repairReq := &GenerateRequest{
+		Model:          req.Model,
+		Prompt:         buildRepairPrompt(req.Prompt, resp.Text, parseErr),
+		MaxTokens:      req.MaxTokens,
+		Temperature:    req.Temperature,
+		ResponseSchema: req.ResponseSchema,
+	}
+
+	repairResp, repairErr := provider.Generate(ctx, repairReq)
+	if repairErr != nil {
+		return fmt.Errorf("response was not valid JSON (%v) and the repair attempt failed: %w", parseErr, repairErr)
+	}
+
+	if err := json.Unmarshal([]byte(repairResp.Text), out); err != nil {
+		return fmt.Errorf("response was still not valid JSON after one repair attempt: %w", err)
+	}
+
+	return nil
+}
+
+// buildRepairPrompt asks the model to redo a generation that produced
+// unparseable JSON, showing it the exact error and its own bad output so it
+// can correct the specific mistake rather than guessing at the shape again.
+func buildRepairPrompt(originalPrompt, badOutput string, parseErr error) string {
+	return fmt.Sprintf(
+		"%s\n\nYour previous response could not be parsed as JSON (%v):\n\n%s\n\nRespond again with ONLY valid JSON matching the requested shape, and nothing else.",
+		originalPrompt, parseErr, badOutput,
+	)
+}
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// structuredTestProvider is a minimal Provider stub for exercising
+// GenerateStructured without spinning up an HTTP server; only Generate is
+// exercised, so every other method is an unused no-op.
+type structuredTestProvider struct {
+	responses []string
+	calls     int
+}
+
+func (p *structuredTestProvider) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if p.calls >= len(p.responses) {
+		return nil, errors.New("no more canned responses")
+	}
+	resp := p.responses[p.calls]
+	p.calls++
+	return &GenerateResponse{Text: resp, Model: req.Model}, nil
+}
+
+func (p *structuredTestProvider) GenerateStream(ctx context.Context, req *GenerateRequest, onChunk func(StreamChunk) error) error {
+	resp, err := p.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return onChunk(StreamChunk{Text: resp.Text, Done: true})
+}
+
+func (p *structuredTestProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *structuredTestProvider) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+func (p *structuredTestProvider) Health(ctx context.Context) error                 { return nil }
+func (p *structuredTestProvider) Name() string                                     { return "structured-test" }
+func (p *structuredTestProvider) Close() error                                     { return nil }
+
+type structuredResult struct {
+	Topics       []string `json:"topics"`
+	Participants []string `json:"participants"`
+}
+
+func TestGenerateStructured_ParsesWellFormedJSON(t *testing.T) {
+	provider := &structuredTestProvider{responses: []string{`{"topics":["lunch"],"participants":["alice"]}`}}
+
+	var out structuredResult
+	req := &GenerateRequest{Model: "test-model", Prompt: "summarize"}
+	if err := GenerateStructured(context.Background(), provider, req, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(out.Topics) != 1 || out.Topics[0] != "lunch" {
+		t.Errorf("expected topics ['lunch'], got %v", out.Topics)
+	}
+	if req.ResponseSchema != "json" {
+		t.Errorf("expected ResponseSchema to default to 'json', got %q", req.ResponseSchema)
+	}
+	if provider.calls != 1 {
+		t.Errorf("expected a single call with no repair attempt, got %d", provider.calls)
+	}
+}
+
+func TestGenerateStructured_RepairsOnFirstParseFailure(t *testing.T) {
+	provider := &structuredTestProvider{responses: []string{
+		"sure, here's the summary: topics are lunch and weather",
+		`{"topics":["lunch","weather"],"participants":["bob"]}`,
+	}}
+
+	var out structuredResult
+	req := &GenerateRequest{Model: "test-model", Prompt: "summarize"}
+	if err := GenerateStructured(context.Background(), provider, req, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if provider.calls != 2 {
+		t.Fatalf("expected exactly one repair attempt (2 calls total), got %d", provider.calls)
+	}
+	if len(out.Topics) != 2 {
+		t.Errorf("expected the repaired response to parse, got %+v", out)
+	}
+}
+
+func TestGenerateStructured_FailsAfterOneFailedRepairAttempt(t *testing.T) {
+	provider := &structuredTestProvider{responses: []string{
+		"not json",
+		"still not json",
+	}}
+
+	var out structuredResult
+	req := &GenerateRequest{Model: "test-model", Prompt: "summarize"}
+	err := GenerateStructured(context.Background(), provider, req, &out)
+	if err == nil {
+		t.Fatal("expected an error after the repair attempt also fails to parse")
+	}
+	if provider.calls != 2 {
+		t.Errorf("expected no more than one repair attempt (2 calls total), got %d", provider.calls)
+	}
+}
@@ -0,0 +1,132 @@
+// Package logging provides the structured Logger interface used throughout
+// the client, wrapping go.uber.org/zap so call sites never depend on zap
+// directly and tests can inject a no-op implementation.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"irccloud-watcher/internal/config"
+
+	"github.com/natefinch/lumberjack"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger is the printf-style structured logging interface. Implementations
+// are expected to attach contextual fields (channel, eid, retry_count,
+// state, ...) via formatted key=value pairs in the message rather than a
+// separate fields API, keeping call sites simple.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// New builds the default Logger from cfg, wrapping zap. A "console" encoding
+// produces the human-readable output developers are used to; "json"
+// produces one-object-per-line output suitable for shipping to Loki/ELK.
+// Output goes to stderr unless cfg.OutputPath is set, in which case it's
+// written to a file that rotates via lumberjack.
+func New(cfg config.LoggingConfig) (Logger, error) {
+	level := zapcore.InfoLevel
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, fmt.Errorf("invalid logging level %q: %w", cfg.Level, err)
+		}
+	}
+
+	encoding := cfg.Encoding
+	if encoding == "" {
+		encoding = "console"
+	}
+
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	var encoder zapcore.Encoder
+	if encoding == "console" {
+		encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderCfg)
+	}
+
+	core := zapcore.NewCore(encoder, logSink(cfg), level)
+	built := zap.New(core)
+
+	return &zapLogger{sugar: built.Sugar()}, nil
+}
+
+// logSink returns the zapcore.WriteSyncer log output is written to: stderr
+// by default, or a rotating file via lumberjack when cfg.OutputPath is set.
+func logSink(cfg config.LoggingConfig) zapcore.WriteSyncer {
+	if cfg.OutputPath == "" {
+		return zapcore.Lock(os.Stderr)
+	}
+
+	maxSizeMB := cfg.MaxSizeMB
+	if maxSizeMB == 0 {
+		maxSizeMB = 100
+	}
+	maxBackups := cfg.MaxBackups
+	if maxBackups == 0 {
+		maxBackups = 3
+	}
+	maxAgeDays := cfg.MaxAgeDays
+	if maxAgeDays == 0 {
+		maxAgeDays = 28
+	}
+
+	return zapcore.AddSync(&lumberjack.Logger{
+		Filename:   cfg.OutputPath,
+		MaxSize:    maxSizeMB,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAgeDays,
+		Compress:   cfg.Compress,
+	})
+}
+
+// sensitivePattern catches key=value fields whose key looks like a
+// credential (session tokens, passwords, auth headers, ...) wherever they
+// show up in a log message, not just in the debugLogRequest/Response
+// helpers that scrub headers and bodies explicitly. Every zapLogger method
+// runs its formatted message through redactMessage before it reaches zap,
+// so this is the one chokepoint a careless %+v or new call site can't leak
+// through.
+var sensitivePattern = regexp.MustCompile(`(?i)(session|token|password|authorization|formtoken)=[^\s;]+`)
+
+func redactMessage(msg string) string {
+	return sensitivePattern.ReplaceAllString(msg, "$1=***REDACTED***")
+}
+
+type zapLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+func (z *zapLogger) Debugf(format string, args ...any) {
+	z.sugar.Debug(redactMessage(fmt.Sprintf(format, args...)))
+}
+func (z *zapLogger) Infof(format string, args ...any) {
+	z.sugar.Info(redactMessage(fmt.Sprintf(format, args...)))
+}
+func (z *zapLogger) Warnf(format string, args ...any) {
+	z.sugar.Warn(redactMessage(fmt.Sprintf(format, args...)))
+}
+func (z *zapLogger) Errorf(format string, args ...any) {
+	z.sugar.Error(redactMessage(fmt.Sprintf(format, args...)))
+}
+
+// Nop returns a Logger that discards everything it's given, for tests that
+// don't care about log output.
+func Nop() Logger { return nopLogger{} }
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...any) {}
+func (nopLogger) Infof(string, ...any)  {}
+func (nopLogger) Warnf(string, ...any)  {}
+func (nopLogger) Errorf(string, ...any) {}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"irccloud-watcher/internal/config"
+)
+
+func TestNewDefaultsToConsoleInfo(t *testing.T) {
+	logger, err := New(config.LoggingConfig{})
+	if err != nil {
+		t.Fatalf("New with empty config failed: %v", err)
+	}
+
+	// Should not panic at any level.
+	logger.Debugf("debug %s", "msg")
+	logger.Infof("info %s", "msg")
+	logger.Warnf("warn %s", "msg")
+	logger.Errorf("error %s", "msg")
+}
+
+func TestNewRejectsInvalidLevel(t *testing.T) {
+	if _, err := New(config.LoggingConfig{Level: "not-a-level"}); err == nil {
+		t.Fatal("expected an error for an invalid logging level")
+	}
+}
+
+func TestNewWritesToRotatingFileWhenOutputPathSet(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "watcher.log")
+
+	logger, err := New(config.LoggingConfig{OutputPath: logPath})
+	if err != nil {
+		t.Fatalf("New with OutputPath set failed: %v", err)
+	}
+
+	logger.Infof("hello %s", "world")
+
+	if _, err := os.Stat(logPath); err != nil {
+		t.Errorf("expected log file at %s after logging: %v", logPath, err)
+	}
+}
+
+func TestRedactMessageMasksCredentialFields(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"session", "Cookie: session=abc123; other=1", "Cookie: session=***REDACTED***; other=1"},
+		{"token", "authenticated with token=sk-verysecret", "authenticated with token=***REDACTED***"},
+		{"no credential", "channel=#general from=alice eid=42", "channel=#general from=alice eid=42"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := redactMessage(tc.in); got != tc.want {
+				t.Errorf("redactMessage(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNopDoesNotPanic(t *testing.T) {
+	logger := Nop()
+	logger.Debugf("debug %s", "msg")
+	logger.Infof("info %s", "msg")
+	logger.Warnf("warn %s", "msg")
+	logger.Errorf("error %s", "msg")
+}
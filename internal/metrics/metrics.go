@@ -0,0 +1,57 @@
+// Package metrics holds the Prometheus collectors shared by packages that
+// have no metrics file of their own (storage, summary, llm), so they report
+// through the same /metrics endpoint internal/api/metrics.go already wires
+// up for the IRCCloudClient's own collectors.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// MessagesInsertedTotal counts messages successfully persisted to the
+	// message store, per channel.
+	MessagesInsertedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "irccloud_watcher_messages_inserted_total",
+		Help: "Messages successfully persisted to the message store, per channel.",
+	}, []string{"channel"})
+
+	// MessagesFilteredTotal counts messages the summary generator drops as
+	// noise before grouping, by reason.
+	MessagesFilteredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "irccloud_watcher_messages_filtered_total",
+		Help: "Messages dropped by the summary generator's noise filter, by reason.",
+	}, []string{"reason"})
+
+	// SummaryGenerationDuration times a full LLM summary generation: the
+	// map stage across every message group plus the reduce stage.
+	SummaryGenerationDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "irccloud_watcher_summary_generation_duration_seconds",
+		Help:    "Time spent generating an LLM summary, map and reduce stages combined.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// LLMTokensUsedTotal counts tokens consumed by successful LLM
+	// generations, per provider and model.
+	LLMTokensUsedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "irccloud_watcher_llm_tokens_used_total",
+		Help: "Tokens consumed by successful LLM generations, per provider and model.",
+	}, []string{"provider", "model"})
+
+	// LLMErrorsTotal counts failed LLM generation attempts, per provider.
+	LLMErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "irccloud_watcher_llm_errors_total",
+		Help: "Failed LLM generation attempts (Generate, Chat or GenerateStream), per provider.",
+	}, []string{"provider"})
+
+	// LLMGenerateDuration times a single Generate/Chat call against one
+	// provider, per provider and model, distinct from
+	// SummaryGenerationDuration which times the whole map-reduce pipeline
+	// across every provider a FallbackProvider chain tries.
+	LLMGenerateDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "irccloud_watcher_llm_generate_duration_seconds",
+		Help:    "Time spent in a single provider's Generate/Chat call, per provider and model.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider", "model"})
+)
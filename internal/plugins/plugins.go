@@ -0,0 +1,150 @@
+// Package plugins loads operator-supplied Lua scripts and invokes them at
+// fixed points in the message and summary pipelines, so redaction, filtering
+// and enrichment can be customized without recompiling the watcher.
+package plugins
+
+import (
+	"fmt"
+	"log"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"irccloud-watcher/internal/config"
+)
+
+// Hook names recognized by the plugin manager.
+const (
+	HookOnMessage   = "on_message"
+	HookPreSummary  = "pre_summary"
+	HookPostSummary = "post_summary"
+	HookOnCommand   = "on_command"
+)
+
+// Manager loads and runs Lua plugin scripts.
+type Manager struct {
+	scripts []*lua.LState
+	hooks   map[string]bool
+}
+
+// NewManager compiles and loads every configured plugin script, failing
+// fast with a file name and line number if any script has a syntax error.
+func NewManager(cfg config.PluginsConfig) (*Manager, error) {
+	m := &Manager{
+		hooks: make(map[string]bool, len(cfg.Hooks)),
+	}
+	for _, hook := range cfg.Hooks {
+		m.hooks[hook] = true
+	}
+
+	for _, path := range cfg.Scripts {
+		L := lua.NewState()
+		registerAPI(L)
+
+		if err := L.DoFile(path); err != nil {
+			L.Close()
+			m.Close()
+			return nil, fmt.Errorf("could not load plugin %q: %w", path, err)
+		}
+
+		m.scripts = append(m.scripts, L)
+	}
+
+	return m, nil
+}
+
+// Close releases every loaded Lua interpreter.
+func (m *Manager) Close() {
+	for _, L := range m.scripts {
+		L.Close()
+	}
+	m.scripts = nil
+}
+
+// OnMessage runs the on_message hook for every loaded plugin, giving each a
+// chance to mutate or drop an incoming IRC message. Returning false from the
+// Lua handler drops the message.
+func (m *Manager) OnMessage(channel, sender, message string) (rewritten string, keep bool, err error) {
+	rewritten = message
+	if !m.hooks[HookOnMessage] {
+		return rewritten, true, nil
+	}
+
+	for _, L := range m.scripts {
+		fn := L.GetGlobal(HookOnMessage)
+		if fn.Type() != lua.LTFunction {
+			continue
+		}
+
+		if callErr := L.CallByParam(lua.P{Fn: fn, NRet: 2, Protect: true},
+			lua.LString(channel), lua.LString(sender), lua.LString(rewritten)); callErr != nil {
+			return rewritten, true, fmt.Errorf("on_message hook failed: %w", callErr)
+		}
+
+		keepRet := L.Get(-1)
+		messageRet := L.Get(-2)
+		L.Pop(2)
+
+		if str, ok := messageRet.(lua.LString); ok {
+			rewritten = string(str)
+		}
+		if b, ok := keepRet.(lua.LBool); ok && !bool(b) {
+			return rewritten, false, nil
+		}
+	}
+
+	return rewritten, true, nil
+}
+
+// PreSummary runs the pre_summary hook, letting plugins rewrite the prompt
+// sent to the LLM before generation.
+func (m *Manager) PreSummary(prompt string) (string, error) {
+	return m.runTextHook(HookPreSummary, prompt)
+}
+
+// PostSummary runs the post_summary hook, letting plugins transform the
+// generated summary before it is written to SummaryOutputPath.
+func (m *Manager) PostSummary(summary string) (string, error) {
+	return m.runTextHook(HookPostSummary, summary)
+}
+
+// runTextHook threads text through every loaded plugin's handler for hook,
+// in load order, using each plugin's output as the next plugin's input.
+func (m *Manager) runTextHook(hook, text string) (string, error) {
+	if !m.hooks[hook] {
+		return text, nil
+	}
+
+	for _, L := range m.scripts {
+		fn := L.GetGlobal(hook)
+		if fn.Type() != lua.LTFunction {
+			continue
+		}
+
+		if err := L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, lua.LString(text)); err != nil {
+			return text, fmt.Errorf("%s hook failed: %w", hook, err)
+		}
+
+		ret := L.Get(-1)
+		L.Pop(1)
+		if str, ok := ret.(lua.LString); ok {
+			text = string(str)
+		}
+	}
+
+	return text, nil
+}
+
+// registerAPI exposes the small API plugins can call into: logging and
+// (eventually) DB queries / LLM requests, via the "watcher" Lua table.
+func registerAPI(L *lua.LState) {
+	watcherTable := L.NewTable()
+	L.SetField(watcherTable, "log", L.NewFunction(luaLog))
+	L.SetGlobal("watcher", watcherTable)
+}
+
+// luaLog implements watcher.log(message) for plugin scripts.
+func luaLog(L *lua.LState) int {
+	msg := L.ToString(1)
+	log.Printf("🔌 [plugin] %s", msg)
+	return 0
+}
@@ -0,0 +1,106 @@
+package plugins
+
+import (
+	"os"
+	"testing"
+
+	"irccloud-watcher/internal/config"
+)
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	tmpFile, err := os.CreateTemp("", "plugin-*.lua")
+	if err != nil {
+		t.Fatalf("failed to create temp script: %v", err)
+	}
+	if _, err := tmpFile.WriteString(body); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	tmpFile.Close()
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+	return tmpFile.Name()
+}
+
+func TestNewManagerRejectsMissingScript(t *testing.T) {
+	_, err := NewManager(config.PluginsConfig{Scripts: []string{"/does/not/exist.lua"}})
+	if err == nil {
+		t.Fatal("expected an error for a missing script")
+	}
+}
+
+func TestOnMessageFiltersAndRewrites(t *testing.T) {
+	path := writeScript(t, `
+function on_message(channel, sender, message)
+  if sender == "spambot" then
+    return message, false
+  end
+  return string.upper(message), true
+end
+`)
+
+	m, err := NewManager(config.PluginsConfig{Scripts: []string{path}, Hooks: []string{HookOnMessage}})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer m.Close()
+
+	rewritten, keep, err := m.OnMessage("#test", "alice", "hello")
+	if err != nil {
+		t.Fatalf("OnMessage failed: %v", err)
+	}
+	if !keep || rewritten != "HELLO" {
+		t.Errorf("expected message to be kept and upper-cased, got keep=%v rewritten=%q", keep, rewritten)
+	}
+
+	_, keep, err = m.OnMessage("#test", "spambot", "buy now")
+	if err != nil {
+		t.Fatalf("OnMessage failed: %v", err)
+	}
+	if keep {
+		t.Error("expected message from spambot to be dropped")
+	}
+}
+
+func TestPostSummaryHookDisabledByDefault(t *testing.T) {
+	path := writeScript(t, `
+function post_summary(summary)
+  return summary .. " [edited]"
+end
+`)
+
+	m, err := NewManager(config.PluginsConfig{Scripts: []string{path}})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer m.Close()
+
+	result, err := m.PostSummary("original")
+	if err != nil {
+		t.Fatalf("PostSummary failed: %v", err)
+	}
+	if result != "original" {
+		t.Errorf("expected hook to be a no-op when not enabled, got %q", result)
+	}
+}
+
+func TestPostSummaryHookEnabled(t *testing.T) {
+	path := writeScript(t, `
+function post_summary(summary)
+  return summary .. " [edited]"
+end
+`)
+
+	m, err := NewManager(config.PluginsConfig{Scripts: []string{path}, Hooks: []string{HookPostSummary}})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer m.Close()
+
+	result, err := m.PostSummary("original")
+	if err != nil {
+		t.Fatalf("PostSummary failed: %v", err)
+	}
+	if result != "original [edited]" {
+		t.Errorf("expected 'original [edited]', got %q", result)
+	}
+}
@@ -0,0 +1,411 @@
+package storage
+
+import (
+	"bufio"
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fsNetwork is the directory segment under an FSStore's root that holds
+// every channel's logs. IRCCloud watches exactly one account's worth of
+// networks, but the on-disk layout still nests under a network name, the
+// same as a ZNC log tree, so logs written here can be read by tools built
+// for ZNC's layout.
+const fsNetwork = "irccloud"
+
+// unsafePathComponent matches anything in a channel/network name that
+// isn't safe to use as a single path segment, so a malicious or malformed
+// channel name (e.g. containing "../") can't escape the log root.
+var unsafePathComponent = regexp.MustCompile(`[^A-Za-z0-9_.#-]`)
+
+// sanitizePathComponent replaces characters that aren't safe in a single
+// path segment with "_" and collapses ".." so a channel name can never
+// traverse outside root.
+func sanitizePathComponent(s string) string {
+	s = strings.ReplaceAll(s, "..", "_")
+	s = unsafePathComponent.ReplaceAllString(s, "_")
+	if s == "" {
+		s = "_"
+	}
+	return s
+}
+
+// FSStore is a MessageStore that writes one plain-text log file per
+// network/channel/day, in the layout ZNC's log module uses:
+// "<root>/<network>/<channel>/YYYY-MM-DD.log". Each line holds one
+// message, and each message's EID encodes its date and the byte offset of
+// its line (see encodeMsgID); reads parse a day's file from its start.
+//
+// Use this driver when you want grep-able logs on disk instead of a
+// SQLite database; storage.driver: fs in the config selects it.
+type FSStore struct {
+	root string
+
+	mu      sync.Mutex
+	handles *fileHandleLRU
+}
+
+// NewFSStore creates (if needed) root and returns an FSStore rooted there.
+func NewFSStore(root string) (*FSStore, error) {
+	if root == "" {
+		return nil, fmt.Errorf("fs store requires a non-empty root directory")
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create fs store root %s: %w", root, err)
+	}
+	return &FSStore{
+		root:    root,
+		handles: newFileHandleLRU(20),
+	}, nil
+}
+
+// logPath returns the path of channel's log file for date (YYYY-MM-DD).
+func (s *FSStore) logPath(channel, date string) string {
+	return filepath.Join(s.root, fsNetwork, sanitizePathComponent(channel), date+".log")
+}
+
+// encodeMsgID packs date (YYYY-MM-DD) and a byte offset within that date's
+// log file into a single int64, the same role EID plays for the SQLite
+// driver: a compact, sortable handle a caller can use to seek straight
+// back to a specific message.
+func encodeMsgID(date string, offset int64) int64 {
+	dateNum, _ := strconv.ParseInt(strings.ReplaceAll(date, "-", ""), 10, 64)
+	return dateNum*1_000_000_000 + offset
+}
+
+// decodeMsgID reverses encodeMsgID.
+func decodeMsgID(id int64) (date string, offset int64) {
+	dateNum := id / 1_000_000_000
+	offset = id % 1_000_000_000
+	return fmt.Sprintf("%04d-%02d-%02d", dateNum/10000, (dateNum/100)%100, dateNum%100), offset
+}
+
+// InsertMessage appends m to its channel's log file for m.Date, rewriting
+// m.EID to the encodeMsgID of the line it was just written at - the fs
+// driver's own message identity, independent of whatever EID the message
+// arrived with.
+func (s *FSStore) InsertMessage(m *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.logPath(m.Channel, m.Date)
+	f, err := s.handles.open(path)
+	if err != nil {
+		return fmt.Errorf("could not open log file %s: %w", path, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("could not stat log file %s: %w", path, err)
+	}
+	offset := info.Size()
+
+	line := formatLogLine(m)
+	if _, err := f.WriteString(line); err != nil {
+		return fmt.Errorf("could not write log file %s: %w", path, err)
+	}
+
+	m.EID = encodeMsgID(m.Date, offset)
+	return nil
+}
+
+// formatLogLine renders m as one ZNC-style log line: "[15:04:05] <sender>
+// message\n". Any newline in the message is flattened to a space so each
+// message stays exactly one line, preserving the seek-by-offset property.
+func formatLogLine(m *Message) string {
+	flat := strings.ReplaceAll(strings.ReplaceAll(m.Message, "\r\n", " "), "\n", " ")
+	return fmt.Sprintf("[%s] <%s> %s\n", m.Timestamp.Format("15:04:05"), m.Sender, flat)
+}
+
+// logLinePattern parses a line written by formatLogLine back into its
+// timestamp, sender, and message.
+var logLinePattern = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2})\] <([^>]*)> (.*)$`)
+
+// parseLogLine reconstructs a Message from one log line plus the channel,
+// date, and byte offset it was read at. It returns ok=false for a line
+// that doesn't match the expected format (e.g. a hand-edited log).
+func parseLogLine(channel, date string, offset int64, line string) (Message, bool) {
+	match := logLinePattern.FindStringSubmatch(line)
+	if match == nil {
+		return Message{}, false
+	}
+
+	clock, err := time.Parse("15:04:05", match[1])
+	if err != nil {
+		return Message{}, false
+	}
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return Message{}, false
+	}
+	timestamp := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, time.UTC)
+
+	return Message{
+		Channel:   channel,
+		Timestamp: timestamp,
+		Sender:    match[2],
+		Message:   match[3],
+		Date:      date,
+		EID:       encodeMsgID(date, offset),
+	}, true
+}
+
+// readDayFile parses every line of channel's log file for date, or returns
+// (nil, nil) if that file doesn't exist.
+func (s *FSStore) readDayFile(channel, date string) ([]Message, error) {
+	path := s.logPath(channel, date)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not open log file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var messages []Message
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var offset int64
+	for scanner.Scan() {
+		line := scanner.Text()
+		if msg, ok := parseLogLine(channel, date, offset, line); ok {
+			messages = append(messages, msg)
+		}
+		offset += int64(len(line)) + 1 // +1 for the newline Scanner strips
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read log file %s: %w", path, err)
+	}
+	return messages, nil
+}
+
+// channels lists the channel directories under the fsNetwork root, for
+// queries (GetMessagesByDate, GetMessagesInTimeRange, SearchMessages) that
+// aren't scoped to a single channel and so have to fan out across all of
+// them.
+func (s *FSStore) channels() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, fsNetwork))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var channels []string
+	for _, e := range entries {
+		if e.IsDir() {
+			channels = append(channels, e.Name())
+		}
+	}
+	return channels, nil
+}
+
+// GetMessagesByDate returns every message logged on date, across all
+// channels, oldest first.
+func (s *FSStore) GetMessagesByDate(date string) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channels, err := s.channels()
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Message
+	for _, channel := range channels {
+		messages, err := s.readDayFile(channel, date)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, messages...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+	return all, nil
+}
+
+// GetMessagesInTimeRange returns every message between start and end
+// (inclusive), oldest first, fanning out across every day and channel the
+// range touches.
+func (s *FSStore) GetMessagesInTimeRange(start, end time.Time) ([]Message, error) {
+	var all []Message
+	firstDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for day := firstDay; !day.After(end); day = day.AddDate(0, 0, 1) {
+		messages, err := s.GetMessagesByDate(day.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range messages {
+			if !m.Timestamp.Before(start) && !m.Timestamp.After(end) {
+				all = append(all, m)
+			}
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+	return all, nil
+}
+
+// DeleteMessagesByDate removes date's log file for every channel.
+func (s *FSStore) DeleteMessagesByDate(date string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channels, err := s.channels()
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		path := s.logPath(channel, date)
+		s.handles.remove(path)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not delete log file %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// SearchMessages scans log files matching filter and returns up to
+// filter.Limit results (default 100), newest first. Unlike the SQLite
+// driver's FTS5 index, Keyword here is a plain substring match - fine for
+// grep-sized logs, not for hundreds of thousands of messages.
+func (s *FSStore) SearchMessages(filter SearchFilter) ([]Message, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	start, end := filter.Since, filter.Until
+	if start.IsZero() {
+		start = time.Unix(0, 0)
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	messages, err := s.GetMessagesInTimeRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []Message
+	for _, m := range messages {
+		if filter.Channel != "" && m.Channel != filter.Channel {
+			continue
+		}
+		if filter.Sender != "" && m.Sender != filter.Sender {
+			continue
+		}
+		if filter.Keyword != "" && !strings.Contains(strings.ToLower(m.Message), strings.ToLower(filter.Keyword)) {
+			continue
+		}
+		if filter.BeforeEID > 0 && m.EID >= filter.BeforeEID {
+			continue
+		}
+		if filter.AfterEID > 0 && m.EID <= filter.AfterEID {
+			continue
+		}
+		matched = append(matched, m)
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].EID > matched[j].EID })
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+// Close flushes and closes every open log file handle.
+func (s *FSStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handles.closeAll()
+}
+
+// fileHandleLRU is a fixed-capacity LRU of open *os.File handles, so
+// FSStore doesn't run into "too many open files" when it's logging a large
+// number of channels: once maxSize files are open, appending to a new one
+// closes the least-recently-used handle first. Not safe for concurrent
+// use; callers hold FSStore.mu.
+type fileHandleLRU struct {
+	maxSize int
+	ll      *list.List
+	items   map[string]*list.Element
+}
+
+type fileHandleEntry struct {
+	path string
+	file *os.File
+}
+
+// newFileHandleLRU creates a fileHandleLRU holding at most maxSize open
+// handles.
+func newFileHandleLRU(maxSize int) *fileHandleLRU {
+	return &fileHandleLRU{
+		maxSize: maxSize,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// open returns an append-mode handle for path, reusing one already open
+// and promoting it to most-recently-used, or opening (and creating parent
+// directories for) a new one and evicting the least-recently-used handle
+// if that would put the cache over maxSize.
+func (l *fileHandleLRU) open(path string) (*os.File, error) {
+	if el, ok := l.items[path]; ok {
+		l.ll.MoveToFront(el)
+		return el.Value.(*fileHandleEntry).file, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	l.items[path] = l.ll.PushFront(&fileHandleEntry{path: path, file: f})
+	for l.ll.Len() > l.maxSize {
+		oldest := l.ll.Back()
+		entry := oldest.Value.(*fileHandleEntry)
+		entry.file.Close()
+		delete(l.items, entry.path)
+		l.ll.Remove(oldest)
+	}
+	return f, nil
+}
+
+// remove closes and evicts path's handle, if one is open. Used before
+// deleting a log file out from under it.
+func (l *fileHandleLRU) remove(path string) {
+	el, ok := l.items[path]
+	if !ok {
+		return
+	}
+	el.Value.(*fileHandleEntry).file.Close()
+	delete(l.items, path)
+	l.ll.Remove(el)
+}
+
+// closeAll closes every handle currently open.
+func (l *fileHandleLRU) closeAll() error {
+	var firstErr error
+	for el := l.ll.Front(); el != nil; el = el.Next() {
+		if err := el.Value.(*fileHandleEntry).file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	l.ll.Init()
+	l.items = make(map[string]*list.Element)
+	return firstErr
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process, unpersisted MessageStore: everything it
+// holds is lost when the process exits. It exists for tests and for
+// storage.driver: memory, where an operator wants the watcher running
+// without writing anything to disk.
+type MemoryStore struct {
+	mu       sync.Mutex
+	messages []Message
+	nextEID  int64
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// InsertMessage appends m, assigning it the next sequential EID if it
+// doesn't already have one.
+func (s *MemoryStore) InsertMessage(m *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m.EID == 0 {
+		s.nextEID++
+		m.EID = s.nextEID
+	} else if m.EID > s.nextEID {
+		s.nextEID = m.EID
+	}
+	s.messages = append(s.messages, *m)
+	return nil
+}
+
+// GetMessagesByDate returns every stored message for date, oldest first.
+func (s *MemoryStore) GetMessagesByDate(date string) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []Message
+	for _, m := range s.messages {
+		if m.Date == date {
+			matched = append(matched, m)
+		}
+	}
+	return matched, nil
+}
+
+// GetMessagesInTimeRange returns every stored message with a timestamp
+// between start and end (inclusive), oldest first.
+func (s *MemoryStore) GetMessagesInTimeRange(start, end time.Time) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []Message
+	for _, m := range s.messages {
+		if !m.Timestamp.Before(start) && !m.Timestamp.After(end) {
+			matched = append(matched, m)
+		}
+	}
+	return matched, nil
+}
+
+// DeleteMessagesByDate removes every stored message for date.
+func (s *MemoryStore) DeleteMessagesByDate(date string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.messages[:0]
+	for _, m := range s.messages {
+		if m.Date != date {
+			kept = append(kept, m)
+		}
+	}
+	s.messages = kept
+	return nil
+}
+
+// SearchMessages scans every stored message matching filter and returns up
+// to filter.Limit results (default 100), newest first. Keyword is matched
+// as a case-insensitive substring, there being no full-text index over an
+// in-memory slice.
+func (s *MemoryStore) SearchMessages(filter SearchFilter) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var matched []Message
+	for _, m := range s.messages {
+		if filter.Channel != "" && m.Channel != filter.Channel {
+			continue
+		}
+		if filter.Sender != "" && m.Sender != filter.Sender {
+			continue
+		}
+		if filter.Keyword != "" && !strings.Contains(strings.ToLower(m.Message), strings.ToLower(filter.Keyword)) {
+			continue
+		}
+		if !filter.Since.IsZero() && m.Timestamp.Before(filter.Since) {
+			continue
+		}
+		if !filter.Until.IsZero() && m.Timestamp.After(filter.Until) {
+			continue
+		}
+		if filter.BeforeEID > 0 && m.EID >= filter.BeforeEID {
+			continue
+		}
+		if filter.AfterEID > 0 && m.EID <= filter.AfterEID {
+			continue
+		}
+		matched = append(matched, m)
+	}
+
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+// Close is a no-op; MemoryStore holds no external resources.
+func (s *MemoryStore) Close() error {
+	return nil
+}
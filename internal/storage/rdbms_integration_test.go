@@ -0,0 +1,127 @@
+//go:build integration
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// startPostgresContainer brings up a real Postgres instance and returns an
+// RDBMSStore pointed at it, tearing the container down when the test
+// completes.
+func startPostgresContainer(t *testing.T) *RDBMSStore {
+	t.Helper()
+
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "postgres:16-alpine",
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_USER":     "watcher",
+				"POSTGRES_PASSWORD": "watcher",
+				"POSTGRES_DB":       "watcher",
+			},
+			WaitingFor: wait.ForListeningPort("5432/tcp").WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+	t.Cleanup(func() {
+		if termErr := container.Terminate(context.Background()); termErr != nil {
+			t.Logf("failed to terminate postgres container: %v", termErr)
+		}
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get postgres host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		t.Fatalf("failed to get postgres port: %v", err)
+	}
+
+	dsn := "postgres://watcher:watcher@" + host + ":" + port.Port() + "/watcher?sslmode=disable"
+
+	var store *RDBMSStore
+	for attempt := 0; attempt < 10; attempt++ {
+		store, err = NewPostgresStore(dsn)
+		if err == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("failed to open postgres store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func TestRDBMSStorePostgresInsertAndFetchByDate(t *testing.T) {
+	store := startPostgresContainer(t)
+
+	now := time.Now()
+	msg := &Message{
+		Channel:   "#test",
+		Timestamp: now,
+		Sender:    "alice",
+		Message:   "hello from postgres",
+		Date:      now.Format("2006-01-02"),
+		EID:       1,
+		Tags:      Tags{"msgid": "abc123"},
+	}
+	if err := store.InsertMessage(msg); err != nil {
+		t.Fatalf("InsertMessage returned error: %v", err)
+	}
+
+	got, err := store.GetMessagesByDate(now.Format("2006-01-02"))
+	if err != nil {
+		t.Fatalf("GetMessagesByDate returned error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(got))
+	}
+	if got[0].Message != "hello from postgres" {
+		t.Errorf("expected message to round-trip, got %q", got[0].Message)
+	}
+	if got[0].Tags["msgid"] != "abc123" {
+		t.Errorf("expected tags to round-trip, got %v", got[0].Tags)
+	}
+}
+
+func TestRDBMSStorePostgresInsertMessageIgnoresDuplicateEID(t *testing.T) {
+	store := startPostgresContainer(t)
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		msg := &Message{
+			Channel:   "#test",
+			Timestamp: now,
+			Sender:    "alice",
+			Message:   "hello",
+			Date:      now.Format("2006-01-02"),
+			EID:       42,
+		}
+		if err := store.InsertMessage(msg); err != nil {
+			t.Fatalf("InsertMessage returned error: %v", err)
+		}
+	}
+
+	got, err := store.GetMessagesByDate(now.Format("2006-01-02"))
+	if err != nil {
+		t.Fatalf("GetMessagesByDate returned error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected duplicate eid to be ignored, got %d messages", len(got))
+	}
+}
@@ -2,12 +2,63 @@ package storage
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
 	_ "modernc.org/sqlite"
+
+	"irccloud-watcher/internal/metrics"
 )
 
+// Tags holds a message's IRCv3 message-tags (e.g. "account", "msgid",
+// "+draft/reply", "+draft/react"), persisted as a JSON object in a single
+// TEXT column rather than a separate table, since tags are always read and
+// written alongside their message and never queried on their own.
+type Tags map[string]string
+
+// Value implements driver.Valuer, encoding nil/empty Tags as NULL instead
+// of the literal string "null" or "{}".
+func (t Tags) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner, decoding a NULL or empty column back into a
+// nil Tags rather than erroring.
+func (t *Tags) Scan(src any) error {
+	if src == nil {
+		*t = nil
+		return nil
+	}
+
+	var raw []byte
+	switch v := src.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Tags", src)
+	}
+
+	if len(raw) == 0 {
+		*t = nil
+		return nil
+	}
+	return json.Unmarshal(raw, t)
+}
+
 // Message represents a message from an IRC channel.
 type Message struct {
 	ID        int       `db:"id"`
@@ -17,6 +68,22 @@ type Message struct {
 	Message   string    `db:"message"`
 	Date      string    `db:"date"`
 	EID       int64     `db:"eid"`
+
+	// Tags holds the IRCv3 message-tags IRCCloud forwarded alongside this
+	// message, if any (see the Tags type doc comment).
+	Tags Tags `db:"tags"`
+}
+
+// Enrichment holds LLM-derived metadata for a stored message, joined to it
+// by EID.
+type Enrichment struct {
+	EID       int64     `db:"eid"`
+	Channel   string    `db:"channel"`
+	Summary   string    `db:"summary"`
+	Language  string    `db:"language"`
+	Sentiment string    `db:"sentiment"`
+	Entities  string    `db:"entities"`
+	CreatedAt time.Time `db:"created_at"`
 }
 
 // DB is a wrapper around sqlx.DB for SQLite operations.
@@ -31,6 +98,14 @@ func NewDB(dataSourceName string) (*DB, error) {
 		return nil, err
 	}
 
+	// SQLite allows only one writer at a time; a second concurrent writer
+	// fails immediately with SQLITE_BUSY rather than waiting. Limiting the
+	// pool to a single connection serializes writes through database/sql's
+	// own queue instead, which callers that insert from multiple goroutines
+	// (the handler worker pool, processBacklog's sharded backlog workers)
+	// rely on to not drop messages under contention.
+	db.SetMaxOpenConns(1)
+
 	if err := createSchema(db); err != nil {
 		return nil, err
 	}
@@ -48,12 +123,45 @@ func createSchema(db *sqlx.DB) error {
 		sender TEXT,
 		message TEXT,
 		date DATE NOT NULL,
-		eid INTEGER UNIQUE
+		eid INTEGER UNIQUE,
+		tags TEXT
 	);
 	
 	CREATE INDEX IF NOT EXISTS idx_messages_date ON messages(date);
 	CREATE INDEX IF NOT EXISTS idx_messages_channel ON messages(channel);
 	CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_eid ON messages(eid);
+
+	CREATE TABLE IF NOT EXISTS eid_cache (
+		eid INTEGER PRIMARY KEY,
+		rank INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS enrichments (
+		eid INTEGER PRIMARY KEY,
+		channel TEXT NOT NULL,
+		summary TEXT,
+		language TEXT,
+		sentiment TEXT,
+		entities TEXT,
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+		message,
+		content='messages',
+		content_rowid='id'
+	);
+
+	CREATE TRIGGER IF NOT EXISTS messages_ai AFTER INSERT ON messages BEGIN
+		INSERT INTO messages_fts(rowid, message) VALUES (new.id, new.message);
+	END;
+	CREATE TRIGGER IF NOT EXISTS messages_ad AFTER DELETE ON messages BEGIN
+		INSERT INTO messages_fts(messages_fts, rowid, message) VALUES ('delete', old.id, old.message);
+	END;
+	CREATE TRIGGER IF NOT EXISTS messages_au AFTER UPDATE ON messages BEGIN
+		INSERT INTO messages_fts(messages_fts, rowid, message) VALUES ('delete', old.id, old.message);
+		INSERT INTO messages_fts(rowid, message) VALUES (new.id, new.message);
+	END;
 	`
 	_, err := db.Exec(schema)
 	if err != nil {
@@ -67,12 +175,27 @@ func createSchema(db *sqlx.DB) error {
 	// This will fail silently if the column already exists, which is expected
 	_, _ = db.Exec(migrationSchema)
 
+	// Handle migration for existing databases - add tags column if it doesn't exist
+	_, _ = db.Exec(`ALTER TABLE messages ADD COLUMN tags TEXT;`)
+
 	// Create the unique index if it doesn't exist (will fail silently if exists)
 	indexSchema := `
 	CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_eid ON messages(eid);
 	`
 	_, _ = db.Exec(indexSchema)
 
+	// Backfill messages_fts for rows inserted before the virtual table
+	// existed (or by any path that bypassed the messages_ai trigger, e.g.
+	// a restored backup). Safe to run on every startup: rows already
+	// indexed are excluded by the NOT IN subquery. That subquery reads
+	// messages_fts_docsize, which holds one row per indexed message; a scan
+	// of the external-content messages_fts itself reads the messages table.
+	backfillFTS := `
+	INSERT INTO messages_fts(rowid, message)
+	SELECT id, message FROM messages WHERE id NOT IN (SELECT id FROM messages_fts_docsize);
+	`
+	_, _ = db.Exec(backfillFTS)
+
 	return nil
 }
 
@@ -81,11 +204,19 @@ func (db *DB) InsertMessage(m *Message) error {
 	// Use INSERT OR IGNORE to handle duplicates based on EID uniqueness
 	// EID is IRCCloud's unique event identifier, so this is the most reliable deduplication
 	query := `
-	INSERT OR IGNORE INTO messages (channel, timestamp, sender, message, date, eid)
-	VALUES (:channel, :timestamp, :sender, :message, :date, :eid)
+	INSERT OR IGNORE INTO messages (channel, timestamp, sender, message, date, eid, tags)
+	VALUES (:channel, :timestamp, :sender, :message, :date, :eid, :tags)
 	`
-	_, err := db.DB.NamedExec(query, m)
-	return err
+	result, err := db.DB.NamedExec(query, m)
+	if err != nil {
+		return err
+	}
+	// INSERT OR IGNORE reports success even when the row was a duplicate, so
+	// only count rows actually written.
+	if affected, affectedErr := result.RowsAffected(); affectedErr == nil && affected > 0 {
+		metrics.MessagesInsertedTotal.WithLabelValues(m.Channel).Inc()
+	}
+	return nil
 }
 
 // GetMessagesByDate retrieves all messages for a given date.
@@ -102,6 +233,23 @@ func (db *DB) GetMessagesByDate(date string) ([]Message, error) {
 	return messages, err
 }
 
+// GetMessagesInTimeRange retrieves all messages with a timestamp between
+// start and end (inclusive), oldest first. Used by the summary generator to
+// gather the last 24 hours of activity.
+func (db *DB) GetMessagesInTimeRange(start, end time.Time) ([]Message, error) {
+	var messages []Message
+	query := `
+	SELECT * FROM messages
+	WHERE timestamp >= ? AND timestamp <= ?
+	ORDER BY timestamp ASC
+	`
+	err := db.DB.Select(&messages, query, start, end)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return messages, err
+}
+
 // DeleteMessagesByDate deletes all messages for a given date.
 func (db *DB) DeleteMessagesByDate(date string) error {
 	query := `
@@ -111,3 +259,193 @@ func (db *DB) DeleteMessagesByDate(date string) error {
 	_, err := db.DB.Exec(query, date)
 	return err
 }
+
+// GetLastEID returns the highest EID stored for channel, or 0 if the
+// channel has no stored messages.
+func (db *DB) GetLastEID(channel string) (int64, error) {
+	var eid sql.NullInt64
+	query := `SELECT MAX(eid) FROM messages WHERE channel = ?`
+	if err := db.DB.Get(&eid, query, channel); err != nil {
+		return 0, err
+	}
+	return eid.Int64, nil
+}
+
+// GetLastEIDPerChannel returns the highest EID stored for every channel that
+// has at least one message, keyed by channel name. Resync uses this to know
+// where to pick up IRCCloud's history after a restart or network drop.
+func (db *DB) GetLastEIDPerChannel() (map[string]int64, error) {
+	rows, err := db.DB.Queryx(`SELECT channel, MAX(eid) AS eid FROM messages GROUP BY channel`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]int64)
+	for rows.Next() {
+		var channel string
+		var eid sql.NullInt64
+		if err := rows.Scan(&channel, &eid); err != nil {
+			return nil, err
+		}
+		result[channel] = eid.Int64
+	}
+	return result, rows.Err()
+}
+
+// RegisterMetrics registers a db_open_connections gauge backed by the
+// underlying connection pool's stats with reg. Callers - main.go, once per
+// process - wire this in alongside the rest of the Prometheus setup; unlike
+// the promauto-registered collectors in package metrics, this one reports
+// a value (the pool size) that only exists once a *DB is opened.
+func (db *DB) RegisterMetrics(reg prometheus.Registerer) error {
+	return reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "irccloud_watcher_db_open_connections",
+		Help: "Open connections held by the underlying SQL connection pool.",
+	}, func() float64 {
+		return float64(db.DB.Stats().OpenConnections)
+	}))
+}
+
+// SaveEIDCache replaces the persisted EID dedup cache snapshot with eids,
+// most-recent first. Used to survive a process restart without re-storing
+// messages IRCCloud replays via an oob_include backlog fetch right after
+// reconnect.
+func (db *DB) SaveEIDCache(eids []int64) error {
+	tx, err := db.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM eid_cache"); err != nil {
+		return err
+	}
+	for rank, eid := range eids {
+		if _, err := tx.Exec("INSERT INTO eid_cache (eid, rank) VALUES (?, ?)", eid, rank); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadEIDCache returns the persisted EID dedup cache snapshot, most-recent
+// first.
+func (db *DB) LoadEIDCache() ([]int64, error) {
+	var eids []int64
+	err := db.DB.Select(&eids, "SELECT eid FROM eid_cache ORDER BY rank ASC")
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return eids, err
+}
+
+// InsertEnrichment stores e, replacing any prior enrichment for the same
+// EID (a reconciler retry overwrites the earlier, presumably-failed, row).
+func (db *DB) InsertEnrichment(e *Enrichment) error {
+	query := `
+	INSERT OR REPLACE INTO enrichments (eid, channel, summary, language, sentiment, entities, created_at)
+	VALUES (:eid, :channel, :summary, :language, :sentiment, :entities, :created_at)
+	`
+	_, err := db.DB.NamedExec(query, e)
+	return err
+}
+
+// GetMessagesMissingEnrichment returns up to limit messages that have no
+// corresponding enrichments row, oldest first, for the background
+// reconciler to retry.
+func (db *DB) GetMessagesMissingEnrichment(limit int) ([]Message, error) {
+	var messages []Message
+	query := `
+	SELECT m.* FROM messages m
+	LEFT JOIN enrichments e ON m.eid = e.eid
+	WHERE e.eid IS NULL
+	ORDER BY m.id ASC
+	LIMIT ?
+	`
+	err := db.DB.Select(&messages, query, limit)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return messages, err
+}
+
+// SearchFilter scopes a SearchMessages call. Every field is optional (its
+// zero value leaves that predicate unconstrained) except Limit, which
+// SearchMessages defaults to 100 when unset.
+//
+// BeforeEID/AfterEID form a cursor for paging through results without the
+// usual OFFSET-scan cost: pass the EID of the last row seen as BeforeEID
+// (paging backward in time) or AfterEID (paging forward) to fetch the next
+// page.
+type SearchFilter struct {
+	Channel   string
+	Sender    string
+	Keyword   string
+	Since     time.Time
+	Until     time.Time
+	BeforeEID int64
+	AfterEID  int64
+	Limit     int
+}
+
+// SearchMessages runs filter against the message store, newest first. A
+// non-empty Keyword is matched against the messages_fts full-text index
+// instead of a LIKE scan, so keyword lookups stay fast over a large
+// history. This is the query engine behind the CLI's --search flag and is
+// exported for callers that want the same CHATHISTORY/SEARCH-style access
+// without writing SQL themselves.
+func (db *DB) SearchMessages(filter SearchFilter) ([]Message, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	from := "messages m"
+	var conditions []string
+	var args []any
+
+	if filter.Keyword != "" {
+		from = "messages m JOIN messages_fts ON messages_fts.rowid = m.id"
+		conditions = append(conditions, "messages_fts MATCH ?")
+		args = append(args, filter.Keyword)
+	}
+	if filter.Channel != "" {
+		conditions = append(conditions, "m.channel = ?")
+		args = append(args, filter.Channel)
+	}
+	if filter.Sender != "" {
+		conditions = append(conditions, "m.sender = ?")
+		args = append(args, filter.Sender)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "m.timestamp >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "m.timestamp <= ?")
+		args = append(args, filter.Until)
+	}
+	if filter.BeforeEID > 0 {
+		conditions = append(conditions, "m.eid < ?")
+		args = append(args, filter.BeforeEID)
+	}
+	if filter.AfterEID > 0 {
+		conditions = append(conditions, "m.eid > ?")
+		args = append(args, filter.AfterEID)
+	}
+
+	query := "SELECT m.* FROM " + from
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY m.eid DESC LIMIT ?"
+	args = append(args, limit)
+
+	var messages []Message
+	err := db.DB.Select(&messages, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return messages, err
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	if err != nil {
+		t.Fatalf("Failed to create temp database: %v", err)
+	}
+	tmpFile.Close()
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+
+	db, err := NewDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestGetLastEIDNoMessages(t *testing.T) {
+	db := newTestDB(t)
+
+	eid, err := db.GetLastEID("#empty")
+	if err != nil {
+		t.Fatalf("GetLastEID returned error: %v", err)
+	}
+	if eid != 0 {
+		t.Errorf("expected 0 for a channel with no messages, got %d", eid)
+	}
+}
+
+func TestGetLastEIDReturnsHighest(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Now()
+	for i, eid := range []int64{100, 300, 200} {
+		msg := &Message{
+			Channel:   "#test",
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+			Sender:    "user1",
+			Message:   "hello",
+			Date:      now.Format("2006-01-02"),
+			EID:       eid,
+		}
+		if err := db.InsertMessage(msg); err != nil {
+			t.Fatalf("InsertMessage failed: %v", err)
+		}
+	}
+
+	eid, err := db.GetLastEID("#test")
+	if err != nil {
+		t.Fatalf("GetLastEID returned error: %v", err)
+	}
+	if eid != 300 {
+		t.Errorf("expected 300, got %d", eid)
+	}
+}
+
+func TestGetLastEIDPerChannel(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Now()
+	messages := []*Message{
+		{Channel: "#a", Timestamp: now, Sender: "u1", Message: "m1", Date: now.Format("2006-01-02"), EID: 10},
+		{Channel: "#a", Timestamp: now, Sender: "u1", Message: "m2", Date: now.Format("2006-01-02"), EID: 20},
+		{Channel: "#b", Timestamp: now, Sender: "u2", Message: "m3", Date: now.Format("2006-01-02"), EID: 5},
+	}
+	for _, msg := range messages {
+		if err := db.InsertMessage(msg); err != nil {
+			t.Fatalf("InsertMessage failed: %v", err)
+		}
+	}
+
+	lastEIDs, err := db.GetLastEIDPerChannel()
+	if err != nil {
+		t.Fatalf("GetLastEIDPerChannel returned error: %v", err)
+	}
+
+	want := map[string]int64{"#a": 20, "#b": 5}
+	if len(lastEIDs) != len(want) {
+		t.Fatalf("expected %d channels, got %d (%v)", len(want), len(lastEIDs), lastEIDs)
+	}
+	for channel, eid := range want {
+		if lastEIDs[channel] != eid {
+			t.Errorf("channel %s: expected eid %d, got %d", channel, eid, lastEIDs[channel])
+		}
+	}
+}
+
+func TestGetLastEIDPerChannelNoMessages(t *testing.T) {
+	db := newTestDB(t)
+
+	lastEIDs, err := db.GetLastEIDPerChannel()
+	if err != nil {
+		t.Fatalf("GetLastEIDPerChannel returned error: %v", err)
+	}
+	if len(lastEIDs) != 0 {
+		t.Errorf("expected no channels, got %v", lastEIDs)
+	}
+}
+
+func TestInsertMessageRoundTripsTags(t *testing.T) {
+	db := newTestDB(t)
+
+	msg := &Message{
+		Channel:   "#test",
+		Timestamp: time.Now(),
+		Sender:    "alice",
+		Message:   "hello",
+		Date:      "2026-01-01",
+		EID:       1,
+		Tags:      Tags{"msgid": "abc123", "+draft/reply": "xyz789"},
+	}
+	if err := db.InsertMessage(msg); err != nil {
+		t.Fatalf("InsertMessage returned error: %v", err)
+	}
+
+	got, err := db.GetMessagesByDate("2026-01-01")
+	if err != nil {
+		t.Fatalf("GetMessagesByDate returned error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(got))
+	}
+	if got[0].Tags["msgid"] != "abc123" || got[0].Tags["+draft/reply"] != "xyz789" {
+		t.Errorf("expected tags to round-trip, got %v", got[0].Tags)
+	}
+}
+
+func TestInsertMessageWithNoTagsScansAsNil(t *testing.T) {
+	db := newTestDB(t)
+
+	msg := &Message{
+		Channel:   "#test",
+		Timestamp: time.Now(),
+		Sender:    "alice",
+		Message:   "hello",
+		Date:      "2026-01-02",
+		EID:       2,
+	}
+	if err := db.InsertMessage(msg); err != nil {
+		t.Fatalf("InsertMessage returned error: %v", err)
+	}
+
+	got, err := db.GetMessagesByDate("2026-01-02")
+	if err != nil {
+		t.Fatalf("GetMessagesByDate returned error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(got))
+	}
+	if len(got[0].Tags) != 0 {
+		t.Errorf("expected no tags, got %v", got[0].Tags)
+	}
+}
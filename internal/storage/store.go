@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// MessageStore is the storage backend IRCCloudClient and the summary/search
+// tooling persist and query messages through. DB (SQLite) is the default
+// implementation; FSStore and MemoryStore trade its indexed SQL queries for
+// a grep-able flat-file layout and an unpersisted in-process store,
+// respectively; RDBMSStore backs the same interface with a standalone
+// Postgres or MySQL server for operators who'd rather point the watcher at
+// infra they already run.
+//
+// Some callers still depend on *DB directly for features that only make
+// sense backed by a real database - commands.Runner's named SQL queries,
+// and IRCCloudClient's EID-cache/enrichment persistence - so switching
+// Driver away from "sqlite" loses those, not just the backend.
+type MessageStore interface {
+	InsertMessage(m *Message) error
+	GetMessagesByDate(date string) ([]Message, error)
+	GetMessagesInTimeRange(start, end time.Time) ([]Message, error)
+	DeleteMessagesByDate(date string) error
+	SearchMessages(filter SearchFilter) ([]Message, error)
+	Close() error
+}
+
+var (
+	_ MessageStore = (*DB)(nil)
+	_ MessageStore = (*FSStore)(nil)
+	_ MessageStore = (*MemoryStore)(nil)
+	_ MessageStore = (*RDBMSStore)(nil)
+)
+
+// Open builds the MessageStore named by driver, interpreting source
+// according to which one: a SQLite DSN/file path for "sqlite" (the
+// default), a root log directory for "fs", ignored for "memory", or a
+// Postgres/MySQL DSN for "postgres"/"mysql" (see RDBMSStore).
+func Open(driver, source string) (MessageStore, error) {
+	switch driver {
+	case "", "sqlite":
+		return NewDB(source)
+	case "fs":
+		return NewFSStore(source)
+	case "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(source)
+	case "mysql":
+		return NewMySQLStore(source)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
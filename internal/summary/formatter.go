@@ -0,0 +1,180 @@
+package summary
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// FormattedGroup is one MessageGroup's rendered summary: the repo-wide data
+// every Formatter renders from, whether that Summary text came from the
+// LLM's per-group ("map" stage) output or, absent an LLM provider, the
+// group's messages joined together.
+type FormattedGroup struct {
+	Channel      string
+	Topic        string
+	Summary      string
+	Participants []string
+	MessageCount int
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+// SummaryDocument is a full generated summary, structured enough for a
+// Formatter to render one entry per channel/topic instead of a single
+// narrative blob. Narrative, when set, is the reduce stage's combined
+// digest across every group - MarkdownFormatter is the only implementation
+// that uses it, since JSON and Atom both want one entry per group.
+type SummaryDocument struct {
+	GeneratedAt time.Time
+	Narrative   string
+	Provider    string
+	Model       string
+	Groups      []FormattedGroup
+}
+
+// Formatter renders a SummaryDocument into a specific output representation.
+// Selected by Config.SummaryFormat / ChannelConfig.SummaryFormat via
+// FormatterFor.
+type Formatter interface {
+	Format(doc SummaryDocument) (string, error)
+}
+
+// formatters maps a summary_format config value to its Formatter.
+var formatters = map[string]Formatter{
+	"markdown": MarkdownFormatter{},
+	"json":     JSONFormatter{},
+	"atom":     AtomFormatter{},
+}
+
+// FormatterFor returns the Formatter registered for name, or
+// MarkdownFormatter if name is empty or unrecognized - an unset
+// summary_format behaves exactly like the original Markdown-only output.
+func FormatterFor(name string) Formatter {
+	if f, ok := formatters[name]; ok {
+		return f
+	}
+	return MarkdownFormatter{}
+}
+
+// MarkdownFormatter reproduces generateLLMSummary/formatSummary's original
+// output: a title, a byline naming the provider (or noting basic
+// formatting), and the narrative verbatim.
+type MarkdownFormatter struct{}
+
+func (MarkdownFormatter) Format(doc SummaryDocument) (string, error) {
+	summary := fmt.Sprintf("# Daily IRC Summary - %s\n\n", doc.GeneratedAt.Format("January 2, 2006"))
+	if doc.Provider != "" {
+		summary += fmt.Sprintf("*Generated using %s (%s)*\n\n", doc.Provider, doc.Model)
+	} else {
+		summary += "*Generated using basic text formatting*\n\n"
+	}
+	summary += doc.Narrative
+	return summary, nil
+}
+
+// jsonGroup is JSONFormatter's per-group output shape.
+type jsonGroup struct {
+	Channel      string    `json:"channel"`
+	Topic        string    `json:"topic"`
+	Summary      string    `json:"summary"`
+	Participants []string  `json:"participants"`
+	MessageCount int       `json:"message_count"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+}
+
+// jsonDocument is JSONFormatter's top-level output shape.
+type jsonDocument struct {
+	GeneratedAt time.Time   `json:"generated_at"`
+	Groups      []jsonGroup `json:"groups"`
+}
+
+// JSONFormatter emits doc as structured per-channel groups - topic,
+// participants, message count - for downstream tooling (dashboards, further
+// LLM processing) that would otherwise have to re-parse Markdown.
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(doc SummaryDocument) (string, error) {
+	out := jsonDocument{
+		GeneratedAt: doc.GeneratedAt,
+		Groups:      make([]jsonGroup, len(doc.Groups)),
+	}
+	for i, group := range doc.Groups {
+		out.Groups[i] = jsonGroup{
+			Channel:      group.Channel,
+			Topic:        group.Topic,
+			Summary:      group.Summary,
+			Participants: group.Participants,
+			MessageCount: group.MessageCount,
+			StartTime:    group.StartTime,
+			EndTime:      group.EndTime,
+		}
+	}
+
+	body, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not marshal summary to JSON: %w", err)
+	}
+	return string(body), nil
+}
+
+// atomFeed and atomEntry mirror the Atom 1.0 syndication format (RFC 4287),
+// close enough to an ActivityPub Collection of Notes that a daily digest
+// feed can be repurposed there without a format change, just a different
+// Content-Type.
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// AtomFormatter emits one <entry> per MessageGroup, the channel+topic as
+// title and the group's summary as content, so a daily digest can be piped
+// into an existing feed reader.
+type AtomFormatter struct{}
+
+func (AtomFormatter) Format(doc SummaryDocument) (string, error) {
+	feed := atomFeed{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		Title:   fmt.Sprintf("IRC Daily Summary - %s", doc.GeneratedAt.Format("January 2, 2006")),
+		ID:      fmt.Sprintf("urn:irccloud-watcher:summary:%s", doc.GeneratedAt.Format(time.RFC3339)),
+		Updated: doc.GeneratedAt.Format(time.RFC3339),
+		Entries: make([]atomEntry, len(doc.Groups)),
+	}
+
+	for i, group := range doc.Groups {
+		title := group.Channel
+		if group.Topic != "" {
+			title = fmt.Sprintf("%s: %s", group.Channel, group.Topic)
+		}
+		feed.Entries[i] = atomEntry{
+			Title:   title,
+			ID:      fmt.Sprintf("urn:irccloud-watcher:summary:%s:%d", doc.GeneratedAt.Format(time.RFC3339), i),
+			Updated: group.EndTime.Format(time.RFC3339),
+			Content: atomContent{Type: "text", Text: group.Summary},
+		}
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not marshal summary to Atom: %w", err)
+	}
+	return xml.Header + string(body) + "\n", nil
+}
@@ -0,0 +1,154 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"irccloud-watcher/internal/config"
+	"irccloud-watcher/internal/storage"
+)
+
+func testSummaryDocument() SummaryDocument {
+	return SummaryDocument{
+		GeneratedAt: time.Date(2026, 7, 30, 9, 0, 0, 0, time.UTC),
+		Narrative:   "people discussed the release",
+		Provider:    "ollama",
+		Model:       "llama3.2",
+		Groups: []FormattedGroup{
+			{
+				Channel:      "#general",
+				Topic:        "release planning",
+				Summary:      "the team agreed on a release date",
+				Participants: []string{"alice", "bob"},
+				MessageCount: 12,
+				StartTime:    time.Date(2026, 7, 30, 8, 0, 0, 0, time.UTC),
+				EndTime:      time.Date(2026, 7, 30, 8, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestFormatterForFallsBackToMarkdown(t *testing.T) {
+	for _, name := range []string{"", "yaml", "markdown"} {
+		if _, ok := FormatterFor(name).(MarkdownFormatter); !ok {
+			t.Errorf("FormatterFor(%q) = %T, want MarkdownFormatter", name, FormatterFor(name))
+		}
+	}
+	if _, ok := FormatterFor("json").(JSONFormatter); !ok {
+		t.Errorf("FormatterFor(%q) = %T, want JSONFormatter", "json", FormatterFor("json"))
+	}
+	if _, ok := FormatterFor("atom").(AtomFormatter); !ok {
+		t.Errorf("FormatterFor(%q) = %T, want AtomFormatter", "atom", FormatterFor("atom"))
+	}
+}
+
+func TestMarkdownFormatterIncludesNarrativeAndProvider(t *testing.T) {
+	out, err := MarkdownFormatter{}.Format(testSummaryDocument())
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	for _, want := range []string{
+		"# Daily IRC Summary - July 30, 2026",
+		"ollama",
+		"llama3.2",
+		"people discussed the release",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got: %s", want, out)
+		}
+	}
+}
+
+func TestMarkdownFormatterWithoutProviderNotesBasicFormatting(t *testing.T) {
+	doc := testSummaryDocument()
+	doc.Provider = ""
+
+	out, err := MarkdownFormatter{}.Format(doc)
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	if !strings.Contains(out, "*Generated using basic text formatting*") {
+		t.Errorf("expected basic-formatting byline, got: %s", out)
+	}
+}
+
+func TestJSONFormatterEmitsStructuredGroups(t *testing.T) {
+	out, err := JSONFormatter{}.Format(testSummaryDocument())
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	var parsed jsonDocument
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("Format produced invalid JSON: %v\n%s", err, out)
+	}
+	if len(parsed.Groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(parsed.Groups))
+	}
+
+	group := parsed.Groups[0]
+	if group.Channel != "#general" || group.Topic != "release planning" {
+		t.Errorf("unexpected channel/topic: %+v", group)
+	}
+	if group.MessageCount != 12 {
+		t.Errorf("expected message_count 12, got %d", group.MessageCount)
+	}
+	if len(group.Participants) != 2 {
+		t.Errorf("expected 2 participants, got %v", group.Participants)
+	}
+}
+
+func TestAtomFormatterEmitsOneEntryPerGroup(t *testing.T) {
+	out, err := AtomFormatter{}.Format(testSummaryDocument())
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	if !strings.Contains(out, `<feed xmlns="http://www.w3.org/2005/Atom">`) {
+		t.Errorf("expected an Atom feed element, got: %s", out)
+	}
+	if strings.Count(out, "<entry>") != 1 {
+		t.Errorf("expected exactly one <entry> for the one group, got: %s", out)
+	}
+	if !strings.Contains(out, "<title>#general: release planning</title>") {
+		t.Errorf("expected entry title to combine channel and topic, got: %s", out)
+	}
+	if !strings.Contains(out, "the team agreed on a release date") {
+		t.Errorf("expected entry content to be the group's summary, got: %s", out)
+	}
+}
+
+func TestBuildSummaryDocumentWithoutProviderUsesPlainGroupText(t *testing.T) {
+	cfg := &config.Config{}
+	generator := NewGenerator(cfg)
+
+	groups := []MessageGroup{
+		{
+			Channel: "#general",
+			Messages: []storage.Message{
+				{Sender: "alice", Message: "hello", Timestamp: time.Now()},
+				{Sender: "bob", Message: "hi there", Timestamp: time.Now()},
+			},
+		},
+	}
+
+	doc, err := generator.buildSummaryDocument(context.Background(), groups, nil)
+	if err != nil {
+		t.Fatalf("buildSummaryDocument returned error: %v", err)
+	}
+	if len(doc.Groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(doc.Groups))
+	}
+
+	group := doc.Groups[0]
+	if !strings.Contains(group.Summary, "hello") || !strings.Contains(group.Summary, "hi there") {
+		t.Errorf("expected plain group text to include both messages, got: %q", group.Summary)
+	}
+	if len(group.Participants) != 2 {
+		t.Errorf("expected 2 distinct participants, got %v", group.Participants)
+	}
+}
@@ -4,14 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"irccloud-watcher/internal/config"
 	"irccloud-watcher/internal/llm"
+	"irccloud-watcher/internal/metrics"
+	"irccloud-watcher/internal/plugins"
 	"irccloud-watcher/internal/storage"
 )
 
@@ -19,6 +23,35 @@ import (
 type Generator struct {
 	config   *config.Config
 	provider llm.Provider
+	prompts  map[string]*promptSet
+
+	// providers holds every configured named provider, keyed by name.
+	// providerChain is the order they're tried in: the default provider
+	// first, then each entry in Fallbacks. breakers holds one persistent
+	// CircuitBreaker per provider name, shared across every chain built
+	// from providers so a failure recorded via one channel's chain is
+	// visible to every other chain that includes the same provider.
+	providers       map[string]llm.Provider
+	providerConfigs map[string]config.NamedProviderConfig
+	providerChain   []string
+	breakers        map[string]*llm.CircuitBreaker
+
+	// llmMu guards lastLLMError/llmFailureCount, which generateWithChain
+	// updates from the concurrent map-stage workers mapGroupSummaries
+	// spawns (see LastLLMError/LLMFailureCount).
+	llmMu           sync.Mutex
+	lastLLMError    error
+	llmFailureCount int
+
+	// plugins, if set via SetPluginManager, can rewrite the prompt sent to
+	// the LLM and the generated summary before it's written to disk.
+	plugins *plugins.Manager
+}
+
+// SetPluginManager attaches a plugin manager so the pre_summary/post_summary
+// hooks run during GenerateDailySummary.
+func (g *Generator) SetPluginManager(m *plugins.Manager) {
+	g.plugins = m
 }
 
 // MessageGroup represents a group of related messages.
@@ -28,6 +61,11 @@ type MessageGroup struct {
 	Messages  []storage.Message
 	StartTime time.Time
 	EndTime   time.Time
+
+	// TopicScores holds the tf*idf score assignTopics computed for each
+	// term that makes up Topic, keyed by its stemmed form. It's nil when
+	// Topic fell back to "General Discussion".
+	TopicScores map[string]float64
 }
 
 // Template holds configurable prompt templates.
@@ -42,36 +80,141 @@ func NewGenerator(cfg *config.Config) *Generator {
 		config: cfg,
 	}
 
-	// Initialize LLM provider if configured
-	if cfg.LLM.Provider != "" {
-		g.initializeLLMProvider()
+	// initializeLLMProvider no-ops safely when neither the legacy Provider
+	// field nor the named Providers list is set, so it's always safe to call.
+	g.initializeLLMProvider()
+
+	if err := g.loadPromptTemplates(); err != nil {
+		log.Printf("⚠️ Failed to load prompt templates: %v, falling back to built-in defaults", err)
+		g.prompts = map[string]*promptSet{"default": {systemPrompt: defaultSystemPrompt}}
 	}
 
 	return g
 }
 
-// initializeLLMProvider initializes the LLM provider based on config.
+// initializeLLMProvider initializes the named LLM provider profiles and
+// resolves the default/fallback chain. Configs built without going through
+// config.LoadConfig (e.g. in tests) use the flat Provider field directly;
+// LoadConfig itself migrates that into a single "default" named profile.
 func (g *Generator) initializeLLMProvider() {
-	switch g.config.LLM.Provider {
-	case "ollama":
-		providerConfig := &llm.ProviderConfig{
-			BaseURL:            g.config.LLM.BaseURL,
-			DefaultModel:       g.config.LLM.Model,
-			DefaultMaxTokens:   g.config.LLM.MaxTokens,
-			DefaultTemperature: g.config.LLM.Temperature,
-			Timeout:            30 * time.Second,
-			RetryAttempts:      3,
-			RetryDelay:         1 * time.Second,
-			MaxRetryDelay:      10 * time.Second,
-		}
-		g.provider = llm.NewOllamaClient(providerConfig)
-	default:
-		log.Printf("⚠️ Unsupported LLM provider: %s, falling back to basic formatting", g.config.LLM.Provider)
+	namedProviders := g.config.LLM.Providers
+	defaultName := g.config.LLM.Default
+
+	if len(namedProviders) == 0 && g.config.LLM.Provider != "" {
+		namedProviders = []config.NamedProviderConfig{{
+			Name:        "default",
+			Kind:        g.config.LLM.Provider,
+			BaseURL:     g.config.LLM.BaseURL,
+			Model:       g.config.LLM.Model,
+			APIKey:      g.config.LLM.APIKey,
+			Temperature: g.config.LLM.Temperature,
+			MaxTokens:   g.config.LLM.MaxTokens,
+		}}
+		defaultName = "default"
+	}
+
+	g.providers = make(map[string]llm.Provider, len(namedProviders))
+	g.providerConfigs = make(map[string]config.NamedProviderConfig, len(namedProviders))
+	g.breakers = make(map[string]*llm.CircuitBreaker, len(namedProviders))
+	for _, p := range namedProviders {
+		provider, err := newProviderFromConfig(p)
+		if err != nil {
+			log.Printf("⚠️ Skipping LLM provider %q: %v, falling back to basic formatting", p.Name, err)
+			continue
+		}
+		g.providers[p.Name] = provider
+		g.providerConfigs[p.Name] = p
+		g.breakers[p.Name] = llm.NewCircuitBreaker(llm.DefaultCircuitBreakerConfig())
+	}
+
+	g.providerChain = append([]string{defaultName}, g.config.LLM.Fallbacks...)
+	g.provider = g.providers[defaultName]
+}
+
+// newProviderFromConfig builds a Provider for a single named provider
+// profile based on its Kind.
+func newProviderFromConfig(p config.NamedProviderConfig) (llm.Provider, error) {
+	timeout := 30 * time.Second
+	if p.Timeout != "" {
+		if d, err := time.ParseDuration(p.Timeout); err == nil {
+			timeout = d
+		}
+	}
+
+	providerConfig := &llm.ProviderConfig{
+		BaseURL:            p.BaseURL,
+		DefaultModel:       p.Model,
+		DefaultMaxTokens:   p.MaxTokens,
+		DefaultTemperature: p.Temperature,
+		Organization:       p.Organization,
+		APIKey:             p.APIKey,
+		Timeout:            timeout,
+		RetryAttempts:      3,
+		RetryDelay:         1 * time.Second,
+		MaxRetryDelay:      10 * time.Second,
+	}
+
+	provider, err := llm.NewProvider(p.Kind, providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// OllamaClient already retries internally (streamWithRetry, per
+	// ProviderConfig's RetryAttempts/RetryDelay/MaxRetryDelay); wrapping it
+	// in a second, independent RetryingProvider would compound the two
+	// backoff loops, multiplying worst-case latency before a failure
+	// reaches FallbackProvider's circuit breaker.
+	if p.Kind == "ollama" {
+		return provider, nil
+	}
+
+	// Wrap every other provider with retry-with-backoff so a transient
+	// error (a dropped connection, a 500 from an overloaded endpoint)
+	// doesn't immediately count against the provider's CircuitBreaker -
+	// see llm.RetryingProvider's doc comment for how the two compose.
+	return llm.NewRetryingProvider(provider, llm.DefaultRetryConfig()), nil
+}
+
+// WarmLLMProviders ensures every configured Ollama provider's model is
+// pulled and loaded into memory, so the first cron-triggered summary after a
+// fresh deploy doesn't fail with "model not found" and doesn't eat a
+// cold-load penalty inside the user's summary window. Call this once at
+// startup, before the scheduler starts firing - it's deliberately not part
+// of GenerateDailySummary/GenerateChannelSummary's own path. Non-Ollama
+// providers have no equivalent concept and are skipped.
+func (g *Generator) WarmLLMProviders(ctx context.Context) error {
+	var firstErr error
+	for name, provider := range g.providers {
+		ollamaClient, ok := provider.(*llm.OllamaClient)
+		if !ok {
+			continue
+		}
+
+		model := g.providerConfigs[name].Model
+		if model == "" {
+			model = ollamaClient.DefaultModel()
+		}
+
+		log.Printf("🔄 Ensuring Ollama model %q is ready for provider %q...", model, name)
+		err := ollamaClient.EnsureModel(ctx, model, func(p llm.PullProgress) {
+			if p.Total > 0 {
+				log.Printf("⬇️ Pulling %q: %s (%d/%d bytes)", model, p.Status, p.Completed, p.Total)
+			} else {
+				log.Printf("⬇️ Pulling %q: %s", model, p.Status)
+			}
+		})
+		if err != nil {
+			log.Printf("⚠️ Failed to prepare Ollama model %q for provider %q: %v", model, name, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
 	}
+	return firstErr
 }
 
 // GenerateDailySummary generates a summary of messages from the last 24 hours.
-func (g *Generator) GenerateDailySummary(db *storage.DB, outputPath string) error {
+func (g *Generator) GenerateDailySummary(db storage.MessageStore, outputPath string) error {
 	endTime := time.Now()
 	startTime := endTime.Add(-24 * time.Hour)
 
@@ -89,22 +232,12 @@ func (g *Generator) GenerateDailySummary(db *storage.DB, outputPath string) erro
 	filteredMessages := g.filterMessages(messages)
 	groupedMessages := g.groupMessages(filteredMessages)
 
-	var summary string
-
-	// Try LLM generation first, fall back to basic formatting
-	if g.provider != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		defer cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
 
-		llmSummary, llmErr := g.generateLLMSummary(ctx, groupedMessages)
-		if llmErr != nil {
-			log.Printf("⚠️ LLM summary generation failed: %v, falling back to basic formatting", llmErr)
-			summary = g.formatSummary(filteredMessages)
-		} else {
-			summary = llmSummary
-		}
-	} else {
-		summary = g.formatSummary(filteredMessages)
+	summary, err := g.renderSummary(ctx, groupedMessages, filteredMessages, g.providerChain, g.config.SummaryFormat)
+	if err != nil {
+		return fmt.Errorf("could not render summary: %w", err)
 	}
 
 	err = os.WriteFile(outputPath, []byte(summary), 0o644)
@@ -116,6 +249,171 @@ func (g *Generator) GenerateDailySummary(db *storage.DB, outputPath string) erro
 	return nil
 }
 
+// GenerateChannelSummary generates a summary scoped to a single channel,
+// honoring that channel's effective overrides (LLM provider, prompts and
+// output path). It still covers the last 24 hours of messages; only
+// SummaryTime varies per channel, and that's handled by how main.go
+// schedules the call, not by the time window here.
+func (g *Generator) GenerateChannelSummary(db storage.MessageStore, ch config.ChannelConfig) error {
+	endTime := time.Now()
+	startTime := endTime.Add(-24 * time.Hour)
+
+	messages, err := db.GetMessagesInTimeRange(startTime, endTime)
+	if err != nil {
+		return fmt.Errorf("could not get messages for channel %s: %w", ch.Name, err)
+	}
+
+	var channelMessages []storage.Message
+	for _, msg := range messages {
+		if msg.Channel == ch.Name {
+			channelMessages = append(channelMessages, msg)
+		}
+	}
+
+	if len(channelMessages) == 0 {
+		fmt.Printf("No messages found for channel %s in the last 24 hours\n", ch.Name)
+		return nil
+	}
+
+	filtered := g.filterMessages(channelMessages)
+	grouped := g.groupMessages(filtered)
+
+	chain := g.providerChainFor(ch.LLM)
+	if len(chain) > 0 && g.providers[chain[0]] == nil {
+		chain = nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	summaryText, err := g.renderSummary(ctx, grouped, filtered, chain, ch.SummaryFormat)
+	if err != nil {
+		return fmt.Errorf("could not render summary for channel %s: %w", ch.Name, err)
+	}
+
+	if err := os.WriteFile(ch.SummaryOutputPath, []byte(summaryText), 0o644); err != nil {
+		return fmt.Errorf("could not write summary for channel %s to file %s: %w", ch.Name, ch.SummaryOutputPath, err)
+	}
+
+	fmt.Printf("Successfully generated summary for channel %s to %s\n", ch.Name, ch.SummaryOutputPath)
+	return nil
+}
+
+// providerChainFor returns the provider fallback chain to use for a
+// summary: overrideName (a channel's llm override) followed by the global
+// fallbacks, or the default chain when no override is set.
+func (g *Generator) providerChainFor(overrideName string) []string {
+	if overrideName == "" {
+		return g.providerChain
+	}
+	return append([]string{overrideName}, g.config.LLM.Fallbacks...)
+}
+
+// renderSummary produces the final summary text for groups, honoring the
+// effective summary_format: "markdown" (the default) reproduces
+// generateLLMSummary/formatSummary's original single-narrative output
+// unchanged, while "json" and "atom" route through buildSummaryDocument for
+// per-group structured output instead. chain empty or nil skips the LLM
+// entirely and falls back to plain formatting, the same as the original
+// g.provider == nil check.
+func (g *Generator) renderSummary(ctx context.Context, groups []MessageGroup, filtered []storage.Message, chain []string, format string) (string, error) {
+	if _, ok := FormatterFor(format).(MarkdownFormatter); ok {
+		if len(chain) == 0 || g.provider == nil {
+			return g.formatSummary(filtered), nil
+		}
+		summary, err := g.generateLLMSummary(ctx, groups, chain)
+		if err != nil {
+			log.Printf("⚠️ LLM summary generation failed: %v, falling back to basic formatting", err)
+			return g.formatSummary(filtered), nil
+		}
+		return summary, nil
+	}
+
+	doc, err := g.buildSummaryDocument(ctx, groups, chain)
+	if err != nil {
+		log.Printf("⚠️ LLM summary generation failed: %v, falling back to basic formatting", err)
+		if doc, err = g.buildSummaryDocument(ctx, groups, nil); err != nil {
+			return "", err
+		}
+	}
+	return FormatterFor(format).Format(doc)
+}
+
+// buildSummaryDocument produces the per-group data JSON/Atom formatters
+// need: one FormattedGroup per MessageGroup, using the LLM's own per-group
+// ("map" stage) summary when chain is non-empty and a provider is
+// configured, or each group's messages joined together (see
+// plainGroupText) when it's not.
+func (g *Generator) buildSummaryDocument(ctx context.Context, groups []MessageGroup, chain []string) (SummaryDocument, error) {
+	doc := SummaryDocument{GeneratedAt: time.Now()}
+
+	if len(chain) == 0 || g.provider == nil {
+		doc.Groups = make([]FormattedGroup, len(groups))
+		for i, group := range groups {
+			doc.Groups[i] = formattedGroupFrom(group, plainGroupText(group))
+		}
+		return doc, nil
+	}
+
+	maxGroupTokens := g.mapMaxTokens(chain)
+	var mapInputs []MessageGroup
+	for _, group := range groups {
+		mapInputs = append(mapInputs, splitGroupByTime(group, maxGroupTokens)...)
+	}
+
+	groupSummaries := g.mapGroupSummaries(ctx, chain, mapInputs, g.config.LLM.SummaryConcurrency)
+	if len(groupSummaries) == 0 {
+		return doc, fmt.Errorf("all LLM providers failed to summarize any message group")
+	}
+
+	doc.Groups = make([]FormattedGroup, len(groupSummaries))
+	for i, gs := range groupSummaries {
+		doc.Groups[i] = formattedGroupFrom(gs.group, gs.text)
+	}
+	return doc, nil
+}
+
+// formattedGroupFrom builds a FormattedGroup from group and its already-
+// computed summary text, whichever of buildSummaryDocument's two sources
+// produced it.
+func formattedGroupFrom(group MessageGroup, text string) FormattedGroup {
+	return FormattedGroup{
+		Channel:      group.Channel,
+		Topic:        group.Topic,
+		Summary:      text,
+		Participants: participantsOf(group),
+		MessageCount: len(group.Messages),
+		StartTime:    group.StartTime,
+		EndTime:      group.EndTime,
+	}
+}
+
+// participantsOf returns the distinct senders in group, in first-seen order.
+func participantsOf(group MessageGroup) []string {
+	seen := make(map[string]bool, len(group.Messages))
+	var participants []string
+	for _, msg := range group.Messages {
+		if seen[msg.Sender] {
+			continue
+		}
+		seen[msg.Sender] = true
+		participants = append(participants, msg.Sender)
+	}
+	return participants
+}
+
+// plainGroupText joins group's messages one per line via formatMessageLine
+// (formatSummary's "[15:04] <sender> message" layout, with the message
+// trimmed and replies annotated), for use as a group's Summary when no LLM
+// provider is configured.
+func plainGroupText(group MessageGroup) string {
+	var sb strings.Builder
+	for _, msg := range group.Messages {
+		sb.WriteString(formatMessageLine(msg))
+	}
+	return sb.String()
+}
+
 // filterMessages filters out noise like joins/parts, bot messages, etc.
 func (g *Generator) filterMessages(messages []storage.Message) []storage.Message {
 	var filtered []storage.Message
@@ -132,29 +430,47 @@ func (g *Generator) filterMessages(messages []storage.Message) []storage.Message
 		regexp.MustCompile(`(?i)bot$`),
 	}
 
+	// seenMsgIDs catches duplicates EID dedup misses: IRCCloud's own EID is
+	// the usual dedup key, but a replayed backlog can occasionally hand
+	// back the same IRCv3 msgid under a different EID, so check both.
+	seenMsgIDs := make(map[string]bool)
+
 	for _, msg := range messages {
+		if msgID := msg.Tags["msgid"]; msgID != "" {
+			if seenMsgIDs[msgID] {
+				metrics.MessagesFilteredTotal.WithLabelValues("duplicate_msgid").Inc()
+				continue
+			}
+			seenMsgIDs[msgID] = true
+		}
+
 		// Skip empty messages
 		if strings.TrimSpace(msg.Message) == "" {
+			metrics.MessagesFilteredTotal.WithLabelValues("empty").Inc()
 			continue
 		}
 
 		// Skip join/part/quit messages
 		if joinPartRegex.MatchString(msg.Message) {
+			metrics.MessagesFilteredTotal.WithLabelValues("join_part").Inc()
 			continue
 		}
 
 		// Skip mode changes
 		if modeChangeRegex.MatchString(msg.Message) {
+			metrics.MessagesFilteredTotal.WithLabelValues("mode_change").Inc()
 			continue
 		}
 
 		// Skip nick changes
 		if nickChangeRegex.MatchString(msg.Message) {
+			metrics.MessagesFilteredTotal.WithLabelValues("nick_change").Inc()
 			continue
 		}
 
 		// Skip topic changes (unless it's substantial)
 		if topicChangeRegex.MatchString(msg.Message) && len(msg.Message) < 100 {
+			metrics.MessagesFilteredTotal.WithLabelValues("topic_change").Inc()
 			continue
 		}
 
@@ -167,11 +483,13 @@ func (g *Generator) filterMessages(messages []storage.Message) []storage.Message
 			}
 		}
 		if isBot {
+			metrics.MessagesFilteredTotal.WithLabelValues("bot").Inc()
 			continue
 		}
 
 		// Skip very short messages (likely not meaningful)
 		if len(strings.TrimSpace(msg.Message)) < 10 {
+			metrics.MessagesFilteredTotal.WithLabelValues("too_short").Inc()
 			continue
 		}
 
@@ -215,7 +533,6 @@ func (g *Generator) groupMessages(messages []storage.Message) []MessageGroup {
 				if timeDiff > time.Hour || len(currentGroup.Messages) > 20 {
 					if len(currentGroup.Messages) > 0 {
 						currentGroup.EndTime = currentGroup.Messages[len(currentGroup.Messages)-1].Timestamp
-						currentGroup.Topic = g.extractTopic(currentGroup.Messages)
 						groups = append(groups, currentGroup)
 					}
 
@@ -236,63 +553,142 @@ func (g *Generator) groupMessages(messages []storage.Message) []MessageGroup {
 			// Add final group
 			if i == len(msgs)-1 && len(currentGroup.Messages) > 0 {
 				currentGroup.EndTime = msg.Timestamp
-				currentGroup.Topic = g.extractTopic(currentGroup.Messages)
 				groups = append(groups, currentGroup)
 			}
 		}
 	}
 
+	g.assignTopics(groups)
+
 	return groups
 }
 
-// extractTopic attempts to extract a topic from a group of messages.
-func (g *Generator) extractTopic(messages []storage.Message) string {
-	if len(messages) == 0 {
-		return "General Discussion"
+// wordCleanRE strips punctuation from a token before it's counted.
+var wordCleanRE = regexp.MustCompile(`\W`)
+
+// topicTermThreshold is the minimum tf*idf score a group's top term must
+// clear before assignTopics trusts it as a topic label. Below it, the
+// group's most frequent words are common across the whole day (meeting
+// chatter, greetings) rather than distinctive to that group, and the
+// group falls back to "General Discussion".
+const topicTermThreshold = 0.05
+
+// assignTopics labels each of groups with a topic derived from TF-IDF over
+// the whole batch: every group is a "document", and a term that's frequent
+// within one group but rare across the rest of the day's groups scores
+// higher than a term that's common everywhere. The top terms by score (at
+// most three) become the group's Topic label, and their scores are kept on
+// TopicScores so buildMapPrompt can surface them as a hint to the LLM.
+func (g *Generator) assignTopics(groups []MessageGroup) {
+	if len(groups) == 0 {
+		return
 	}
 
-	// Look for common keywords and topics
-	wordCount := make(map[string]int)
-	totalWords := 0
+	termCounts := make([]map[string]int, len(groups))
+	totalTerms := make([]int, len(groups))
+	docFreq := make(map[string]int)
 
-	for _, msg := range messages {
-		words := strings.Fields(strings.ToLower(msg.Message))
-		for _, word := range words {
-			// Clean word of punctuation
-			word = regexp.MustCompile(`\W`).ReplaceAllString(word, "")
-			if len(word) > 3 && !isStopWord(word) {
-				wordCount[word]++
-				totalWords++
+	for i, group := range groups {
+		counts := make(map[string]int)
+		for _, msg := range group.Messages {
+			for _, word := range strings.Fields(strings.ToLower(msg.Message)) {
+				word = wordCleanRE.ReplaceAllString(word, "")
+				if len(word) <= 3 || isStopWord(word) {
+					continue
+				}
+				counts[stemWord(word)]++
+				totalTerms[i]++
 			}
 		}
+		termCounts[i] = counts
+		for term := range counts {
+			docFreq[term]++
+		}
 	}
 
-	// Find most common meaningful words
-	type wordFreq struct {
-		word  string
-		count int
+	for i := range groups {
+		groups[i].Topic, groups[i].TopicScores = topicForGroup(termCounts[i], totalTerms[i], docFreq, len(groups))
 	}
+}
 
-	var frequencies []wordFreq
-	for word, count := range wordCount {
-		if count > 1 && float64(count)/float64(totalWords) > 0.05 {
-			frequencies = append(frequencies, wordFreq{word, count})
-		}
+// topicForGroup scores one group's terms by tf*idf - tf as the term's share
+// of the group's meaningful words, idf as log(N/(1+df)) over the groups
+// passed to assignTopics - and renders the top three (or fewer, if the
+// group has fewer distinct terms) into a label.
+func topicForGroup(counts map[string]int, totalTerms int, docFreq map[string]int, n int) (string, map[string]float64) {
+	if totalTerms == 0 {
+		return "General Discussion", nil
 	}
 
-	sort.Slice(frequencies, func(i, j int) bool {
-		return frequencies[i].count > frequencies[j].count
-	})
+	type termScore struct {
+		term  string
+		score float64
+	}
+
+	scores := make([]termScore, 0, len(counts))
+	for term, count := range counts {
+		tf := float64(count) / float64(totalTerms)
+		idf := math.Log(float64(n) / float64(1+docFreq[term]))
+		scores = append(scores, termScore{term, tf * idf})
+	}
 
-	if len(frequencies) > 0 {
-		topic := strings.ToUpper(string(frequencies[0].word[0])) + frequencies[0].word[1:]
-		if len(frequencies) > 1 {
-			topic += " & " + strings.ToUpper(string(frequencies[1].word[0])) + frequencies[1].word[1:]
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
 		}
-		return topic + " Discussion"
+		return scores[i].term < scores[j].term
+	})
+
+	if len(scores) == 0 || scores[0].score < topicTermThreshold {
+		return "General Discussion", nil
+	}
+
+	top := scores
+	if len(top) > 3 {
+		top = top[:3]
 	}
 
-	return "General Discussion"
+	terms := make([]string, len(top))
+	topicScores := make(map[string]float64, len(top))
+	for i, ts := range top {
+		terms[i] = ts.term
+		topicScores[ts.term] = ts.score
+	}
+
+	return formatTopic(terms), topicScores
+}
+
+// formatTopic renders the top topic terms in the same "X & Y Discussion"
+// style the old word-frequency heuristic used.
+func formatTopic(terms []string) string {
+	capped := make([]string, len(terms))
+	for i, t := range terms {
+		capped[i] = strings.ToUpper(t[:1]) + t[1:]
+	}
+
+	switch len(capped) {
+	case 1:
+		return capped[0] + " Discussion"
+	case 2:
+		return capped[0] + " & " + capped[1] + " Discussion"
+	default:
+		return strings.Join(capped[:len(capped)-1], ", ") + " & " + capped[len(capped)-1] + " Discussion"
+	}
+}
+
+// stemWord applies a lightweight Porter-style suffix strip - not the full
+// Porter algorithm, just the common endings that matter for IRC chat - so
+// "testing"/"tests"/"tested" collapse to a shared stem before
+// TF-IDF counts them. It strips at most one suffix, and only when enough
+// of the word remains for the stem to still mean something.
+func stemWord(word string) string {
+	suffixes := []string{"tion", "ing", "ed", "ly", "s"}
+	for _, suf := range suffixes {
+		if strings.HasSuffix(word, suf) && len(word)-len(suf) >= 3 {
+			return word[:len(word)-len(suf)]
+		}
+	}
+	return word
 }
 
 // isStopWord checks if a word is a common stop word.
@@ -316,44 +712,334 @@ func isStopWord(word string) bool {
 	return stopWords[word]
 }
 
-// generateLLMSummary generates a summary using the configured LLM provider.
-func (g *Generator) generateLLMSummary(ctx context.Context, groups []MessageGroup) (string, error) {
-	if g.provider == nil {
+// groupSummary is the map stage's output for one (possibly split)
+// MessageGroup: its condensed text and the tokens the provider reported
+// using to produce it.
+type groupSummary struct {
+	group  MessageGroup
+	text   string
+	tokens int
+}
+
+// generateLLMSummary generates a summary using a two-stage map-reduce
+// pipeline: each MessageGroup is summarized independently ("map", bounded
+// by SummaryConcurrency workers), then the per-group summaries are combined
+// into the daily digest by a final "reduce" call. This keeps busy days from
+// blowing past a single context window, which concatenating every message
+// into one prompt would do.
+func (g *Generator) generateLLMSummary(ctx context.Context, groups []MessageGroup, providerChain []string) (string, error) {
+	if len(providerChain) == 0 || g.provider == nil {
 		return "", fmt.Errorf("no LLM provider configured")
 	}
 
-	// Check provider health first
-	if err := g.provider.Health(ctx); err != nil {
-		return "", fmt.Errorf("LLM provider health check failed: %w", err)
+	start := time.Now()
+	defer func() { metrics.SummaryGenerationDuration.Observe(time.Since(start).Seconds()) }()
+
+	channel := dominantChannel(groups)
+	template := g.getPromptTemplate(channel)
+
+	maxGroupTokens := g.mapMaxTokens(providerChain)
+	var mapInputs []MessageGroup
+	for _, group := range groups {
+		mapInputs = append(mapInputs, splitGroupByTime(group, maxGroupTokens)...)
 	}
 
-	template := g.getPromptTemplate()
-	prompt := g.buildPrompt(template, groups)
+	groupSummaries := g.mapGroupSummaries(ctx, providerChain, mapInputs, g.config.LLM.SummaryConcurrency)
+	if len(groupSummaries) == 0 {
+		return "", fmt.Errorf("all LLM providers failed to summarize any message group")
+	}
 
-	req := &llm.GenerateRequest{
-		Model:       g.config.LLM.Model,
-		Prompt:      prompt,
-		MaxTokens:   g.config.LLM.MaxTokens,
-		Temperature: g.config.LLM.Temperature,
+	mapTokens := 0
+	for _, gs := range groupSummaries {
+		mapTokens += gs.tokens
 	}
 
-	resp, err := g.provider.Generate(ctx, req)
+	reducePrompt := g.buildReducePrompt(template, groupSummaries)
+
+	if g.plugins != nil {
+		rewritten, err := g.plugins.PreSummary(reducePrompt)
+		if err != nil {
+			log.Printf("⚠️ Plugin pre_summary hook error: %v", err)
+		} else {
+			reducePrompt = rewritten
+		}
+	}
+
+	resp, providerName, err := g.generateWithChain(ctx, providerChain, reducePrompt)
 	if err != nil {
-		return "", fmt.Errorf("LLM generation failed: %w", err)
+		return "", fmt.Errorf("reduce stage failed: %w", err)
 	}
 
-	// Add metadata
 	summary := fmt.Sprintf("# Daily IRC Summary - %s\n\n", time.Now().Format("January 2, 2006"))
-	summary += fmt.Sprintf("*Generated using %s (%s) - %d tokens*\n\n", g.provider.Name(), resp.Model, resp.TokensUsed)
+	summary += fmt.Sprintf("*Generated using %s (%s) - map: %d tokens across %d groups, reduce: %d tokens*\n\n",
+		providerName, resp.Model, mapTokens, len(groupSummaries), resp.TokensUsed)
 	summary += resp.Text
 
+	if g.plugins != nil {
+		rewritten, err := g.plugins.PostSummary(summary)
+		if err != nil {
+			log.Printf("⚠️ Plugin post_summary hook error: %v", err)
+		} else {
+			summary = rewritten
+		}
+	}
+
 	return summary, nil
 }
 
-// getPromptTemplate returns the prompt template for summary generation.
-func (g *Generator) getPromptTemplate() Template {
-	return Template{
-		SystemPrompt: `You are an intelligent IRC conversation summarizer. Your task is to create concise, informative daily summaries of IRC channel discussions.
+// generateWithChain tries each provider in chain in order through a
+// FallbackProvider, honoring each provider's circuit breaker (opened after
+// repeated failures, see llm.DefaultCircuitBreakerConfig) instead of the
+// previous approach of pinging Health() before every single call, and
+// returns the first successful generation along with the provider's
+// display name. Model/MaxTokens/Temperature are left unset on the shared
+// request so each provider falls back to its own configured defaults
+// rather than all using the first provider's settings. Per-provider error
+// and token metrics are recorded by FallbackProvider itself.
+func (g *Generator) generateWithChain(ctx context.Context, chain []string, prompt string) (*llm.GenerateResponse, string, error) {
+	fb, err := g.fallbackProviderFor(chain)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, name, err := fb.GenerateNamed(ctx, &llm.GenerateRequest{Prompt: prompt})
+	g.recordLLMResult(err)
+	if err != nil {
+		log.Printf("⚠️ LLM generation failed across the whole provider chain: %v", err)
+		return nil, "", fmt.Errorf("all LLM providers failed, last error: %w", err)
+	}
+
+	return resp, name, nil
+}
+
+// fallbackProviderFor builds a FallbackProvider for chain, reusing each
+// provider's persistent CircuitBreaker from g.breakers so breaker state
+// carries over between calls and across the different chains a per-channel
+// override may build from the same underlying providers.
+func (g *Generator) fallbackProviderFor(chain []string) (*llm.FallbackProvider, error) {
+	entries := make([]llm.FallbackEntry, 0, len(chain))
+	for _, name := range chain {
+		provider, ok := g.providers[name]
+		if !ok {
+			log.Printf("⚠️ LLM provider %q is not configured, skipping", name)
+			continue
+		}
+		entries = append(entries, llm.FallbackEntry{
+			Name:     name,
+			Provider: provider,
+			Breaker:  g.breakers[name],
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no configured providers in chain %v", chain)
+	}
+	return llm.NewFallbackProvider(entries), nil
+}
+
+// mapMaxTokens returns the token budget a single MessageGroup may consume
+// before splitGroupByTime splits it: 60% of the provider chain's configured
+// MaxTokens, leaving headroom for the map prompt's own instructions.
+func (g *Generator) mapMaxTokens(chain []string) int {
+	maxTokens := g.config.LLM.MaxTokens
+	if len(chain) > 0 {
+		if pc, ok := g.providerConfigs[chain[0]]; ok && pc.MaxTokens > 0 {
+			maxTokens = pc.MaxTokens
+		}
+	}
+	if maxTokens <= 0 {
+		maxTokens = 2048
+	}
+	return int(float64(maxTokens) * 0.6)
+}
+
+// mapGroupSummaries summarizes each MessageGroup independently, bounded by
+// at most concurrency workers at a time, and returns one groupSummary per
+// group that summarized successfully. Groups whose generation fails are
+// dropped with a logged warning rather than failing the whole run.
+func (g *Generator) mapGroupSummaries(ctx context.Context, chain []string, groups []MessageGroup, concurrency int) []groupSummary {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]*groupSummary, len(groups))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, group := range groups {
+		wg.Add(1)
+		go func(i int, group MessageGroup) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			resp, _, err := g.generateWithChain(ctx, chain, g.buildMapPrompt(group))
+			if err != nil {
+				log.Printf("⚠️ Map-stage summary failed for %s (%s - %s): %v",
+					group.Channel, group.StartTime.Format("15:04"), group.EndTime.Format("15:04"), err)
+				return
+			}
+			results[i] = &groupSummary{group: group, text: resp.Text, tokens: resp.TokensUsed}
+		}(i, group)
+	}
+	wg.Wait()
+
+	summaries := make([]groupSummary, 0, len(groups))
+	for _, r := range results {
+		if r != nil {
+			summaries = append(summaries, *r)
+		}
+	}
+	return summaries
+}
+
+// tokenEstimateChars approximates how many LLM tokens a string of this many
+// characters will consume. Real tokenization varies by provider and model;
+// this uses the common chars/4 rule of thumb, which is precise enough to
+// decide whether a group needs splitting.
+func tokenEstimateChars(chars int) int {
+	return (chars + 3) / 4
+}
+
+// formatMessageLine renders a single message the way both the map prompt
+// and (via buildPrompt) the legacy single-shot prompt expect it. A message
+// carrying a "+draft/reply" tag is annotated with the msgid it replies to,
+// so the LLM can reconstruct reply threads instead of reading the
+// transcript as one flat stream.
+func formatMessageLine(msg storage.Message) string {
+	if replyTo := msg.Tags["+draft/reply"]; replyTo != "" {
+		return fmt.Sprintf("[%s] <%s> (replying to msgid %s) %s\n", msg.Timestamp.Format("15:04"), msg.Sender, replyTo, strings.TrimSpace(msg.Message))
+	}
+	return fmt.Sprintf("[%s] <%s> %s\n", msg.Timestamp.Format("15:04"), msg.Sender, strings.TrimSpace(msg.Message))
+}
+
+// splitGroupByTime splits a MessageGroup into time-ordered sub-groups so
+// that none is estimated to exceed maxTokens. A group already under budget
+// is returned unchanged as a single-element slice.
+func splitGroupByTime(group MessageGroup, maxTokens int) []MessageGroup {
+	if maxTokens <= 0 {
+		return []MessageGroup{group}
+	}
+
+	totalChars := 0
+	for _, msg := range group.Messages {
+		totalChars += len(formatMessageLine(msg))
+	}
+	if tokenEstimateChars(totalChars) <= maxTokens {
+		return []MessageGroup{group}
+	}
+
+	var out []MessageGroup
+	var current []storage.Message
+	var currentChars int
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		out = append(out, MessageGroup{
+			Channel:   group.Channel,
+			Topic:     group.Topic,
+			Messages:  current,
+			StartTime: current[0].Timestamp,
+			EndTime:   current[len(current)-1].Timestamp,
+		})
+	}
+
+	for _, msg := range group.Messages {
+		lineChars := len(formatMessageLine(msg))
+		if len(current) > 0 && tokenEstimateChars(currentChars+lineChars) > maxTokens {
+			flush()
+			current = nil
+			currentChars = 0
+		}
+		current = append(current, msg)
+		currentChars += lineChars
+	}
+	flush()
+
+	return out
+}
+
+// mapUserPrompt asks for a compact summary of a single conversation
+// segment; it's the map stage of generateLLMSummary's map-reduce pipeline.
+const mapUserPrompt = `Summarize the key points of this IRC conversation segment in a few concise bullet points. Preserve important technical details, decisions, action items, and any unanswered questions.
+
+%s`
+
+// buildMapPrompt renders a single MessageGroup for the map stage.
+func (g *Generator) buildMapPrompt(group MessageGroup) string {
+	var messageLines strings.Builder
+	for _, msg := range group.Messages {
+		messageLines.WriteString(formatMessageLine(msg))
+	}
+
+	header := fmt.Sprintf("## %s - %s to %s\n", group.Channel, group.StartTime.Format("15:04"), group.EndTime.Format("15:04"))
+	header += topicHint(group)
+	return fmt.Sprintf(mapUserPrompt, header+messageLines.String())
+}
+
+// topicHint renders a group's TF-IDF topic terms as a prompt line the LLM
+// can use as a steer, e.g. "Topic hint: docker (0.41), kubernetes (0.30)".
+// Groups that fell back to "General Discussion" have no TopicScores and
+// get no hint line.
+func topicHint(group MessageGroup) string {
+	if len(group.TopicScores) == 0 {
+		return ""
+	}
+
+	type termScore struct {
+		term  string
+		score float64
+	}
+
+	terms := make([]termScore, 0, len(group.TopicScores))
+	for term, score := range group.TopicScores {
+		terms = append(terms, termScore{term, score})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].score != terms[j].score {
+			return terms[i].score > terms[j].score
+		}
+		return terms[i].term < terms[j].term
+	})
+
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = fmt.Sprintf("%s (%.2f)", t.term, t.score)
+	}
+	return fmt.Sprintf("Topic hint: %s\n", strings.Join(parts, ", "))
+}
+
+// reduceUserPrompt combines the map stage's per-group summaries into
+// instructions for the final daily digest.
+const reduceUserPrompt = `Below are summaries of individual IRC conversation segments, already condensed from the full message history. Combine them into one coherent daily summary.
+
+%s
+
+Please provide a well-structured summary with:
+1. An overview of the day's activity
+2. Key discussions by channel/topic
+3. Important decisions or announcements
+4. Technical discussions and solutions
+5. Outstanding questions or action items
+
+Format the summary in clear markdown with appropriate headers and structure.`
+
+// buildReducePrompt builds the reduce stage's prompt from the map stage's
+// per-group summaries.
+func (g *Generator) buildReducePrompt(template Template, summaries []groupSummary) string {
+	var sb strings.Builder
+	for _, gs := range summaries {
+		sb.WriteString(fmt.Sprintf("\n### %s (%s - %s)\n%s\n",
+			gs.group.Channel, gs.group.StartTime.Format("15:04"), gs.group.EndTime.Format("15:04"), gs.text))
+	}
+	return template.SystemPrompt + "\n\n" + fmt.Sprintf(reduceUserPrompt, sb.String())
+}
+
+// defaultSystemPrompt is used when no Prompts.SystemPrompt/SystemPromptFile
+// is configured, either globally or for the channel being summarized.
+const defaultSystemPrompt = `You are an intelligent IRC conversation summarizer. Your task is to create concise, informative daily summaries of IRC channel discussions.
 
 Guidelines:
 - Focus on key discussions, decisions, and important information
@@ -363,9 +1049,11 @@ Guidelines:
 - Use clear, readable formatting with headers and bullet points
 - Keep summaries concise but comprehensive
 - Preserve important technical details and links
-- Note any questions that were asked but not answered`,
+- Note any questions that were asked but not answered`
 
-		UserPrompt: `Please create a daily summary of the following IRC conversations. The messages are grouped by channel and topic. Focus on the most important discussions and key takeaways.
+// defaultUserPrompt wraps the rendered conversation text with instructions
+// for the overall structure of the summary.
+const defaultUserPrompt = `Please create a daily summary of the following IRC conversations. The messages are grouped by channel and topic. Focus on the most important discussions and key takeaways.
 
 IRC Conversations:
 %s
@@ -377,28 +1065,68 @@ Please provide a well-structured summary with:
 4. Technical discussions and solutions
 5. Outstanding questions or action items
 
-Format the summary in clear markdown with appropriate headers and structure.`,
+Format the summary in clear markdown with appropriate headers and structure.`
+
+// getPromptTemplate returns the prompt template for summary generation,
+// honoring any per-channel system prompt override.
+func (g *Generator) getPromptTemplate(channel string) Template {
+	return Template{
+		SystemPrompt: g.promptSetFor(channel).systemPrompt,
+		UserPrompt:   defaultUserPrompt,
+	}
+}
+
+// dominantChannel returns the single channel shared by every group, or
+// "default" when the groups span more than one channel.
+func dominantChannel(groups []MessageGroup) string {
+	channel := ""
+	for _, group := range groups {
+		if channel == "" {
+			channel = group.Channel
+		} else if channel != group.Channel {
+			return "default"
+		}
+	}
+	if channel == "" {
+		return "default"
 	}
+	return channel
 }
 
-// buildPrompt builds the complete prompt for LLM generation.
-func (g *Generator) buildPrompt(template Template, groups []MessageGroup) string {
+// buildPrompt builds the complete prompt for LLM generation, rendering each
+// group through the channel's configured summary template.
+func (g *Generator) buildPrompt(channel string, template Template, groups []MessageGroup) string {
 	var conversationText strings.Builder
 
+	tmpl := g.promptSetFor(channel).summaryTemplate
+
 	for _, group := range groups {
 		if len(group.Messages) == 0 {
 			continue
 		}
 
-		conversationText.WriteString(fmt.Sprintf("\n## %s - %s\n", group.Channel, group.Topic))
-		conversationText.WriteString(fmt.Sprintf("*Time: %s to %s*\n\n",
-			group.StartTime.Format("15:04"), group.EndTime.Format("15:04")))
-
+		var messageLines strings.Builder
 		for _, msg := range group.Messages {
-			conversationText.WriteString(fmt.Sprintf("[%s] <%s> %s\n",
-				msg.Timestamp.Format("15:04"), msg.Sender, strings.TrimSpace(msg.Message)))
+			messageLines.WriteString(formatMessageLine(msg))
 		}
-		conversationText.WriteString("\n")
+
+		data := TemplateData{
+			Channel:      group.Channel,
+			Date:         group.StartTime.Format("15:04") + " - " + group.EndTime.Format("15:04"),
+			Messages:     messageLines.String(),
+			MessageCount: len(group.Messages),
+		}
+
+		if tmpl != nil {
+			if err := tmpl.Execute(&conversationText, data); err != nil {
+				log.Printf("⚠️ Failed to render summary template for %s: %v", channel, err)
+			}
+			continue
+		}
+
+		// No compiled template available (e.g. template loading failed at
+		// startup); fall back to a minimal inline rendering.
+		conversationText.WriteString(fmt.Sprintf("\n## %s - %s\n%s\n", data.Channel, data.Date, data.Messages))
 	}
 
 	// Combine system prompt and user prompt
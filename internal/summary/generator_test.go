@@ -2,7 +2,10 @@ package summary
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -49,6 +52,114 @@ func TestNewGeneratorWithoutLLM(t *testing.T) {
 	}
 }
 
+func TestGenerateLLMSummaryFallsBackToSecondProvider(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	var gotModel string
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chat/completions" {
+			var req map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			gotModel, _ = req["model"].(string)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"model": gotModel,
+				"choices": []map[string]any{
+					{"message": map[string]string{"role": "assistant", "content": "fallback summary"}, "finish_reason": "stop"},
+				},
+			})
+			return
+		}
+		// /models, used for health checks
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]string{{"id": gotModel}}})
+	}))
+	defer healthy.Close()
+
+	cfg := &config.Config{
+		LLM: config.LLMConfig{
+			Default:   "primary",
+			Fallbacks: []string{"secondary"},
+			Providers: []config.NamedProviderConfig{
+				{Name: "primary", Kind: "ollama", BaseURL: failing.URL, Model: "llama3.2"},
+				{Name: "secondary", Kind: "openai-compatible", BaseURL: healthy.URL, Model: "local-model"},
+			},
+		},
+	}
+
+	generator := NewGenerator(cfg)
+
+	groups := []MessageGroup{
+		{Channel: "#test", StartTime: time.Now(), EndTime: time.Now(),
+			Messages: []storage.Message{{Sender: "user1", Message: "hi", Timestamp: time.Now()}}},
+	}
+
+	summary, err := generator.generateLLMSummary(context.Background(), groups, generator.providerChain)
+	if err != nil {
+		t.Fatalf("expected fallback provider to succeed, got error: %v", err)
+	}
+	if !strings.Contains(summary, "fallback summary") {
+		t.Errorf("expected summary to contain fallback provider's response, got: %s", summary)
+	}
+	if gotModel != "local-model" {
+		t.Errorf("expected fallback provider to be called with model 'local-model', got %q", gotModel)
+	}
+	if !strings.Contains(summary, "map:") || !strings.Contains(summary, "reduce:") {
+		t.Errorf("expected summary metadata to report per-stage token usage, got: %s", summary)
+	}
+}
+
+func TestSplitGroupByTimeLeavesSmallGroupUnchanged(t *testing.T) {
+	baseTime := time.Now()
+	group := MessageGroup{
+		Channel:   "#test",
+		StartTime: baseTime,
+		EndTime:   baseTime.Add(time.Minute),
+		Messages: []storage.Message{
+			{Sender: "user1", Message: "short message", Timestamp: baseTime},
+		},
+	}
+
+	split := splitGroupByTime(group, 1000)
+	if len(split) != 1 {
+		t.Fatalf("expected group under budget to stay whole, got %d sub-groups", len(split))
+	}
+	if len(split[0].Messages) != 1 {
+		t.Errorf("expected the single message to be preserved, got %d", len(split[0].Messages))
+	}
+}
+
+func TestSplitGroupByTimeSplitsOversizedGroup(t *testing.T) {
+	baseTime := time.Now()
+	var messages []storage.Message
+	for i := 0; i < 50; i++ {
+		messages = append(messages, storage.Message{
+			Sender:    "user1",
+			Message:   strings.Repeat("word ", 40),
+			Timestamp: baseTime.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	group := MessageGroup{Channel: "#test", StartTime: baseTime, EndTime: baseTime.Add(50 * time.Minute), Messages: messages}
+
+	split := splitGroupByTime(group, 100)
+	if len(split) < 2 {
+		t.Fatalf("expected an oversized group to be split into multiple sub-groups, got %d", len(split))
+	}
+
+	var total int
+	for _, sub := range split {
+		total += len(sub.Messages)
+		if sub.Channel != "#test" {
+			t.Errorf("expected sub-group to keep the original channel, got %q", sub.Channel)
+		}
+	}
+	if total != len(messages) {
+		t.Errorf("expected all %d messages to be preserved across sub-groups, got %d", len(messages), total)
+	}
+}
+
 func TestFilterMessages(t *testing.T) {
 	cfg := &config.Config{}
 	generator := NewGenerator(cfg)
@@ -85,6 +196,40 @@ func TestFilterMessages(t *testing.T) {
 	}
 }
 
+func TestFilterMessagesDropsDuplicateMsgID(t *testing.T) {
+	cfg := &config.Config{}
+	generator := NewGenerator(cfg)
+
+	messages := []storage.Message{
+		{ID: 1, Channel: "#test", Sender: "user1", Message: "Hello everyone, how are you doing?", Timestamp: time.Now(), Tags: storage.Tags{"msgid": "abc"}},
+		{ID: 2, Channel: "#test", Sender: "user1", Message: "Hello everyone, how are you doing?", Timestamp: time.Now(), Tags: storage.Tags{"msgid": "abc"}},
+		{ID: 3, Channel: "#test", Sender: "user2", Message: "A completely different message here", Timestamp: time.Now(), Tags: storage.Tags{"msgid": "def"}},
+	}
+
+	filtered := generator.filterMessages(messages)
+
+	if len(filtered) != 2 {
+		t.Fatalf("expected the duplicate msgid to be dropped, got %d messages", len(filtered))
+	}
+	if filtered[0].ID != 1 || filtered[1].ID != 3 {
+		t.Errorf("expected messages 1 and 3 to survive, got IDs %d and %d", filtered[0].ID, filtered[1].ID)
+	}
+}
+
+func TestFormatMessageLineAnnotatesReplies(t *testing.T) {
+	msg := storage.Message{
+		Sender:    "alice",
+		Message:   "sounds good",
+		Timestamp: time.Now(),
+		Tags:      storage.Tags{"+draft/reply": "abc123"},
+	}
+
+	line := formatMessageLine(msg)
+	if !strings.Contains(line, "replying to msgid abc123") {
+		t.Errorf("expected the reply annotation in the formatted line, got %q", line)
+	}
+}
+
 func TestGroupMessages(t *testing.T) {
 	cfg := &config.Config{}
 	generator := NewGenerator(cfg)
@@ -121,46 +266,71 @@ func TestGroupMessages(t *testing.T) {
 	}
 }
 
-func TestExtractTopic(t *testing.T) {
+func TestAssignTopics(t *testing.T) {
 	cfg := &config.Config{}
 	generator := NewGenerator(cfg)
 
-	tests := []struct {
-		name     string
-		messages []storage.Message
-		expected string
-	}{
-		{
-			name:     "empty messages",
-			messages: []storage.Message{},
-			expected: "General Discussion",
-		},
+	groups := []MessageGroup{
 		{
-			name: "programming discussion",
-			messages: []storage.Message{
+			Channel: "#dev",
+			Messages: []storage.Message{
 				{Message: "Let's talk about programming languages"},
 				{Message: "I love programming in Go"},
 				{Message: "Programming is fun when you solve problems"},
 			},
-			expected: "Programming Discussion",
 		},
 		{
-			name: "docker and kubernetes",
-			messages: []storage.Message{
+			Channel: "#ops",
+			Messages: []storage.Message{
 				{Message: "We need to deploy using docker containers"},
 				{Message: "Docker makes deployment easier"},
-				{Message: "Should we use kubernetes for orchestration?"},
-				{Message: "Kubernetes would help with scaling"},
+				{Message: "Should we use docker for everything?"},
 			},
-			expected: "Discussion", // Topic extraction order can vary, just check it contains Discussion
 		},
+		{
+			Channel: "#general",
+			Messages: []storage.Message{},
+		},
+	}
+
+	generator.assignTopics(groups)
+
+	if !strings.Contains(groups[0].Topic, "Program") {
+		t.Errorf("expected #dev group's topic to reflect programming, got %q", groups[0].Topic)
+	}
+	if len(groups[0].TopicScores) == 0 {
+		t.Error("expected #dev group to carry topic scores")
+	}
+
+	if !strings.Contains(groups[1].Topic, "Docker") {
+		t.Errorf("expected #ops group's topic to reflect docker, got %q", groups[1].Topic)
+	}
+
+	if groups[2].Topic != "General Discussion" {
+		t.Errorf("expected empty group to fall back to General Discussion, got %q", groups[2].Topic)
+	}
+	if len(groups[2].TopicScores) != 0 {
+		t.Error("expected empty group to carry no topic scores")
+	}
+}
+
+func TestStemWord(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{"programming", "programm"},
+		{"programmed", "programm"},
+		{"programs", "program"},
+		{"docker", "docker"},
+		{"quickly", "quick"},
+		{"ops", "ops"},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			topic := generator.extractTopic(tt.messages)
-			if !strings.Contains(topic, tt.expected) {
-				t.Errorf("Expected topic to contain '%s', got '%s'", tt.expected, topic)
+		t.Run(tt.word, func(t *testing.T) {
+			if got := stemWord(tt.word); got != tt.expected {
+				t.Errorf("stemWord(%q) = %q, want %q", tt.word, got, tt.expected)
 			}
 		})
 	}
@@ -193,7 +363,7 @@ func TestGetPromptTemplate(t *testing.T) {
 	cfg := &config.Config{}
 	generator := NewGenerator(cfg)
 
-	template := generator.getPromptTemplate()
+	template := generator.getPromptTemplate("default")
 
 	if template.SystemPrompt == "" {
 		t.Error("Expected system prompt to be non-empty")
@@ -235,14 +405,14 @@ func TestBuildPrompt(t *testing.T) {
 		},
 	}
 
-	prompt := generator.buildPrompt(template, groups)
+	prompt := generator.buildPrompt("default", template, groups)
 
 	if !strings.Contains(prompt, "You are a test summarizer.") {
 		t.Error("Expected prompt to contain system prompt")
 	}
 
-	if !strings.Contains(prompt, "#test - Test Discussion") {
-		t.Error("Expected prompt to contain channel and topic")
+	if !strings.Contains(prompt, "#test") {
+		t.Error("Expected prompt to contain channel")
 	}
 
 	if !strings.Contains(prompt, "user1") {
@@ -397,6 +567,26 @@ func (m *MockLLMProvider) Generate(ctx context.Context, req *llm.GenerateRequest
 	}, nil
 }
 
+func (m *MockLLMProvider) GenerateStream(ctx context.Context, req *llm.GenerateRequest, onChunk func(llm.StreamChunk) error) error {
+	resp, err := m.Generate(ctx, req)
+	if err != nil {
+		return err
+	}
+	return onChunk(llm.StreamChunk{Text: resp.Text, Done: true, TokensUsed: resp.TokensUsed})
+}
+
+func (m *MockLLMProvider) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	if m.shouldFail {
+		return nil, errors.New("mock LLM failure")
+	}
+	return &llm.ChatResponse{
+		Message:      llm.ChatMessage{Role: "assistant", Content: m.response},
+		TokensUsed:   100,
+		Model:        "mock-model",
+		FinishReason: "stop",
+	}, nil
+}
+
 func (m *MockLLMProvider) ListModels(ctx context.Context) ([]string, error) {
 	return []string{"mock-model"}, nil
 }
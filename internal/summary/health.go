@@ -0,0 +1,86 @@
+package summary
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// StartHealthChecks launches a background loop that polls every configured
+// provider whose circuit breaker is currently open, closing the breaker as
+// soon as one responds to Health again. Without this, a provider that
+// recovers between scheduled summary runs would stay marked open until the
+// next real GenerateDailySummary call happened to land after its cooldown -
+// for a once-a-day cron job, that can mean a full extra day degraded to a
+// lower-priority provider for no reason. Stops when ctx is done.
+func (g *Generator) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go g.runHealthChecks(ctx, interval)
+}
+
+func (g *Generator) runHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.checkProviderHealth(ctx)
+		}
+	}
+}
+
+func (g *Generator) checkProviderHealth(ctx context.Context) {
+	for name, provider := range g.providers {
+		breaker, ok := g.breakers[name]
+		if !ok || breaker.State() != "open" {
+			continue
+		}
+		if err := provider.Health(ctx); err != nil {
+			log.Printf("🩺 LLM provider %q still unhealthy: %v", name, err)
+			continue
+		}
+		log.Printf("🩺 LLM provider %q recovered, closing circuit breaker", name)
+		breaker.RecordSuccess()
+	}
+}
+
+// LastLLMError returns the error from the most recent generateWithChain
+// call, or nil if it succeeded (or none has run yet).
+func (g *Generator) LastLLMError() error {
+	g.llmMu.Lock()
+	defer g.llmMu.Unlock()
+	return g.lastLLMError
+}
+
+// LLMFailureCount returns the number of consecutive generateWithChain
+// failures since the last success.
+func (g *Generator) LLMFailureCount() int {
+	g.llmMu.Lock()
+	defer g.llmMu.Unlock()
+	return g.llmFailureCount
+}
+
+// CircuitState returns the named provider's circuit breaker state
+// ("closed", "open", "half-open"), or "" if name isn't configured.
+func (g *Generator) CircuitState(name string) string {
+	breaker, ok := g.breakers[name]
+	if !ok {
+		return ""
+	}
+	return breaker.State()
+}
+
+// recordLLMResult updates lastLLMError/llmFailureCount from the outcome of
+// a generateWithChain call.
+func (g *Generator) recordLLMResult(err error) {
+	g.llmMu.Lock()
+	defer g.llmMu.Unlock()
+	if err == nil {
+		g.lastLLMError = nil
+		g.llmFailureCount = 0
+		return
+	}
+	g.lastLLMError = err
+	g.llmFailureCount++
+}
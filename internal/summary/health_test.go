@@ -0,0 +1,126 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"irccloud-watcher/internal/llm"
+)
+
+// healthTestProvider is a minimal llm.Provider stub whose Health call
+// always succeeds or always fails, so tests can simulate a provider
+// recovering.
+type healthTestProvider struct {
+	name      string
+	healthErr error
+}
+
+func (p *healthTestProvider) Generate(ctx context.Context, req *llm.GenerateRequest) (*llm.GenerateResponse, error) {
+	return nil, errors.New("not implemented")
+}
+func (p *healthTestProvider) GenerateStream(ctx context.Context, req *llm.GenerateRequest, onChunk func(llm.StreamChunk) error) error {
+	return errors.New("not implemented")
+}
+func (p *healthTestProvider) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return nil, errors.New("not implemented")
+}
+func (p *healthTestProvider) ListModels(ctx context.Context) ([]string, error) { return nil, nil }
+func (p *healthTestProvider) Health(ctx context.Context) error                 { return p.healthErr }
+func (p *healthTestProvider) Name() string                                    { return p.name }
+func (p *healthTestProvider) Close() error                                    { return nil }
+
+func openBreaker() *llm.CircuitBreaker {
+	breaker := llm.NewCircuitBreaker(llm.DefaultCircuitBreakerConfig())
+	for i := 0; i < llm.DefaultCircuitBreakerConfig().FailureThreshold; i++ {
+		breaker.RecordFailure()
+	}
+	return breaker
+}
+
+func TestCheckProviderHealth_ClosesBreakerOnceProviderRecovers(t *testing.T) {
+	breaker := openBreaker()
+	if breaker.State() != "open" {
+		t.Fatalf("expected breaker to start open, got %q", breaker.State())
+	}
+
+	g := &Generator{
+		providers: map[string]llm.Provider{"primary": &healthTestProvider{name: "primary"}},
+		breakers:  map[string]*llm.CircuitBreaker{"primary": breaker},
+	}
+
+	g.checkProviderHealth(context.Background())
+
+	if got := breaker.State(); got != "closed" {
+		t.Errorf("expected breaker to close once Health succeeds, got %q", got)
+	}
+}
+
+func TestCheckProviderHealth_LeavesBreakerOpenWhileStillUnhealthy(t *testing.T) {
+	breaker := openBreaker()
+	g := &Generator{
+		providers: map[string]llm.Provider{"primary": &healthTestProvider{name: "primary", healthErr: errors.New("still down")}},
+		breakers:  map[string]*llm.CircuitBreaker{"primary": breaker},
+	}
+
+	g.checkProviderHealth(context.Background())
+
+	if got := breaker.State(); got != "open" {
+		t.Errorf("expected breaker to stay open while Health keeps failing, got %q", got)
+	}
+}
+
+func TestCheckProviderHealth_SkipsProvidersWhoseBreakerIsntOpen(t *testing.T) {
+	breaker := llm.NewCircuitBreaker(llm.DefaultCircuitBreakerConfig())
+	provider := &healthTestProvider{name: "primary", healthErr: errors.New("would fail if checked")}
+	g := &Generator{
+		providers: map[string]llm.Provider{"primary": provider},
+		breakers:  map[string]*llm.CircuitBreaker{"primary": breaker},
+	}
+
+	g.checkProviderHealth(context.Background())
+
+	if got := breaker.State(); got != "closed" {
+		t.Errorf("expected closed breaker to be left alone, got %q", got)
+	}
+}
+
+func TestRecordLLMResult(t *testing.T) {
+	g := &Generator{}
+
+	g.recordLLMResult(errors.New("boom"))
+	if got := g.LLMFailureCount(); got != 1 {
+		t.Errorf("expected failure count 1, got %d", got)
+	}
+	if g.LastLLMError() == nil {
+		t.Error("expected LastLLMError to be set after a failure")
+	}
+
+	g.recordLLMResult(errors.New("boom again"))
+	if got := g.LLMFailureCount(); got != 2 {
+		t.Errorf("expected failure count to accumulate to 2, got %d", got)
+	}
+
+	g.recordLLMResult(nil)
+	if got := g.LLMFailureCount(); got != 0 {
+		t.Errorf("expected a success to reset failure count, got %d", got)
+	}
+	if g.LastLLMError() != nil {
+		t.Error("expected LastLLMError to clear after a success")
+	}
+}
+
+func TestCircuitState(t *testing.T) {
+	g := &Generator{
+		breakers: map[string]*llm.CircuitBreaker{
+			"primary": llm.NewCircuitBreaker(llm.DefaultCircuitBreakerConfig()),
+		},
+	}
+
+	if got := g.CircuitState("primary"); got != "closed" {
+		t.Errorf("expected primary to report closed, got %q", got)
+	}
+	if got := g.CircuitState("unknown"); got != "" {
+		t.Errorf("expected unconfigured provider to report empty state, got %q", got)
+	}
+}
@@ -0,0 +1,91 @@
+package summary
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// defaultSummaryTemplate renders a single conversation group fed into the LLM
+// prompt when no custom template is configured. It is executed once per
+// MessageGroup and the results are concatenated.
+const defaultSummaryTemplate = `
+## {{.Channel}} ({{.MessageCount}} messages) - {{.Date}}
+
+{{.Messages}}
+`
+
+// TemplateData is the set of variables exposed to prompt templates.
+type TemplateData struct {
+	Channel      string
+	Date         string
+	Messages     string
+	MessageCount int
+}
+
+// promptSet holds the compiled system prompt and summary template for a
+// single channel (or the global default).
+type promptSet struct {
+	systemPrompt    string
+	summaryTemplate *template.Template
+}
+
+// loadPromptTemplates reads and compiles the configured system prompts and
+// summary templates once at startup so GenerateDailySummary doesn't re-parse
+// them on every run.
+func (g *Generator) loadPromptTemplates() error {
+	g.prompts = make(map[string]*promptSet)
+
+	defaultSet, err := buildPromptSet(g.config.Prompts.SystemPrompt, g.config.Prompts.SystemPromptFile, g.config.Prompts.SummaryTemplate, "default")
+	if err != nil {
+		return fmt.Errorf("could not load default prompts: %w", err)
+	}
+	g.prompts["default"] = defaultSet
+
+	for channel, override := range g.config.Prompts.Channels {
+		set, err := buildPromptSet(override.SystemPrompt, override.SystemPromptFile, override.SummaryTemplate, channel)
+		if err != nil {
+			return fmt.Errorf("could not load prompts for channel %q: %w", channel, err)
+		}
+		g.prompts[channel] = set
+	}
+
+	return nil
+}
+
+// buildPromptSet resolves the system prompt text (inline or from file) and
+// compiles the summary template, falling back to package defaults for any
+// field left empty.
+func buildPromptSet(systemPrompt, systemPromptFile, summaryTemplate, name string) (*promptSet, error) {
+	if systemPromptFile != "" {
+		data, err := os.ReadFile(systemPromptFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read system_prompt_file: %w", err)
+		}
+		systemPrompt = string(data)
+	}
+	if strings.TrimSpace(systemPrompt) == "" {
+		systemPrompt = defaultSystemPrompt
+	}
+
+	if strings.TrimSpace(summaryTemplate) == "" {
+		summaryTemplate = defaultSummaryTemplate
+	}
+
+	tmpl, err := template.New(name + "-summary").Parse(summaryTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse summary_template: %w", err)
+	}
+
+	return &promptSet{systemPrompt: systemPrompt, summaryTemplate: tmpl}, nil
+}
+
+// promptSetFor returns the compiled prompts for a channel, falling back to
+// the default set when no per-channel override is configured.
+func (g *Generator) promptSetFor(channel string) *promptSet {
+	if set, ok := g.prompts[channel]; ok {
+		return set
+	}
+	return g.prompts["default"]
+}
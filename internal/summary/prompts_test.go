@@ -0,0 +1,79 @@
+package summary
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"irccloud-watcher/internal/config"
+)
+
+func TestLoadPromptTemplatesDefaults(t *testing.T) {
+	cfg := &config.Config{}
+	generator := NewGenerator(cfg)
+
+	set := generator.promptSetFor("#anything")
+	if set.systemPrompt != defaultSystemPrompt {
+		t.Error("expected default system prompt when nothing is configured")
+	}
+	if set.summaryTemplate == nil {
+		t.Error("expected a compiled default summary template")
+	}
+}
+
+func TestLoadPromptTemplatesInlineOverride(t *testing.T) {
+	cfg := &config.Config{
+		Prompts: config.PromptsConfig{
+			SystemPrompt: "Custom global prompt",
+			Channels: map[string]config.ChannelPromptConfig{
+				"#dev": {SystemPrompt: "Custom #dev prompt"},
+			},
+		},
+	}
+	generator := NewGenerator(cfg)
+
+	if generator.promptSetFor("#general").systemPrompt != "Custom global prompt" {
+		t.Error("expected global override to apply to channels without an override")
+	}
+	if generator.promptSetFor("#dev").systemPrompt != "Custom #dev prompt" {
+		t.Error("expected channel-specific override to win over the global prompt")
+	}
+}
+
+func TestLoadPromptTemplatesSystemPromptFile(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "prompt-*.txt")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString("Prompt from file"); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	cfg := &config.Config{
+		Prompts: config.PromptsConfig{SystemPromptFile: tmpFile.Name()},
+	}
+	generator := NewGenerator(cfg)
+
+	if generator.promptSetFor("default").systemPrompt != "Prompt from file" {
+		t.Error("expected system prompt to be loaded from system_prompt_file")
+	}
+}
+
+func TestBuildPromptSetCustomSummaryTemplate(t *testing.T) {
+	set, err := buildPromptSet("", "", "{{.Channel}}: {{.MessageCount}} msgs", "test")
+	if err != nil {
+		t.Fatalf("buildPromptSet failed: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := set.summaryTemplate.Execute(&sb, TemplateData{Channel: "#dev", MessageCount: 3}); err != nil {
+		t.Fatalf("template execution failed: %v", err)
+	}
+
+	if sb.String() != "#dev: 3 msgs" {
+		t.Errorf("expected rendered template '#dev: 3 msgs', got %q", sb.String())
+	}
+}
@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// mIRC formatting control codes, as parsed by ParseFormatting.
+// RemoveIRCColors (the legacy strip-everything behavior) matches the same
+// codes with its own regex rather than these constants.
+const (
+	ircBold      = '\x02'
+	ircColor     = '\x03'
+	ircReset     = '\x0F'
+	ircUnderline = '\x1F'
+	ircItalic    = '\x1D'
+	ircStrike    = '\x1E'
+)
+
+// FormattedSegment is one run of text sharing the same mIRC formatting
+// state. FG/BG are mIRC palette indices (0-98), or -1 if unset.
+type FormattedSegment struct {
+	Text      string
+	FG, BG    int
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Strike    bool
+}
+
+// ParseFormatting tokenizes message into a stream of FormattedSegments,
+// tracking mIRC's stateful bold/italic/underline/strikethrough toggles and
+// \x03 foreground[,background] color codes. \x0F (and implicitly, the end
+// of the message) resets all formatting. A color code with no digits after
+// \x03 (or after the comma, e.g. the malformed "\x03,5") clears or leaves
+// unset whichever half it doesn't find digits for, rather than erroring.
+func ParseFormatting(message string) []FormattedSegment {
+	var segments []FormattedSegment
+	cur := FormattedSegment{FG: -1, BG: -1}
+	var buf strings.Builder
+
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		seg := cur
+		seg.Text = buf.String()
+		segments = append(segments, seg)
+		buf.Reset()
+	}
+
+	runes := []rune(message)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case ircBold:
+			flush()
+			cur.Bold = !cur.Bold
+		case ircUnderline:
+			flush()
+			cur.Underline = !cur.Underline
+		case ircItalic:
+			flush()
+			cur.Italic = !cur.Italic
+		case ircStrike:
+			flush()
+			cur.Strike = !cur.Strike
+		case ircReset:
+			flush()
+			cur = FormattedSegment{FG: -1, BG: -1}
+		case ircColor:
+			flush()
+			fg, bg, consumed := parseColorCode(runes[i+1:])
+			cur.FG, cur.BG = fg, bg
+			i += consumed
+		default:
+			buf.WriteRune(runes[i])
+		}
+	}
+	flush()
+	return segments
+}
+
+// parseColorCode parses the digits following a \x03 control code, returning
+// the foreground/background palette indices (-1 if absent) and how many
+// runes of rest were consumed. Handles the well-formed "NN", "NN,MM" and
+// bare "" (color reset) forms, as well as the malformed "," / ",MM" form
+// (no foreground digits) some clients emit.
+func parseColorCode(rest []rune) (fg, bg, consumed int) {
+	fg, bg = -1, -1
+
+	i := 0
+	for i < 2 && i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+		i++
+	}
+	if i > 0 {
+		fg, _ = strconv.Atoi(string(rest[:i]))
+	}
+
+	if i < len(rest) && rest[i] == ',' {
+		j := i + 1
+		for j < i+3 && j < len(rest) && rest[j] >= '0' && rest[j] <= '9' {
+			j++
+		}
+		if j > i+1 {
+			bg, _ = strconv.Atoi(string(rest[i+1 : j]))
+			i = j
+		}
+	}
+
+	return fg, bg, i
+}
+
+// RenderPlain concatenates segment text with all formatting discarded,
+// equivalent to RemoveIRCColors for well-formed codes. For the malformed
+// "\x03,MM" form RenderPlain drops the ",MM" while RemoveIRCColors leaves it
+// in the text.
+func RenderPlain(segments []FormattedSegment) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		b.WriteString(seg.Text)
+	}
+	return b.String()
+}
+
+// RenderANSI renders segments as a TTY-ready string, mapping mIRC palette
+// colors to the nearest xterm 256-color escape and bold/italic/underline/
+// strikethrough to their SGR codes.
+func RenderANSI(segments []FormattedSegment) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		var codes []string
+		if seg.Bold {
+			codes = append(codes, "1")
+		}
+		if seg.Italic {
+			codes = append(codes, "3")
+		}
+		if seg.Underline {
+			codes = append(codes, "4")
+		}
+		if seg.Strike {
+			codes = append(codes, "9")
+		}
+		if hex, ok := mircHex(seg.FG); ok {
+			codes = append(codes, fmt.Sprintf("38;5;%d", hexToANSI256(hex)))
+		}
+		if hex, ok := mircHex(seg.BG); ok {
+			codes = append(codes, fmt.Sprintf("48;5;%d", hexToANSI256(hex)))
+		}
+
+		if len(codes) == 0 {
+			b.WriteString(seg.Text)
+			continue
+		}
+		b.WriteString("\x1b[" + strings.Join(codes, ";") + "m")
+		b.WriteString(seg.Text)
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// RenderHTML renders segments as HTML, wrapping colored runs in a
+// `<span style="...">` and bold/italic/underline/strikethrough in the
+// matching tag. Text is HTML-escaped.
+func RenderHTML(segments []FormattedSegment) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		text := html.EscapeString(seg.Text)
+
+		var style []string
+		if hex, ok := mircHex(seg.FG); ok {
+			style = append(style, "color:"+hex)
+		}
+		if hex, ok := mircHex(seg.BG); ok {
+			style = append(style, "background-color:"+hex)
+		}
+
+		var open, closeTags string
+		if seg.Bold {
+			open += "<b>"
+			closeTags = "</b>" + closeTags
+		}
+		if seg.Italic {
+			open += "<i>"
+			closeTags = "</i>" + closeTags
+		}
+		if seg.Underline {
+			open += "<u>"
+			closeTags = "</u>" + closeTags
+		}
+		if seg.Strike {
+			open += "<s>"
+			closeTags = "</s>" + closeTags
+		}
+
+		if len(style) > 0 {
+			fmt.Fprintf(&b, `<span style="%s">%s%s%s</span>`, strings.Join(style, ";"), open, text, closeTags)
+		} else {
+			b.WriteString(open)
+			b.WriteString(text)
+			b.WriteString(closeTags)
+		}
+	}
+	return b.String()
+}
+
+// RenderMarkdown approximates segments as Markdown: bold -> **, italic ->
+// _, strikethrough -> ~~. Underline and color have no common Markdown
+// equivalent and are dropped.
+func RenderMarkdown(segments []FormattedSegment) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		text := seg.Text
+		if seg.Bold {
+			text = "**" + text + "**"
+		}
+		if seg.Italic {
+			text = "_" + text + "_"
+		}
+		if seg.Strike {
+			text = "~~" + text + "~~"
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// mircPalette is the 99-color (0-98) mIRC extended color table, indexed by
+// palette number.
+var mircPalette = [...]string{
+	"#FFFFFF", "#000000", "#00007F", "#009300", "#FF0000", "#7F0000", "#9C009C", "#FC7F00",
+	"#FFFF00", "#00FC00", "#009393", "#00FFFF", "#0000FC", "#FF00FF", "#7F7F7F", "#D2D2D2",
+	"#470000", "#472100", "#474700", "#324700", "#004700", "#00472C", "#004747", "#002747",
+	"#000047", "#2E0047", "#470047", "#47002A", "#740000", "#743A00", "#747400", "#517400",
+	"#007400", "#007449", "#007474", "#004074", "#000074", "#4B0074", "#740074", "#740045",
+	"#B50000", "#B56300", "#B5B500", "#7DB500", "#00B500", "#00B571", "#00B5B5", "#0063B5",
+	"#0000B5", "#7500B5", "#B500B5", "#B5006B", "#FF0000", "#FF8C00", "#FFFF00", "#B2FF00",
+	"#00FF00", "#00FFA0", "#00FFFF", "#008CFF", "#0000FF", "#A500FF", "#FF00FF", "#FF0098",
+	"#FF5959", "#FFB459", "#FFFF71", "#CFFF60", "#6FFF6F", "#65FFC9", "#6DFFFF", "#59B4FF",
+	"#5959FF", "#C459FF", "#FF66FF", "#FF59BC", "#FF9C9C", "#FFD39C", "#FFFF9C", "#E2FF9C",
+	"#9CFF9C", "#9CFFDB", "#9CFFFF", "#9CD3FF", "#9C9CFF", "#DC9CFF", "#FF9CFF", "#FF94D3",
+	"#000000", "#131313", "#282828", "#363636", "#4D4D4D", "#656565", "#818181", "#9F9F9F",
+	"#BCBCBC", "#E2E2E2", "#FFFFFF",
+}
+
+// mircHex looks up idx in mircPalette, reporting false for an unset (-1) or
+// out-of-range index.
+func mircHex(idx int) (string, bool) {
+	if idx < 0 || idx >= len(mircPalette) {
+		return "", false
+	}
+	return mircPalette[idx], true
+}
+
+// hexToANSI256 maps a "#RRGGBB" hex color to the nearest xterm 256-color
+// palette index, via the standard 6x6x6 color cube steps (0, 95, 135, 175,
+// 215, 255).
+func hexToANSI256(hexColor string) int {
+	r, g, b := hexRGB(hexColor)
+	toCube := func(v int) int {
+		switch {
+		case v < 48:
+			return 0
+		case v < 115:
+			return 1
+		default:
+			return (v - 35) / 40
+		}
+	}
+	return 16 + 36*toCube(r) + 6*toCube(g) + toCube(b)
+}
+
+// hexRGB parses a "#RRGGBB" string into its red/green/blue components,
+// returning zero for any component it can't parse.
+func hexRGB(hexColor string) (r, g, b int) {
+	hexColor = strings.TrimPrefix(hexColor, "#")
+	if len(hexColor) != 6 {
+		return 0, 0, 0
+	}
+	r64, _ := strconv.ParseInt(hexColor[0:2], 16, 0)
+	g64, _ := strconv.ParseInt(hexColor[2:4], 16, 0)
+	b64, _ := strconv.ParseInt(hexColor[4:6], 16, 0)
+	return int(r64), int(g64), int(b64)
+}
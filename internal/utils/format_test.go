@@ -0,0 +1,106 @@
+package utils
+
+import "testing"
+
+func TestParseFormattingPlainText(t *testing.T) {
+	segments := ParseFormatting("Hello, world!")
+	if len(segments) != 1 || segments[0].Text != "Hello, world!" {
+		t.Fatalf("expected a single unformatted segment, got %+v", segments)
+	}
+	if segments[0].FG != -1 || segments[0].BG != -1 {
+		t.Errorf("expected no color set, got FG=%d BG=%d", segments[0].FG, segments[0].BG)
+	}
+}
+
+func TestParseFormattingForegroundOnly(t *testing.T) {
+	segments := ParseFormatting("\x0307Borg")
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(segments))
+	}
+	if segments[0].FG != 7 || segments[0].BG != -1 {
+		t.Errorf("expected FG=7 BG=-1, got FG=%d BG=%d", segments[0].FG, segments[0].BG)
+	}
+	if segments[0].Text != "Borg" {
+		t.Errorf("expected text %q, got %q", "Borg", segments[0].Text)
+	}
+}
+
+func TestParseFormattingForegroundAndBackground(t *testing.T) {
+	segments := ParseFormatting("\x0307,01Text")
+	if len(segments) != 1 || segments[0].FG != 7 || segments[0].BG != 1 {
+		t.Fatalf("expected FG=7 BG=1, got %+v", segments)
+	}
+}
+
+func TestParseFormattingMalformedBackgroundOnly(t *testing.T) {
+	segments := ParseFormatting("\x03,5Text")
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(segments))
+	}
+	if segments[0].FG != -1 || segments[0].BG != 5 {
+		t.Errorf("expected FG=-1 BG=5, got FG=%d BG=%d", segments[0].FG, segments[0].BG)
+	}
+}
+
+func TestParseFormattingResetEndsColorAndStyle(t *testing.T) {
+	segments := ParseFormatting("\x02\x0307Bold colored\x0FPlain")
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d: %+v", len(segments), segments)
+	}
+	if !segments[0].Bold || segments[0].FG != 7 {
+		t.Errorf("expected first segment bold with FG=7, got %+v", segments[0])
+	}
+	if segments[1].Bold || segments[1].FG != -1 {
+		t.Errorf("expected second segment plain after reset, got %+v", segments[1])
+	}
+}
+
+func TestParseFormattingTogglesTrackIndependently(t *testing.T) {
+	segments := ParseFormatting("\x1Funderline\x1D italic\x1D\x1F plain")
+	if len(segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d: %+v", len(segments), segments)
+	}
+	if !segments[0].Underline || segments[0].Italic {
+		t.Errorf("expected segment 0 underline-only, got %+v", segments[0])
+	}
+	if !segments[1].Underline || !segments[1].Italic {
+		t.Errorf("expected segment 1 underline+italic, got %+v", segments[1])
+	}
+	if segments[2].Underline || segments[2].Italic {
+		t.Errorf("expected segment 2 plain, got %+v", segments[2])
+	}
+}
+
+func TestRenderPlainMatchesRemoveIRCColors(t *testing.T) {
+	input := "\x0307Borg\x0399 \x0314[2 rubyn00bie]\x0399"
+	if got, want := RenderPlain(ParseFormatting(input)), RemoveIRCColors(input); got != want {
+		t.Errorf("RenderPlain = %q, want %q (RemoveIRCColors)", got, want)
+	}
+}
+
+func TestRenderANSIWrapsColorAndBoldInEscapes(t *testing.T) {
+	got := RenderANSI(ParseFormatting("\x02\x0304red bold\x0F"))
+	if got == "red bold" {
+		t.Fatal("expected ANSI escapes to be present")
+	}
+	want := "\x1b[1;38;5;196mred bold\x1b[0m"
+	if got != want {
+		t.Errorf("RenderANSI = %q, want %q", got, want)
+	}
+}
+
+func TestRenderHTMLEscapesAndWrapsSpan(t *testing.T) {
+	got := RenderHTML(ParseFormatting("\x0304<b>"))
+	want := `<span style="color:#FF0000">&lt;b&gt;</span>`
+	if got != want {
+		t.Errorf("RenderHTML = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdownApproximatesBoldAndItalic(t *testing.T) {
+	got := RenderMarkdown(ParseFormatting("\x02bold\x02 \x1Ditalic\x1D"))
+	want := "**bold** _italic_"
+	if got != want {
+		t.Errorf("RenderMarkdown = %q, want %q", got, want)
+	}
+}